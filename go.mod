@@ -13,8 +13,10 @@ require (
 	github.com/gorilla/sessions v1.2.1
 	github.com/kr/text v0.2.0 // indirect
 	github.com/lib/pq v1.9.0
+	github.com/microcosm-cc/bluemonday v1.0.4
 	github.com/sirupsen/logrus v1.7.0
 	github.com/stretchr/testify v1.7.0
+	github.com/yuin/goldmark v1.2.1
 	golang.org/x/crypto v0.0.0-20201221181555-eec23a3978ad
 	golang.org/x/sys v0.0.0-20210113181707-4bcb84eeeb78 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
@@ -0,0 +1,210 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/mail"
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/gorilla/mux"
+)
+
+var (
+	errMailUnavailable   = errors.New("email is not configured on the server")
+	errInvalidResetToken = errors.New("invalid or expired reset token")
+)
+
+func (s *server) handleAccountPassword() http.HandlerFunc {
+	type request struct {
+		CurrentPassword string `json:"current_password"`
+		Password        string `json:"password"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if !u.ComparePassword(req.CurrentPassword) {
+			s.error(w, r, http.StatusForbidden, errIncorrectPassword)
+			return
+		}
+
+		u.Password = req.Password
+		err := s.store.User().UpdatePassword(u)
+		u.Sanitize()
+		if err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		// the session of the request stays, other logins have to use
+		// the new password
+		session, err := s.sessionStore.Get(r, sessionName)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		current, _ := strconv.Atoi(session.ID)
+		if err := s.endLogins(u.ID, current); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// handlePasswordResetsCreate sends the reset token to the email, the
+// response is the same whether the user exists or not and whether the
+// email is skipped because the last one was sent too recently
+func (s *server) handlePasswordResetsCreate() http.HandlerFunc {
+	type request struct {
+		Email string `json:"email"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.mailer == nil {
+			s.error(w, r, http.StatusNotImplemented, errMailUnavailable)
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u, err := s.store.User().FindByEmail(req.Email)
+		if err == store.ErrRecordNotFound {
+			s.respond(w, r, http.StatusAccepted, nil)
+			return
+		}
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		latest, err := s.store.PasswordResets().FindLatest(u.ID)
+		if err != nil && err != store.ErrRecordNotFound {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		interval := time.Duration(s.config.PasswordResetResendSeconds) * time.Second
+		if latest != nil && time.Since(latest.CreatedAt) < interval {
+			s.respond(w, r, http.StatusAccepted, nil)
+			return
+		}
+
+		ttl := time.Duration(s.config.PasswordResetMinutes) * time.Minute
+		p := &model.PasswordReset{
+			UserID:    u.ID,
+			ExpiresAt: time.Now().Add(ttl),
+		}
+		if err := s.store.PasswordResets().Create(p); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if err := s.mailer.Send(&mail.Message{
+			To:      u.Email,
+			Subject: "Password reset",
+			Body: fmt.Sprintf(
+				"To set a new password, send it with POST %s/password-resets/%s within %d minutes.\n\n"+
+					"If you didn't request the reset, ignore this email.",
+				s.config.BaseURL,
+				p.Token,
+				s.config.PasswordResetMinutes,
+			),
+		}); err != nil {
+			s.logger.Warnf("sending password reset to user %d: %v", u.ID, err)
+		}
+
+		s.respond(w, r, http.StatusAccepted, nil)
+	}
+}
+
+func (s *server) handlePasswordResetsComplete() http.HandlerFunc {
+	type request struct {
+		Password string `json:"password"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		p, err := s.store.PasswordResets().FindByToken(model.HashToken(mux.Vars(r)["token"]))
+		if err != nil {
+			if err == store.ErrRecordNotFound {
+				s.error(w, r, http.StatusNotFound, errInvalidResetToken)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		u, err := s.store.User().Find(p.UserID)
+		if err != nil {
+			if err == store.ErrRecordNotFound {
+				s.error(w, r, http.StatusNotFound, errInvalidResetToken)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		// an invalid password doesn't spend the token
+		u.Password = req.Password
+		if err := u.ValidatePassword(); err != nil {
+			u.Sanitize()
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		// the token is spent together with the password update
+		err = s.store.PasswordResets().Use(p.ID, u, time.Now())
+		u.Sanitize()
+		if err != nil {
+			if err == store.ErrRecordNotFound {
+				s.error(w, r, http.StatusNotFound, errInvalidResetToken)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if err := s.endLogins(u.ID, 0); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// endLogins deletes sessions of the user except the session with the id,
+// revokes refresh tokens, deletes personal access tokens, which could be
+// created by whoever knew the old password, and drops pending password resets
+func (s *server) endLogins(userID int, exceptSession int) error {
+	if _, err := s.store.Sessions().DeleteByUser(userID, exceptSession); err != nil {
+		return err
+	}
+	if err := s.store.RefreshTokens().RevokeByUser(userID, time.Now()); err != nil {
+		return err
+	}
+	if err := s.store.APITokens().DeleteByUser(userID); err != nil {
+		return err
+	}
+	return s.store.PasswordResets().DeleteByUser(userID)
+}
@@ -0,0 +1,198 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/mail"
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/sessionstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func whoamiCode(s *server, cookie *http.Cookie) int {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/private/whoami", nil)
+	req.AddCookie(cookie)
+	s.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func whoamiBearerCode(s *server, token string) int {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/private/whoami", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	s.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestServer_HandleAccountPassword(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	password := u.Password
+	store.User().Create(u)
+
+	s := testServer(t, store, memstore.New(), sessionstore.New(store.Sessions(), time.Hour, []byte("secret")), NewConfig())
+	current := loginCookie(t, s, u)
+	other := loginCookie(t, s, u)
+	tok := &model.APIToken{UserID: u.ID, Name: "backup", Scopes: []string{model.ScopeAccount}}
+	store.APITokens().Create(tok)
+	assert.Equal(t, http.StatusOK, whoamiBearerCode(s, tok.Token))
+
+	testCases := []struct {
+		name         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:         "invalid payload",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "incorrect current password",
+			payload: map[string]string{
+				"current_password": "incorrect",
+				"password":         "new password",
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "short password",
+			payload: map[string]string{
+				"current_password": password,
+				"password":         "short",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "valid",
+			payload: map[string]string{
+				"current_password": password,
+				"password":         "new password",
+			},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req := httptest.NewRequest(http.MethodPost, "/account/password", b)
+			req.AddCookie(current)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	assert.Equal(t, http.StatusOK, whoamiCode(s, current))
+	assert.Equal(t, http.StatusUnauthorized, whoamiCode(s, other))
+	assert.Equal(t, http.StatusUnauthorized, whoamiBearerCode(s, tok.Token))
+
+	rec := postJSON(s, "/sessions", map[string]string{"email": u.Email, "password": password})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = postJSON(s, "/sessions", map[string]string{"email": u.Email, "password": "new password"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestServer_HandlePasswordResets(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+
+	s := testServer(t, store, memstore.New(), sessionstore.New(store.Sessions(), time.Hour, []byte("secret")), NewConfig())
+	mailer := mail.NewMemory()
+	s.mailer = mailer
+	cookie := loginCookie(t, s, u)
+	// a token created by whoever took over the account
+	tok := &model.APIToken{UserID: u.ID, Name: "backdoor", Scopes: []string{model.ScopeAccount}}
+	store.APITokens().Create(tok)
+	assert.Equal(t, http.StatusOK, whoamiBearerCode(s, tok.Token))
+
+	rec := postJSON(s, "/password-resets", map[string]string{"email": "unknown@example.org"})
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Len(t, mailer.Messages(), 0)
+
+	rec = postJSON(s, "/password-resets", map[string]string{"email": u.Email})
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	messages := mailer.Messages()
+	if !assert.Len(t, messages, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, u.Email, messages[0].To)
+
+	// another request right away is accepted without sending
+	rec = postJSON(s, "/password-resets", map[string]string{"email": u.Email})
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Len(t, mailer.Messages(), 1)
+
+	m := regexp.MustCompile(`/password-resets/(\S+) `).FindStringSubmatch(messages[0].Body)
+	if !assert.Len(t, m, 2) {
+		t.FailNow()
+	}
+	token := m[1]
+
+	testCases := []struct {
+		name         string
+		token        string
+		password     string
+		expectedCode int
+	}{
+		{
+			name:         "unknown token",
+			token:        "unknown",
+			password:     "new password",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "short password",
+			token:        token,
+			password:     "short",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "valid",
+			token:        token,
+			password:     "new password",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "used token",
+			token:        token,
+			password:     "another password",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := postJSON(s, "/password-resets/"+tc.token, map[string]string{"password": tc.password})
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, whoamiCode(s, cookie))
+	assert.Equal(t, http.StatusUnauthorized, whoamiBearerCode(s, tok.Token))
+	rec = postJSON(s, "/sessions", map[string]string{"email": u.Email, "password": "new password"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestServer_HandlePasswordResetsUnavailable(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore([]byte("secret")), NewConfig())
+	rec := postJSON(s, "/password-resets", map[string]string{"email": u.Email})
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
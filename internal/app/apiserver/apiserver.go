@@ -3,9 +3,11 @@ package apiserver
 import (
 	"database/sql"
 	"net/http"
+	"time"
 
+	"github.com/KapitanD/http-api-server/internal/app/store/fsstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/sessionstore"
 	"github.com/KapitanD/http-api-server/internal/app/store/sqlstore"
-	"github.com/gorilla/sessions"
 )
 
 // Start ...
@@ -17,8 +19,32 @@ func Start(config *Config) error {
 
 	defer db.Close()
 	store := sqlstore.New(db)
-	sessionStore := sessions.NewCookieStore([]byte(config.SessionKey))
-	srv := newServer(store, sessionStore)
+	blobStore, err := fsstore.New(config.AttachmentDir)
+	if err != nil {
+		return err
+	}
+	sessionStore := sessionstore.New(
+		store.Sessions(),
+		time.Duration(config.SessionMaxAgeDays)*24*time.Hour,
+		[]byte(config.SessionKey),
+	)
+	srv, err := newServer(store, blobStore, sessionStore, config)
+	if err != nil {
+		return err
+	}
+
+	if config.TrashRetentionDays > 0 {
+		stop := srv.startTrashPurger(time.Duration(config.TrashRetentionDays) * 24 * time.Hour)
+		defer stop()
+	}
+
+	stopImportSweeper := srv.startImportSweeper()
+	defer stopImportSweeper()
+
+	if config.ReminderPollSeconds > 0 {
+		stop := srv.startReminderScheduler(time.Duration(config.ReminderPollSeconds) * time.Second)
+		defer stop()
+	}
 
 	srv.logger.Info("starting server")
 
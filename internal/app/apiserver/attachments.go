@@ -0,0 +1,224 @@
+package apiserver
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/google/uuid"
+)
+
+// attachmentField is the multipart form field with the uploaded file
+const attachmentField = "file"
+
+// sniffLen is the number of bytes used to detect the content type
+const sniffLen = 512
+
+// multipartOverhead is allowed size of multipart request besides the file
+const multipartOverhead = 1 << 20
+
+var (
+	errAttachmentRequired    = errors.New("file is required")
+	errAttachmentTooLarge    = errors.New("file is too large")
+	errAttachmentTypeAllowed = errors.New("file type is not allowed")
+)
+
+// handleAttachmentsCreate stores the file of the multipart request, content
+// type is detected from the file content rather than taken from the request
+func (s *server) handleAttachmentsCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.authorizeNote(w, r, model.RoleEditor)
+		if !ok {
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, s.config.AttachmentMaxSize+multipartOverhead)
+		mr, err := r.MultipartReader()
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				s.error(w, r, http.StatusBadRequest, errAttachmentRequired)
+				return
+			}
+			if err != nil {
+				s.error(w, r, http.StatusBadRequest, err)
+				return
+			}
+			if part.FormName() != attachmentField || part.FileName() == "" {
+				continue
+			}
+
+			head := make([]byte, sniffLen)
+			size, err := io.ReadFull(part, head)
+			if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+				s.error(w, r, http.StatusBadRequest, err)
+				return
+			}
+			head = head[:size]
+
+			a := &model.Attachment{
+				NoteID:      n.ID,
+				Filename:    filepath.Base(part.FileName()),
+				ContentType: contentType(http.DetectContentType(head)),
+				StorageKey:  uuid.New().String(),
+			}
+			if !s.attachmentTypeAllowed(a.ContentType) {
+				s.error(w, r, http.StatusUnsupportedMediaType, errAttachmentTypeAllowed)
+				return
+			}
+
+			s.storeAttachment(w, r, a, io.MultiReader(bytes.NewReader(head), part))
+			return
+		}
+	}
+}
+
+func (s *server) handleAttachmentsGetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.authorizeNote(w, r, model.RoleViewer)
+		if !ok {
+			return
+		}
+
+		al, err := s.store.Attachments().FindByNote(n.ID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, al)
+	}
+}
+
+// handleAttachmentsGet serves the file content, http.ServeContent handles
+// Range and conditional requests
+func (s *server) handleAttachmentsGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.authorizeNote(w, r, model.RoleViewer)
+		if !ok {
+			return
+		}
+		a, ok := s.noteAttachment(w, r, n)
+		if !ok {
+			return
+		}
+
+		b, err := s.blobStore.Open(a.StorageKey)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		defer b.Close()
+
+		w.Header().Set("Content-Type", a.ContentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": a.Filename}))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		http.ServeContent(w, r, a.Filename, a.CreatedAt, b)
+	}
+}
+
+func (s *server) handleAttachmentsDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.authorizeNote(w, r, model.RoleEditor)
+		if !ok {
+			return
+		}
+		a, ok := s.noteAttachment(w, r, n)
+		if !ok {
+			return
+		}
+
+		if err := s.store.Attachments().Delete(a.ID); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.deleteBlobs([]*model.Attachment{a})
+
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// storeAttachment puts the content to the blob store and saves the attachment,
+// the blob is removed when the content is too large or saving fails
+func (s *server) storeAttachment(w http.ResponseWriter, r *http.Request, a *model.Attachment, content io.Reader) {
+	size, err := s.blobStore.Put(a.StorageKey, io.LimitReader(content, s.config.AttachmentMaxSize+1))
+	if err != nil {
+		s.error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if size > s.config.AttachmentMaxSize {
+		s.deleteBlobs([]*model.Attachment{a})
+		s.error(w, r, http.StatusRequestEntityTooLarge, errAttachmentTooLarge)
+		return
+	}
+
+	a.Size = size
+	if err := s.store.Attachments().Create(a); err != nil {
+		s.deleteBlobs([]*model.Attachment{a})
+		s.error(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	s.respond(w, r, http.StatusCreated, a)
+}
+
+// noteAttachment returns the attachment with aid from the request path
+// when it belongs to the note
+func (s *server) noteAttachment(w http.ResponseWriter, r *http.Request, n *model.Note) (*model.Attachment, bool) {
+	aid, err := pathInt(r, "aid")
+	if err != nil {
+		s.error(w, r, http.StatusBadRequest, errIncorrectRequest)
+		return nil, false
+	}
+
+	a, err := s.store.Attachments().Find(aid)
+	if err != nil {
+		if err == store.ErrRecordNotFound {
+			s.error(w, r, http.StatusNotFound, err)
+			return nil, false
+		}
+		s.error(w, r, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	if a.NoteID != n.ID {
+		s.error(w, r, http.StatusNotFound, store.ErrRecordNotFound)
+		return nil, false
+	}
+	return a, true
+}
+
+func (s *server) attachmentTypeAllowed(contentType string) bool {
+	for _, t := range s.config.AttachmentTypes {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteBlobs removes contents of the attachments, failures are only logged
+// since the attachments are already gone
+func (s *server) deleteBlobs(al []*model.Attachment) {
+	for _, a := range al {
+		if err := s.blobStore.Delete(a.StorageKey); err != nil && err != store.ErrRecordNotFound {
+			s.logger.Errorf("deleting blob %s: %v", a.StorageKey, err)
+		}
+	}
+}
+
+// contentType strips parameters from the media type
+func contentType(t string) string {
+	mt, _, err := mime.ParseMediaType(t)
+	if err != nil {
+		return t
+	}
+	return mt
+}
@@ -0,0 +1,200 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+var testPNG = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 56)...)
+
+func multipartBody(t *testing.T, field, filename string, content []byte) (*bytes.Buffer, string) {
+	t.Helper()
+
+	b := &bytes.Buffer{}
+	mw := multipart.NewWriter(b)
+	fw, err := mw.CreateFormFile(field, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write(content)
+	mw.Close()
+	return b, mw.FormDataContentType()
+}
+
+func TestServer_HandleAttachmentsCreate(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	viewer := model.TestUser(t)
+	viewer.Email = "viewer@example.org"
+	store.User().Create(viewer)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	store.Shares().Save(&model.NoteShare{NoteID: n.ID, UserID: viewer.ID, Role: model.RoleViewer})
+
+	secretKey := []byte("secret")
+	config := NewConfig()
+	config.AttachmentMaxSize = 100
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), config)
+	testCases := []struct {
+		name         string
+		user         *model.User
+		field        string
+		filename     string
+		content      []byte
+		expectedCode int
+	}{
+		{
+			name:         "valid",
+			user:         u,
+			field:        attachmentField,
+			filename:     "image.png",
+			content:      testPNG,
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "not allowed type",
+			user:         u,
+			field:        attachmentField,
+			filename:     "image.png",
+			content:      []byte("plain text"),
+			expectedCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:         "too large",
+			user:         u,
+			field:        attachmentField,
+			filename:     "image.png",
+			content:      append(testPNG, make([]byte, 100)...),
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:         "without file",
+			user:         u,
+			field:        "other",
+			filename:     "image.png",
+			content:      testPNG,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "viewer",
+			user:         viewer,
+			field:        attachmentField,
+			filename:     "image.png",
+			content:      testPNG,
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, contentType := multipartBody(t, tc.field, tc.filename, tc.content)
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/notes/%d/attachments", n.ID), b)
+			req.Header.Set("Content-Type", contentType)
+			setSessionCookie(t, req, secretKey, tc.user)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusCreated {
+				a := &model.Attachment{}
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(a))
+				assert.Equal(t, "image/png", a.ContentType)
+				assert.Equal(t, int64(len(tc.content)), a.Size)
+			}
+		})
+	}
+
+	al, _ := store.Attachments().FindByNote(n.ID)
+	assert.Len(t, al, 1)
+}
+
+func TestServer_HandleAttachmentsGet(t *testing.T) {
+	store := teststore.New()
+	blobStore := memstore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	a := model.TestAttachment(t)
+	a.NoteID = n.ID
+	a.Size = int64(len(testPNG))
+	blobStore.Put(a.StorageKey, bytes.NewReader(testPNG))
+	store.Attachments().Create(a)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, blobStore, sessions.NewCookieStore(secretKey), NewConfig())
+	path := fmt.Sprintf("/notes/%d/attachments/%d", n.ID, a.ID)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	assert.Equal(t, testPNG, rec.Body.Bytes())
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Range", "bytes=1-3")
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, fmt.Sprintf("bytes 1-3/%d", len(testPNG)), rec.Header().Get("Content-Range"))
+	assert.Equal(t, "PNG", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/notes/%d/attachments/%d", n.ID, a.ID+1), nil)
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServer_HandleAttachmentsDelete(t *testing.T) {
+	store := teststore.New()
+	blobStore := memstore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+
+	al := []*model.Attachment{}
+	for _, key := range []string{"first", "second"} {
+		a := model.TestAttachment(t)
+		a.NoteID = n.ID
+		a.StorageKey = key
+		blobStore.Put(a.StorageKey, bytes.NewReader(testPNG))
+		store.Attachments().Create(a)
+		al = append(al, a)
+	}
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, blobStore, sessions.NewCookieStore(secretKey), NewConfig())
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/notes/%d/attachments/%d", n.ID, al[0].ID), nil)
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	_, err := blobStore.Open(al[0].StorageKey)
+	assert.Error(t, err)
+	_, err = blobStore.Open(al[1].StorageKey)
+	assert.NoError(t, err)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/notes/%d?permanent=true", n.ID), nil)
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	_, err = blobStore.Open(al[1].StorageKey)
+	assert.Error(t, err)
+}
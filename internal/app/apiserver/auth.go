@@ -0,0 +1,221 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/jwt"
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+var (
+	errTokensUnavailable   = errors.New("token authentication is not configured on the server")
+	errInvalidRefreshToken = errors.New("invalid refresh token")
+)
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// newTokenSigner returns nil when no signing keys are configured
+func newTokenSigner(config *Config) (*jwt.Signer, error) {
+	if len(config.JWTKeys) == 0 {
+		return nil, nil
+	}
+
+	keys := make(map[string][]byte, len(config.JWTKeys))
+	for id, key := range config.JWTKeys {
+		keys[id] = []byte(key)
+	}
+	return jwt.NewSigner(keys, config.JWTKeyID)
+}
+
+func (s *server) handleAuthToken() http.HandlerFunc {
+	type request struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.tokenSigner == nil {
+			s.error(w, r, http.StatusNotImplemented, errTokensUnavailable)
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u, err := s.store.User().FindByEmail(req.Email)
+		if err != nil || !u.ComparePassword(req.Password) {
+			s.error(w, r, http.StatusUnauthorized, errIncorrectEmailOrPassword)
+			return
+		}
+
+		res, err := s.issueTokens(u.ID, "")
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, res)
+	}
+}
+
+func (s *server) handleAuthRefresh() http.HandlerFunc {
+	type request struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.tokenSigner == nil {
+			s.error(w, r, http.StatusNotImplemented, errTokensUnavailable)
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		rt, err := s.store.RefreshTokens().FindByToken(model.HashToken(req.RefreshToken))
+		if err != nil {
+			if err == store.ErrRecordNotFound {
+				s.error(w, r, http.StatusUnauthorized, errInvalidRefreshToken)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		now := time.Now()
+		if rt.Expired(now) {
+			s.error(w, r, http.StatusUnauthorized, errInvalidRefreshToken)
+			return
+		}
+
+		// A used token may be stolen: either the thief or the client already
+		// got new tokens, so all tokens of the login are revoked
+		if err := s.store.RefreshTokens().Use(rt.ID, now); err != nil {
+			if err != store.ErrRecordNotFound {
+				s.error(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			if rt.RevokedAt == nil {
+				s.logger.Warnf("reuse of refresh token %d of user %d", rt.ID, rt.UserID)
+			}
+			if err := s.store.RefreshTokens().RevokeFamily(rt.Family, now); err != nil {
+				s.error(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			s.error(w, r, http.StatusUnauthorized, errInvalidRefreshToken)
+			return
+		}
+
+		res, err := s.issueTokens(rt.UserID, rt.Family)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, res)
+	}
+}
+
+// handleAuthRevoke revokes the refresh token with all tokens of its login,
+// unknown tokens aren't an error as in RFC 7009
+func (s *server) handleAuthRevoke() http.HandlerFunc {
+	type request struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		rt, err := s.store.RefreshTokens().FindByToken(model.HashToken(req.RefreshToken))
+		if err != nil {
+			if err == store.ErrRecordNotFound {
+				s.respond(w, r, http.StatusOK, nil)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if err := s.store.RefreshTokens().RevokeFamily(rt.Family, time.Now()); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// issueTokens signs an access token and creates a refresh token in the
+// family, an empty family starts a new one
+func (s *server) issueTokens(userID int, family string) (*tokenResponse, error) {
+	now := time.Now()
+	ttl := time.Duration(s.config.AccessTokenMinutes) * time.Minute
+	access, err := s.tokenSigner.Sign(&jwt.Claims{
+		Subject:   strconv.Itoa(userID),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rt := &model.RefreshToken{
+		UserID:    userID,
+		Family:    family,
+		ExpiresAt: now.Add(time.Duration(s.config.RefreshTokenDays) * 24 * time.Hour),
+	}
+	if err := s.store.RefreshTokens().Create(rt); err != nil {
+		return nil, err
+	}
+
+	return &tokenResponse{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(ttl.Seconds()),
+		RefreshToken: rt.Token,
+	}, nil
+}
+
+// verifyAccessToken returns the id of the user of the token
+func (s *server) verifyAccessToken(token string) (int, error) {
+	if s.tokenSigner == nil {
+		return 0, errTokensUnavailable
+	}
+
+	c, err := s.tokenSigner.Verify(token, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := strconv.Atoi(c.Subject)
+	if err != nil {
+		return 0, jwt.ErrInvalidToken
+	}
+	return id, nil
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	if len(h) < 7 || !strings.EqualFold(h[:7], "Bearer ") {
+		return "", false
+	}
+	return strings.TrimSpace(h[7:]), true
+}
@@ -0,0 +1,231 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/jwt"
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTokenConfig() *Config {
+	config := NewConfig()
+	config.JWTKeys = map[string]string{"k1": "old", "k2": "new"}
+	config.JWTKeyID = "k2"
+	return config
+}
+
+func postJSON(s *server, path string, payload interface{}) *httptest.ResponseRecorder {
+	b := &bytes.Buffer{}
+	json.NewEncoder(b).Encode(payload)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, b))
+	return rec
+}
+
+func issueTestTokens(t *testing.T, s *server, u *model.User) *tokenResponse {
+	t.Helper()
+
+	rec := postJSON(s, "/auth/token", map[string]string{
+		"email":    u.Email,
+		"password": u.Password,
+	})
+	if !assert.Equal(t, http.StatusOK, rec.Code) {
+		t.FailNow()
+	}
+	res := &tokenResponse{}
+	json.NewDecoder(rec.Body).Decode(res)
+	return res
+}
+
+func TestNewServer_InvalidTokenConfig(t *testing.T) {
+	config := testTokenConfig()
+	config.JWTKeyID = "unknown"
+
+	_, err := newServer(teststore.New(), memstore.New(), sessions.NewCookieStore([]byte("secret")), config)
+	assert.EqualError(t, err, jwt.ErrUnknownKey.Error())
+}
+
+func TestServer_HandleAuthToken(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore([]byte("secret")), testTokenConfig())
+	testCases := []struct {
+		name         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			payload: map[string]string{
+				"email":    u.Email,
+				"password": u.Password,
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid payload",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid password",
+			payload: map[string]string{
+				"email":    u.Email,
+				"password": "invalid",
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := postJSON(s, "/auth/token", tc.payload)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	res := issueTestTokens(t, s, u)
+	assert.Equal(t, "Bearer", res.TokenType)
+	assert.Equal(t, 15*60, res.ExpiresIn)
+	assert.NotEmpty(t, res.RefreshToken)
+
+	disabled := testServer(t, store, memstore.New(), sessions.NewCookieStore([]byte("secret")), NewConfig())
+	rec := postJSON(disabled, "/auth/token", map[string]string{
+		"email":    u.Email,
+		"password": u.Password,
+	})
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
+
+func TestServer_AuthenticateBearer(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+
+	config := testTokenConfig()
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore([]byte("secret")), config)
+	res := issueTestTokens(t, s, u)
+
+	sign := func(keyID string, key string, expiresAt time.Time) string {
+		signer, err := jwt.NewSigner(map[string][]byte{keyID: []byte(key)}, keyID)
+		assert.NoError(t, err)
+		token, err := signer.Sign(&jwt.Claims{Subject: "1", ExpiresAt: expiresAt.Unix()})
+		assert.NoError(t, err)
+		return token
+	}
+
+	testCases := []struct {
+		name         string
+		header       string
+		expectedCode int
+	}{
+		{
+			name:         "valid",
+			header:       "Bearer " + res.AccessToken,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "lower case scheme",
+			header:       "bearer " + res.AccessToken,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "previous key",
+			header:       "Bearer " + sign("k1", "old", time.Now().Add(time.Minute)),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "unknown key",
+			header:       "Bearer " + sign("k3", "new", time.Now().Add(time.Minute)),
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "expired",
+			header:       "Bearer " + sign("k2", "new", time.Now().Add(-time.Minute)),
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "refresh token",
+			header:       "Bearer " + res.RefreshToken,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "no token",
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/private/whoami", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleAuthRefresh(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore([]byte("secret")), testTokenConfig())
+	first := issueTestTokens(t, s, u)
+	other := issueTestTokens(t, s, u)
+
+	refresh := func(token string) (*tokenResponse, int) {
+		rec := postJSON(s, "/auth/refresh", map[string]string{"refresh_token": token})
+		res := &tokenResponse{}
+		json.NewDecoder(rec.Body).Decode(res)
+		return res, rec.Code
+	}
+
+	second, code := refresh(first.RefreshToken)
+	assert.Equal(t, http.StatusOK, code)
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+	assert.NotEmpty(t, second.AccessToken)
+
+	_, code = refresh("unknown")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// reuse of the rotated token revokes the tokens issued after it
+	_, code = refresh(first.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	_, code = refresh(second.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	_, code = refresh(other.RefreshToken)
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestServer_HandleAuthRevoke(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore([]byte("secret")), testTokenConfig())
+	res := issueTestTokens(t, s, u)
+
+	rec := postJSON(s, "/auth/revoke", map[string]string{"refresh_token": res.RefreshToken})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = postJSON(s, "/auth/revoke", map[string]string{"refresh_token": "unknown"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = postJSON(s, "/auth/refresh", map[string]string{"refresh_token": res.RefreshToken})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,62 @@
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// noteRole returns the role of the user for the note,
+// the role is empty when the note is not shared with the user
+func (s *server) noteRole(n *model.Note, u *model.User) (string, error) {
+	if n.AuthorID == u.ID {
+		return model.RoleOwner, nil
+	}
+
+	sh, err := s.store.Shares().Find(n.ID, u.ID)
+	if err != nil {
+		if err == store.ErrRecordNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	return sh.Role, nil
+}
+
+// authorizeNote returns the note with id from the request path when the user
+// has the required role for it. Otherwise it writes an error response and
+// returns false, notes inaccessible to the user are reported as not found
+func (s *server) authorizeNote(w http.ResponseWriter, r *http.Request, required string) (*model.Note, bool) {
+	id, err := pathInt(r, "id")
+	if err != nil {
+		s.error(w, r, http.StatusBadRequest, errIncorrectRequest)
+		return nil, false
+	}
+	u := r.Context().Value(ctxKeyUser).(*model.User)
+
+	n, err := s.store.Notes().FindByID(id)
+	if err != nil {
+		if err == store.ErrRecordNotFound {
+			s.error(w, r, http.StatusNotFound, err)
+			return nil, false
+		}
+		s.error(w, r, http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	role, err := s.noteRole(n, u)
+	if err != nil {
+		s.error(w, r, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	if role == "" {
+		s.error(w, r, http.StatusNotFound, store.ErrRecordNotFound)
+		return nil, false
+	}
+	if !model.RoleAllows(role, required) {
+		s.error(w, r, http.StatusForbidden, errForbidden)
+		return nil, false
+	}
+	return n, true
+}
@@ -0,0 +1,165 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+const (
+	bulkModeAtomic     = "atomic"
+	bulkModeBestEffort = "best_effort"
+	bulkMaxItems       = 1000
+
+	bulkStatusOK      = "ok"
+	bulkStatusFailed  = "failed"
+	bulkStatusSkipped = "skipped"
+)
+
+var (
+	errIncorrectBulkMode = errors.New("mode must be atomic or best_effort")
+	errNoBulkOperations  = errors.New("operations must not be empty")
+	errTooManyBulkItems  = errors.New("too many notes in bulk operations")
+)
+
+// bulkRoles are roles required for bulk operations, the same as for
+// the single note endpoints
+var bulkRoles = map[string]string{
+	store.BulkDelete: model.RoleOwner,
+	store.BulkUpdate: model.RoleEditor,
+	store.BulkTag:    model.RoleEditor,
+	store.BulkMove:   model.RoleOwner,
+}
+
+type bulkResult struct {
+	Op     string `json:"op"`
+	NoteID int    `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (s *server) handleNotesBulk() http.HandlerFunc {
+	type operation struct {
+		Op         string   `json:"op"`
+		IDs        []int    `json:"ids"`
+		Header     string   `json:"header"`
+		Body       string   `json:"body"`
+		Add        []string `json:"add"`
+		Remove     []string `json:"remove"`
+		NotebookID *int     `json:"notebook_id"`
+	}
+	type request struct {
+		Mode       string       `json:"mode"`
+		Operations []*operation `json:"operations"`
+	}
+	type response struct {
+		Results []*bulkResult `json:"results"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if req.Mode == "" {
+			req.Mode = bulkModeAtomic
+		}
+		if req.Mode != bulkModeAtomic && req.Mode != bulkModeBestEffort {
+			s.error(w, r, http.StatusBadRequest, errIncorrectBulkMode)
+			return
+		}
+		if len(req.Operations) == 0 {
+			s.error(w, r, http.StatusBadRequest, errNoBulkOperations)
+			return
+		}
+
+		items := []*store.BulkItem{}
+		for _, op := range req.Operations {
+			if _, ok := bulkRoles[op.Op]; !ok {
+				s.error(w, r, http.StatusBadRequest, store.ErrUnknownBulkOp)
+				return
+			}
+			var notebookErr error
+			if op.Op == store.BulkMove {
+				notebookErr = s.checkNotebook(u, op.NotebookID)
+				if notebookErr != nil && notebookErr != errNotebookNotFound {
+					s.error(w, r, http.StatusInternalServerError, notebookErr)
+					return
+				}
+			}
+			for _, id := range op.IDs {
+				items = append(items, &store.BulkItem{
+					Op:         op.Op,
+					NoteID:     id,
+					UserID:     u.ID,
+					Role:       bulkRoles[op.Op],
+					Header:     op.Header,
+					Body:       op.Body,
+					AddTags:    op.Add,
+					RemoveTags: op.Remove,
+					NotebookID: op.NotebookID,
+					Err:        notebookErr,
+				})
+			}
+			if len(items) > bulkMaxItems {
+				s.error(w, r, http.StatusBadRequest, errTooManyBulkItems)
+				return
+			}
+		}
+
+		// roles are checked by Bulk while the notes are locked,
+		// so a share revoked meanwhile can't let an item through
+		atomic := req.Mode == bulkModeAtomic
+		allowed := []*store.BulkItem{}
+		for _, it := range items {
+			if it.Err == nil {
+				allowed = append(allowed, it)
+			}
+		}
+
+		failed := len(allowed) < len(items) && atomic
+		if !failed {
+			if err := s.store.Notes().Bulk(allowed, atomic); err != nil {
+				if err != store.ErrBulkFailed {
+					s.error(w, r, http.StatusInternalServerError, err)
+					return
+				}
+				failed = true
+			}
+		}
+
+		res := &response{Results: make([]*bulkResult, len(items))}
+		for i, it := range items {
+			br := &bulkResult{Op: it.Op, NoteID: it.NoteID, Status: bulkStatusOK}
+			switch {
+			case it.Err != nil:
+				br.Status = bulkStatusFailed
+				br.Error = it.Err.Error()
+			case failed:
+				br.Status = bulkStatusSkipped
+			}
+			res.Results[i] = br
+		}
+
+		if failed {
+			s.respond(w, r, http.StatusUnprocessableEntity, res)
+			return
+		}
+
+		for _, it := range items {
+			if it.Err == nil && it.Op == store.BulkUpdate {
+				if err := s.pruneRevisions(it.NoteID); err != nil {
+					s.error(w, r, http.StatusInternalServerError, err)
+					return
+				}
+			}
+		}
+		s.respond(w, r, http.StatusOK, res)
+	}
+}
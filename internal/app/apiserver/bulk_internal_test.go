@@ -0,0 +1,135 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_HandleNotesBulk(t *testing.T) {
+	secretKey := []byte("secret")
+	testCases := []struct {
+		name             string
+		mode             string
+		operations       func(own, shared, foreign *model.Note) []map[string]interface{}
+		expectedCode     int
+		expectedStatuses []string
+		expectedHeader   string
+	}{
+		{
+			name: "atomic",
+			mode: "atomic",
+			operations: func(own, shared, foreign *model.Note) []map[string]interface{} {
+				return []map[string]interface{}{
+					{"op": "update", "ids": []int{own.ID, shared.ID}, "header": "bulk"},
+					{"op": "tag", "ids": []int{own.ID}, "add": []string{"work"}},
+				}
+			},
+			expectedCode:     http.StatusOK,
+			expectedStatuses: []string{"ok", "ok", "ok"},
+			expectedHeader:   "bulk",
+		},
+		{
+			name: "atomic with forbidden item",
+			mode: "atomic",
+			operations: func(own, shared, foreign *model.Note) []map[string]interface{} {
+				return []map[string]interface{}{
+					{"op": "update", "ids": []int{own.ID}, "header": "bulk"},
+					{"op": "delete", "ids": []int{shared.ID}},
+				}
+			},
+			expectedCode:     http.StatusUnprocessableEntity,
+			expectedStatuses: []string{"skipped", "failed"},
+			expectedHeader:   "header",
+		},
+		{
+			name: "best effort",
+			mode: "best_effort",
+			operations: func(own, shared, foreign *model.Note) []map[string]interface{} {
+				return []map[string]interface{}{
+					{"op": "update", "ids": []int{own.ID, foreign.ID}, "header": "bulk"},
+					{"op": "move", "ids": []int{own.ID}, "notebook_id": 100},
+				}
+			},
+			expectedCode:     http.StatusOK,
+			expectedStatuses: []string{"ok", "failed", "failed"},
+			expectedHeader:   "bulk",
+		},
+		{
+			name: "invalid mode",
+			mode: "sometimes",
+			operations: func(own, shared, foreign *model.Note) []map[string]interface{} {
+				return []map[string]interface{}{
+					{"op": "delete", "ids": []int{own.ID}},
+				}
+			},
+			expectedCode:   http.StatusBadRequest,
+			expectedHeader: "header",
+		},
+		{
+			name: "unknown operation",
+			mode: "atomic",
+			operations: func(own, shared, foreign *model.Note) []map[string]interface{} {
+				return []map[string]interface{}{
+					{"op": "copy", "ids": []int{own.ID}},
+				}
+			},
+			expectedCode:   http.StatusBadRequest,
+			expectedHeader: "header",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := teststore.New()
+			u := model.TestUser(t)
+			store.User().Create(u)
+			other := model.TestUser(t)
+			other.Email = "other@example.org"
+			store.User().Create(other)
+			own := model.TestNote(t)
+			store.Notes().Create(own, u)
+			shared := model.TestNote(t)
+			store.Notes().Create(shared, other)
+			store.Shares().Save(&model.NoteShare{NoteID: shared.ID, UserID: u.ID, Role: model.RoleEditor})
+			foreign := model.TestNote(t)
+			store.Notes().Create(foreign, other)
+			s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(map[string]interface{}{
+				"mode":       tc.mode,
+				"operations": tc.operations(own, shared, foreign),
+			})
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/notes/bulk", b)
+			setSessionCookie(t, req, secretKey, u)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+
+			if tc.expectedStatuses != nil {
+				res := &struct {
+					Results []*bulkResult `json:"results"`
+				}{}
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(res))
+				statuses := []string{}
+				for _, br := range res.Results {
+					statuses = append(statuses, br.Status)
+				}
+				assert.Equal(t, tc.expectedStatuses, statuses)
+			}
+
+			n, err := store.Notes().FindByID(own.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedHeader, n.Header)
+		})
+	}
+}
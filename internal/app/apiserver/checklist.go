@@ -0,0 +1,175 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+func (s *server) handleChecklistGetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.authorizeNote(w, r, model.RoleViewer)
+		if !ok {
+			return
+		}
+
+		il, err := s.store.Checklist().FindByNote(n.ID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, il)
+	}
+}
+
+func (s *server) handleChecklistCreate() http.HandlerFunc {
+	type request struct {
+		Text string `json:"text"`
+		Done bool   `json:"done"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		n, ok := s.authorizeNote(w, r, model.RoleEditor)
+		if !ok {
+			return
+		}
+
+		i := &model.ChecklistItem{
+			NoteID: n.ID,
+			Text:   req.Text,
+			Done:   req.Done,
+		}
+		if err := s.store.Checklist().Create(i); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusCreated, i)
+	}
+}
+
+// handleChecklistUpdate changes text and done of the item
+// and moves it when the position is given
+func (s *server) handleChecklistUpdate() http.HandlerFunc {
+	type request struct {
+		Text     *string `json:"text"`
+		Done     *bool   `json:"done"`
+		Position *int    `json:"position"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		i, ok := s.noteItem(w, r)
+		if !ok {
+			return
+		}
+
+		if req.Text != nil || req.Done != nil {
+			if req.Text != nil {
+				i.Text = *req.Text
+			}
+			if req.Done != nil {
+				i.Done = *req.Done
+			}
+			if err := s.store.Checklist().Update(i); err != nil {
+				s.error(w, r, http.StatusUnprocessableEntity, err)
+				return
+			}
+		}
+		if req.Position != nil {
+			if err := s.store.Checklist().Move(i.ID, *req.Position); err != nil {
+				s.error(w, r, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		s.respondItem(w, r, i.ID)
+	}
+}
+
+func (s *server) handleChecklistToggle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		i, ok := s.noteItem(w, r)
+		if !ok {
+			return
+		}
+
+		i.Done = !i.Done
+		if err := s.store.Checklist().Update(i); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, i)
+	}
+}
+
+func (s *server) handleChecklistDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		i, ok := s.noteItem(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.store.Checklist().Delete(i.ID); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// noteItem returns the item with iid from the request path when it belongs
+// to the note with id which the user can edit. Otherwise it writes an error
+// response and returns false
+func (s *server) noteItem(w http.ResponseWriter, r *http.Request) (*model.ChecklistItem, bool) {
+	n, ok := s.authorizeNote(w, r, model.RoleEditor)
+	if !ok {
+		return nil, false
+	}
+
+	id, err := pathInt(r, "iid")
+	if err != nil {
+		s.error(w, r, http.StatusBadRequest, errIncorrectRequest)
+		return nil, false
+	}
+
+	i, err := s.store.Checklist().Find(id)
+	if err != nil {
+		if err == store.ErrRecordNotFound {
+			s.error(w, r, http.StatusNotFound, err)
+			return nil, false
+		}
+		s.error(w, r, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	if i.NoteID != n.ID {
+		s.error(w, r, http.StatusNotFound, store.ErrRecordNotFound)
+		return nil, false
+	}
+	return i, true
+}
+
+// respondItem responds with the current state of the item
+func (s *server) respondItem(w http.ResponseWriter, r *http.Request, id int) {
+	i, err := s.store.Checklist().Find(id)
+	if err != nil {
+		s.error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	s.respond(w, r, http.StatusOK, i)
+}
@@ -0,0 +1,168 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_HandleChecklist(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	viewer := model.TestUser(t)
+	viewer.Email = "viewer@example.org"
+	store.User().Create(viewer)
+	editor := model.TestUser(t)
+	editor.Email = "editor@example.org"
+	store.User().Create(editor)
+	stranger := model.TestUser(t)
+	stranger.Email = "stranger@example.org"
+	store.User().Create(stranger)
+
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	store.Shares().Save(&model.NoteShare{NoteID: n.ID, UserID: viewer.ID, Role: model.RoleViewer})
+	store.Shares().Save(&model.NoteShare{NoteID: n.ID, UserID: editor.ID, Role: model.RoleEditor})
+	other := model.TestNote(t)
+	store.Notes().Create(other, u)
+
+	first := &model.ChecklistItem{NoteID: n.ID, Text: "first"}
+	store.Checklist().Create(first)
+	second := &model.ChecklistItem{NoteID: n.ID, Text: "second"}
+	store.Checklist().Create(second)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name         string
+		user         *model.User
+		method       string
+		path         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:         "create",
+			user:         editor,
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/notes/%d/items", n.ID),
+			payload:      map[string]interface{}{"text": "third"},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "create invalid",
+			user:         u,
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/notes/%d/items", n.ID),
+			payload:      map[string]interface{}{"text": ""},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "create by viewer",
+			user:         viewer,
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/notes/%d/items", n.ID),
+			payload:      map[string]interface{}{"text": "fourth"},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "get all by viewer",
+			user:         viewer,
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/notes/%d/items", n.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "get all by stranger",
+			user:         stranger,
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/notes/%d/items", n.ID),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "move",
+			user:         editor,
+			method:       http.MethodPatch,
+			path:         fmt.Sprintf("/notes/%d/items/%d", n.ID, second.ID),
+			payload:      map[string]interface{}{"position": 1},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "mark done",
+			user:         u,
+			method:       http.MethodPatch,
+			path:         fmt.Sprintf("/notes/%d/items/%d", n.ID, first.ID),
+			payload:      map[string]interface{}{"done": true},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "toggle",
+			user:         editor,
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/notes/%d/items/%d/toggle", n.ID, second.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "update item of other note",
+			user:         u,
+			method:       http.MethodPatch,
+			path:         fmt.Sprintf("/notes/%d/items/%d", other.ID, first.ID),
+			payload:      map[string]interface{}{"done": false},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "delete by viewer",
+			user:         viewer,
+			method:       http.MethodDelete,
+			path:         fmt.Sprintf("/notes/%d/items/%d", n.ID, first.ID),
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			if tc.payload != nil {
+				json.NewEncoder(b).Encode(tc.payload)
+			}
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, b)
+			setSessionCookie(t, req, secretKey, tc.user)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	il, err := store.Checklist().FindByNote(n.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, il, 3) {
+		assert.Equal(t, []string{"second", "first", "third"}, []string{il[0].Text, il[1].Text, il[2].Text})
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/notes/", nil)
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	page := &struct {
+		Notes []*model.Note `json:"notes"`
+	}{}
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(page))
+	for _, pn := range page.Notes {
+		if pn.ID == n.ID && assert.NotNil(t, pn.Completion) {
+			assert.Equal(t, 66, *pn.Completion)
+		}
+		if pn.ID == other.ID {
+			assert.Nil(t, pn.Completion)
+		}
+	}
+}
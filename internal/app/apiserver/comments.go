@@ -0,0 +1,217 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/notifier"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+var errIncorrectParent = errors.New("parent comment must belong to the note")
+
+func (s *server) handleCommentsGetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.authorizeNote(w, r, model.RoleViewer)
+		if !ok {
+			return
+		}
+
+		cl, err := s.store.Comments().FindByNote(n.ID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, cl)
+	}
+}
+
+// handleCommentsCreate adds the comment or the reply to the comment
+// with parent_id, anyone who can view the note can comment it
+func (s *server) handleCommentsCreate() http.HandlerFunc {
+	type request struct {
+		Body     string `json:"body"`
+		ParentID *int   `json:"parent_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		n, ok := s.authorizeNote(w, r, model.RoleViewer)
+		if !ok {
+			return
+		}
+
+		if req.ParentID != nil {
+			p, err := s.store.Comments().Find(*req.ParentID)
+			if err != nil && err != store.ErrRecordNotFound {
+				s.error(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			if err != nil || p.NoteID != n.ID {
+				s.error(w, r, http.StatusUnprocessableEntity, errIncorrectParent)
+				return
+			}
+		}
+
+		c := &model.Comment{
+			NoteID:      n.ID,
+			AuthorID:    u.ID,
+			AuthorEmail: u.Email,
+			ParentID:    req.ParentID,
+			Body:        req.Body,
+		}
+		if err := s.store.Comments().Create(c); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.notifyMentions(n, c, u, nil)
+		s.respond(w, r, http.StatusCreated, c)
+	}
+}
+
+// handleCommentsUpdate changes the body of the comment, only its author
+// can do it. Users mentioned for the first time are notified
+func (s *server) handleCommentsUpdate() http.HandlerFunc {
+	type request struct {
+		Body string `json:"body"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		n, c, ok := s.noteComment(w, r)
+		if !ok {
+			return
+		}
+		if c.AuthorID != u.ID {
+			s.error(w, r, http.StatusForbidden, errForbidden)
+			return
+		}
+
+		previous := c.Mentions()
+		c.Body = req.Body
+		if err := s.store.Comments().Update(c); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.notifyMentions(n, c, u, previous)
+		s.respond(w, r, http.StatusOK, c)
+	}
+}
+
+// handleCommentsDelete deletes the comment with replies to it,
+// the author of the comment or the owner of the note can do it
+func (s *server) handleCommentsDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		n, c, ok := s.noteComment(w, r)
+		if !ok {
+			return
+		}
+		if c.AuthorID != u.ID && n.AuthorID != u.ID {
+			s.error(w, r, http.StatusForbidden, errForbidden)
+			return
+		}
+
+		if err := s.store.Comments().Delete(c.ID); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// noteComment returns the note with id from the request path which the
+// user can view and its comment with cid. Otherwise it writes an error
+// response and returns false
+func (s *server) noteComment(w http.ResponseWriter, r *http.Request) (*model.Note, *model.Comment, bool) {
+	n, ok := s.authorizeNote(w, r, model.RoleViewer)
+	if !ok {
+		return nil, nil, false
+	}
+
+	id, err := pathInt(r, "cid")
+	if err != nil {
+		s.error(w, r, http.StatusBadRequest, errIncorrectRequest)
+		return nil, nil, false
+	}
+
+	c, err := s.store.Comments().Find(id)
+	if err != nil {
+		if err == store.ErrRecordNotFound {
+			s.error(w, r, http.StatusNotFound, err)
+			return nil, nil, false
+		}
+		s.error(w, r, http.StatusInternalServerError, err)
+		return nil, nil, false
+	}
+	if c.NoteID != n.ID {
+		s.error(w, r, http.StatusNotFound, store.ErrRecordNotFound)
+		return nil, nil, false
+	}
+	return n, c, true
+}
+
+// notifyMentions notifies users mentioned in the comment except those in
+// previous. Users who can't view the note are not notified, so the
+// notification doesn't reveal the note to them
+func (s *server) notifyMentions(n *model.Note, c *model.Comment, author *model.User, previous []string) {
+	skip := map[string]bool{strings.ToLower(author.Email): true}
+	for _, email := range previous {
+		skip[email] = true
+	}
+
+	for _, email := range c.Mentions() {
+		if skip[email] {
+			continue
+		}
+
+		u, err := s.store.User().FindByEmail(email)
+		if err != nil {
+			if err != store.ErrRecordNotFound {
+				s.logger.Warnf("mention of %s: %v", email, err)
+			}
+			continue
+		}
+		role, err := s.noteRole(n, u)
+		if err != nil {
+			s.logger.Warnf("mention of %s: %v", email, err)
+			continue
+		}
+		if role == "" {
+			continue
+		}
+
+		if err := s.notifiers[model.ChannelInApp].Notify(&notifier.Message{
+			Kind:   model.NotificationMention,
+			UserID: u.ID,
+			Email:  u.Email,
+			NoteID: n.ID,
+			Header: n.Header,
+			Text:   author.Email + " mentioned you in " + n.Header,
+			At:     c.UpdatedAt,
+		}); err != nil {
+			s.logger.Warnf("mention of %s: %v", email, err)
+		}
+	}
+}
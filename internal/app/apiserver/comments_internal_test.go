@@ -0,0 +1,179 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_HandleComments(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	viewer := model.TestUser(t)
+	viewer.Email = "viewer@example.org"
+	store.User().Create(viewer)
+	editor := model.TestUser(t)
+	editor.Email = "editor@example.org"
+	store.User().Create(editor)
+	stranger := model.TestUser(t)
+	stranger.Email = "stranger@example.org"
+	store.User().Create(stranger)
+
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	store.Shares().Save(&model.NoteShare{NoteID: n.ID, UserID: viewer.ID, Role: model.RoleViewer})
+	store.Shares().Save(&model.NoteShare{NoteID: n.ID, UserID: editor.ID, Role: model.RoleEditor})
+	other := model.TestNote(t)
+	store.Notes().Create(other, u)
+
+	ownerComment := &model.Comment{NoteID: n.ID, AuthorID: u.ID, Body: "first"}
+	store.Comments().Create(ownerComment)
+	viewerComment := &model.Comment{NoteID: n.ID, AuthorID: viewer.ID, Body: "second"}
+	store.Comments().Create(viewerComment)
+	otherComment := &model.Comment{NoteID: other.ID, AuthorID: u.ID, Body: "other"}
+	store.Comments().Create(otherComment)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name         string
+		user         *model.User
+		method       string
+		path         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:   "create with mentions",
+			user:   viewer,
+			method: http.MethodPost,
+			path:   fmt.Sprintf("/notes/%d/comments", n.ID),
+			payload: map[string]interface{}{
+				"body": "@editor@example.org @stranger@example.org please check",
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "reply",
+			user:   editor,
+			method: http.MethodPost,
+			path:   fmt.Sprintf("/notes/%d/comments", n.ID),
+			payload: map[string]interface{}{
+				"body":      "done",
+				"parent_id": viewerComment.ID,
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "reply to comment of other note",
+			user:   editor,
+			method: http.MethodPost,
+			path:   fmt.Sprintf("/notes/%d/comments", n.ID),
+			payload: map[string]interface{}{
+				"body":      "done",
+				"parent_id": otherComment.ID,
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "create invalid",
+			user:         viewer,
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/notes/%d/comments", n.ID),
+			payload:      map[string]interface{}{"body": ""},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "create by stranger",
+			user:         stranger,
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/notes/%d/comments", n.ID),
+			payload:      map[string]interface{}{"body": "hi"},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "get all",
+			user:         viewer,
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/notes/%d/comments", n.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "update own",
+			user:         viewer,
+			method:       http.MethodPatch,
+			path:         fmt.Sprintf("/notes/%d/comments/%d", n.ID, viewerComment.ID),
+			payload:      map[string]interface{}{"body": "changed"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "update by owner of note",
+			user:         u,
+			method:       http.MethodPatch,
+			path:         fmt.Sprintf("/notes/%d/comments/%d", n.ID, viewerComment.ID),
+			payload:      map[string]interface{}{"body": "moderated"},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "update comment of other note",
+			user:         u,
+			method:       http.MethodPatch,
+			path:         fmt.Sprintf("/notes/%d/comments/%d", n.ID, otherComment.ID),
+			payload:      map[string]interface{}{"body": "changed"},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "delete by editor",
+			user:         editor,
+			method:       http.MethodDelete,
+			path:         fmt.Sprintf("/notes/%d/comments/%d", n.ID, ownerComment.ID),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "delete by owner of note",
+			user:         u,
+			method:       http.MethodDelete,
+			path:         fmt.Sprintf("/notes/%d/comments/%d", n.ID, viewerComment.ID),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			if tc.payload != nil {
+				json.NewEncoder(b).Encode(tc.payload)
+			}
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, b)
+			setSessionCookie(t, req, secretKey, tc.user)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	// the reply is deleted with the comment
+	cl, err := store.Comments().FindByNote(n.ID)
+	assert.NoError(t, err)
+	assert.Len(t, cl, 2)
+
+	nl, err := store.Notifications().FindByUser(editor, true)
+	assert.NoError(t, err)
+	if assert.Len(t, nl, 1) {
+		assert.Equal(t, model.NotificationMention, nl[0].Kind)
+		assert.Equal(t, "viewer@example.org mentioned you in header", nl[0].Message)
+	}
+	nl, err = store.Notifications().FindByUser(stranger, true)
+	assert.NoError(t, err)
+	assert.Empty(t, nl)
+}
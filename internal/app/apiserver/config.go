@@ -6,12 +6,100 @@ type Config struct {
 	LogLevel    string `toml:"log_level"`
 	DatabaseURL string `toml:"database_url"`
 	SessionKey  string `toml:"session_key"`
+	// SessionMaxAgeDays is how long a session lasts after the login
+	SessionMaxAgeDays int `toml:"session_max_age_days"`
+	// JWTKeys are keys signing access tokens by their ids, bearer
+	// authentication is disabled when it's empty. After a rotation
+	// the previous key is kept until its tokens expire
+	JWTKeys map[string]string `toml:"jwt_keys"`
+	// JWTKeyID is the id of the key signing new access tokens
+	JWTKeyID string `toml:"jwt_key_id"`
+	// AccessTokenMinutes is the lifetime of access tokens
+	AccessTokenMinutes int `toml:"access_token_minutes"`
+	// RefreshTokenDays is the lifetime of refresh tokens
+	RefreshTokenDays int `toml:"refresh_token_days"`
+	// RevisionLimit is the number of latest revisions kept for each note,
+	// zero keeps all of them
+	RevisionLimit int `toml:"revision_limit"`
+	// TrashRetentionDays is how long deleted notes are kept in trash,
+	// zero disables purging
+	TrashRetentionDays int `toml:"trash_retention_days"`
+	// StrictConcurrency requires If-Match header on note updates
+	StrictConcurrency bool `toml:"strict_concurrency"`
+	// AttachmentDir is where attachment files are stored
+	AttachmentDir string `toml:"attachment_dir"`
+	// AttachmentMaxSize is the maximum size of an attachment in bytes
+	AttachmentMaxSize int64 `toml:"attachment_max_size"`
+	// AttachmentTypes are allowed MIME types of attachments
+	AttachmentTypes []string `toml:"attachment_types"`
+	// RenderCacheSize is the number of notes with rendered HTML kept in memory
+	RenderCacheSize int `toml:"render_cache_size"`
+	// ImportMaxSize is the maximum size of an imported file in bytes
+	ImportMaxSize int64 `toml:"import_max_size"`
+	// ReminderPollSeconds is how often due reminders are fired,
+	// zero disables the scheduler
+	ReminderPollSeconds int `toml:"reminder_poll_seconds"`
+	// WebhookAllowPrivate lets webhooks reach loopback and private
+	// addresses, only for servers where users are trusted
+	WebhookAllowPrivate bool `toml:"webhook_allow_private"`
+	// SMTPAddr is host:port of the SMTP server, emails are written
+	// to MailFile when it's empty
+	SMTPAddr     string `toml:"smtp_addr"`
+	SMTPUsername string `toml:"smtp_username"`
+	SMTPPassword string `toml:"smtp_password"`
+	// MailFrom is the sender address of emails
+	MailFrom string `toml:"mail_from"`
+	// MailFile is where emails are appended for development, emails
+	// are unavailable when both it and SMTPAddr are empty
+	MailFile string `toml:"mail_file"`
+	// BaseURL is the address of the server in links sent by email
+	BaseURL string `toml:"base_url"`
+	// PasswordResetMinutes is how long a password reset token is valid
+	PasswordResetMinutes int `toml:"password_reset_minutes"`
+	// PasswordResetResendSeconds is the minimum interval between
+	// password reset emails to the user
+	PasswordResetResendSeconds int `toml:"password_reset_resend_seconds"`
+	// VerificationHours is how long an email verification link is valid
+	VerificationHours int `toml:"verification_hours"`
+	// VerificationResendSeconds is the minimum interval between
+	// verification emails to the user
+	VerificationResendSeconds int `toml:"verification_resend_seconds"`
+	// UnverifiedNoteLimit is how many notes a user with unverified email
+	// can have, zero is unlimited
+	UnverifiedNoteLimit int `toml:"unverified_note_limit"`
+	// UnverifiedCanShare allows users with unverified email to share
+	// notes and create public links
+	UnverifiedCanShare bool `toml:"unverified_can_share"`
 }
 
 // NewConfig ...
 func NewConfig() *Config {
 	return &Config{
-		BindAddr: ":8080",
-		LogLevel: "debug",
+		BindAddr:                   ":8080",
+		LogLevel:                   "debug",
+		SessionMaxAgeDays:          30,
+		AccessTokenMinutes:         15,
+		RefreshTokenDays:           30,
+		RevisionLimit:              50,
+		TrashRetentionDays:         30,
+		AttachmentDir:              "attachments",
+		AttachmentMaxSize:          10 << 20,
+		RenderCacheSize:            1000,
+		ImportMaxSize:              100 << 20,
+		ReminderPollSeconds:        30,
+		MailFrom:                   "notes@localhost",
+		BaseURL:                    "http://localhost:8080",
+		PasswordResetMinutes:       60,
+		PasswordResetResendSeconds: 60,
+		VerificationHours:          48,
+		VerificationResendSeconds:  60,
+		UnverifiedCanShare:         true,
+		AttachmentTypes: []string{
+			"image/png",
+			"image/jpeg",
+			"image/gif",
+			"image/webp",
+			"application/pdf",
+		},
 	}
 }
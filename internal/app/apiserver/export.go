@@ -0,0 +1,108 @@
+package apiserver
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/notefile"
+)
+
+const (
+	exportFormatZip    = "zip"
+	exportFormatJSON   = "json"
+	exportFormatNDJSON = "ndjson"
+)
+
+var errIncorrectExportFormat = errors.New("format must be zip, json or ndjson")
+
+// exporters write notes of the user to the response body
+var exporters = map[string]struct {
+	contentType string
+	fileName    string
+	export      func(io.Writer, func(func(*model.Note) error) error) error
+}{
+	exportFormatZip:    {"application/zip", "notes.zip", exportZip},
+	exportFormatJSON:   {"application/json", "notes.json", exportJSON},
+	exportFormatNDJSON: {"application/x-ndjson", "notes.ndjson", exportNDJSON},
+}
+
+func (s *server) handleExport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = exportFormatZip
+		}
+		e, ok := exporters[format]
+		if !ok {
+			s.error(w, r, http.StatusBadRequest, errIncorrectExportFormat)
+			return
+		}
+
+		w.Header().Set("Content-Type", e.contentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="`+e.fileName+`"`)
+		w.WriteHeader(http.StatusOK)
+
+		// the status is already sent, so failures can only be logged
+		// and the client gets a truncated file
+		if err := e.export(w, func(fn func(*model.Note) error) error {
+			return s.store.Notes().EachByUser(u, fn)
+		}); err != nil {
+			s.logger.WithField("request_id", r.Context().Value(ctxKeyRequestID)).Errorf("export failed: %v", err)
+		}
+	}
+}
+
+func exportZip(w io.Writer, each func(func(*model.Note) error) error) error {
+	zw := zip.NewWriter(w)
+	if err := each(func(n *model.Note) error {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     notefile.FileName(n),
+			Method:   zip.Deflate,
+			Modified: n.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		return notefile.Write(fw, n)
+	}); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func exportJSON(w io.Writer, each func(func(*model.Note) error) error) error {
+	sep := "["
+	if err := each(func(n *model.Note) error {
+		b, err := json.Marshal(n)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, sep); err != nil {
+			return err
+		}
+		sep = ","
+		_, err = w.Write(b)
+		return err
+	}); err != nil {
+		return err
+	}
+	if sep == "[" {
+		_, err := io.WriteString(w, "[]")
+		return err
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+func exportNDJSON(w io.Writer, each func(func(*model.Note) error) error) error {
+	enc := json.NewEncoder(w)
+	return each(func(n *model.Note) error {
+		return enc.Encode(n)
+	})
+}
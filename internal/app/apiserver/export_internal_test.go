@@ -0,0 +1,107 @@
+package apiserver
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_HandleExport(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	store.User().Create(other)
+	for _, header := range []string{"First note", "Second note"} {
+		n := model.TestNote(t)
+		n.Header = header
+		store.Notes().Create(n, u)
+	}
+	store.Notes().Create(model.TestNote(t), other)
+	trashed := model.TestNote(t)
+	store.Notes().Create(trashed, u)
+	store.Notes().Trash(trashed.ID)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	export := func(format string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/export?format="+format, nil)
+		setSessionCookie(t, req, secretKey, u)
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("zip", func(t *testing.T) {
+		rec := export("zip")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+
+		zr, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+		assert.NoError(t, err)
+		names := []string{}
+		for _, f := range zr.File {
+			names = append(names, f.Name)
+		}
+		assert.Equal(t, []string{"1-first-note.md", "2-second-note.md"}, names)
+
+		fr, err := zr.File[0].Open()
+		assert.NoError(t, err)
+		b, err := ioutil.ReadAll(fr)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(b), "---\nid: 1\nheader: \"First note\"\n"))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		rec := export("json")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		nl := []*model.Note{}
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(&nl))
+		assert.Len(t, nl, 2)
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		rec := export("ndjson")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		lines := 0
+		sc := bufio.NewScanner(rec.Body)
+		for sc.Scan() {
+			n := &model.Note{}
+			assert.NoError(t, json.Unmarshal(sc.Bytes(), n))
+			lines++
+		}
+		assert.Equal(t, 2, lines)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, export("xml").Code)
+	})
+}
+
+func TestServer_HandleExportEmpty(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/export?format=json", nil)
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "[]", rec.Body.String())
+}
@@ -0,0 +1,280 @@
+package apiserver
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/importer"
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// importField is the multipart form field with the imported file
+const importField = "file"
+
+const (
+	// importJobTTL is how long finished import jobs are kept
+	importJobTTL = 24 * time.Hour
+	// importHeartbeatInterval is how often running import jobs are touched
+	// and stale ones are looked for
+	importHeartbeatInterval = 30 * time.Second
+	// importStaleAfter is how long a running job can go without heartbeat
+	// before it's considered interrupted
+	importStaleAfter = 3 * importHeartbeatInterval
+)
+
+var (
+	errImportFileRequired = errors.New("file is required")
+	errImportTooLarge     = errors.New("file is too large")
+	errImportInterrupted  = errors.New("import was interrupted")
+)
+
+// handleImportCreate saves the uploaded file and imports notes from it
+// in background, the response contains the job to poll for the report
+func (s *server) handleImportCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		r.Body = http.MaxBytesReader(w, r.Body, s.config.ImportMaxSize+multipartOverhead)
+		mr, err := r.MultipartReader()
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				s.error(w, r, http.StatusBadRequest, errImportFileRequired)
+				return
+			}
+			if err != nil {
+				s.error(w, r, http.StatusBadRequest, err)
+				return
+			}
+			if part.FormName() != importField || part.FileName() == "" {
+				continue
+			}
+
+			format := r.URL.Query().Get("format")
+			if format == "" {
+				format, err = importer.Format(part.FileName())
+			} else if format != importer.FormatZip && format != importer.FormatJSON && format != importer.FormatENEX {
+				err = importer.ErrUnknownFormat
+			}
+			if err != nil {
+				s.error(w, r, http.StatusBadRequest, err)
+				return
+			}
+
+			s.startImport(w, r, u, format, part)
+			return
+		}
+	}
+}
+
+func (s *server) startImport(w http.ResponseWriter, r *http.Request, u *model.User, format string, content io.Reader) {
+	f, err := ioutil.TempFile("", "import-")
+	if err != nil {
+		s.error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	size, err := io.CopyN(f, content, s.config.ImportMaxSize+1)
+	if err != nil && err != io.EOF {
+		f.Close()
+		os.Remove(f.Name())
+		s.error(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if size > s.config.ImportMaxSize {
+		f.Close()
+		os.Remove(f.Name())
+		s.error(w, r, http.StatusRequestEntityTooLarge, errImportTooLarge)
+		return
+	}
+
+	if _, err := s.store.ImportJobs().DeleteFinishedBefore(time.Now().Add(-importJobTTL)); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		s.error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	job := &model.ImportJob{
+		ID:     uuid.New().String(),
+		UserID: u.ID,
+		Format: format,
+		Status: model.ImportJobRunning,
+	}
+	if err := s.store.ImportJobs().Create(job); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		s.error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	go s.runImport(job.ID, u, format, f, size)
+
+	w.Header().Set("Location", "/import/"+job.ID)
+	s.respond(w, r, http.StatusAccepted, job)
+}
+
+// runImport imports notes from the file and removes it, the job is
+// touched periodically so other servers don't consider it interrupted
+func (s *server) runImport(id string, u *model.User, format string, f *os.File, size int64) {
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(importHeartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.store.ImportJobs().Touch(id, time.Now()); err != nil {
+					s.logger.Errorf("touching import %s: %v", id, err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err := importer.Read(format, f, size, func(it *importer.Item) error {
+		return s.store.ImportJobs().AddFile(id, s.importNote(u, it))
+	})
+
+	now := time.Now()
+	job := &model.ImportJob{ID: id, Status: model.ImportJobDone, FinishedAt: &now}
+	if err != nil {
+		job.Status = model.ImportJobFailed
+		job.Error = err.Error()
+		s.logger.Warnf("import %s failed: %v", id, err)
+	}
+	if err := s.store.ImportJobs().Finish(job); err != nil {
+		s.logger.Errorf("finishing import %s: %v", id, err)
+	}
+}
+
+// startImportSweeper runs failStaleImports periodically until the returned
+// function is called
+func (s *server) startImportSweeper() func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(importHeartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			s.failStaleImports(time.Now().Add(-importStaleAfter))
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
+
+// failStaleImports fails running jobs without heartbeat since the time,
+// the server running them has stopped and they won't be finished
+func (s *server) failStaleImports(before time.Time) {
+	count, err := s.store.ImportJobs().FailStale(before, errImportInterrupted.Error())
+	if err != nil {
+		s.logger.Errorf("failing stale imports: %v", err)
+		return
+	}
+	if count > 0 {
+		s.logger.Warnf("failed %d interrupted imports", count)
+	}
+}
+
+func (s *server) importNote(u *model.User, it *importer.Item) *model.ImportFileResult {
+	res := &model.ImportFileResult{Name: it.Name, Status: model.ImportFileFailed}
+	if it.Err != nil {
+		res.Error = it.Err.Error()
+		return res
+	}
+
+	n := it.Note
+	n.Tags = model.NormalizeTags(n.Tags)
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+	if n.UpdatedAt.IsZero() {
+		n.UpdatedAt = n.CreatedAt
+	}
+	if err := validateImportedNote(n); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	if err := s.checkNoteLimit(u); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	if err := s.store.Notes().Create(n, u); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.NoteID = n.ID
+	if err := s.store.Tags().SetNoteTags(n, n.Tags); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	for flag, value := range map[string]bool{
+		store.FlagPinned:   n.Pinned,
+		store.FlagArchived: n.Archived,
+		store.FlagStarred:  n.Starred,
+	} {
+		if !value {
+			continue
+		}
+		if err := s.store.Notes().SetFlag(n.ID, flag, true); err != nil {
+			res.Error = err.Error()
+			return res
+		}
+	}
+
+	res.Status = model.ImportFileImported
+	return res
+}
+
+func validateImportedNote(n *model.Note) error {
+	if err := n.Validate(); err != nil {
+		return err
+	}
+	for _, name := range n.Tags {
+		if err := (&model.Tag{Name: name}).Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *server) handleImportGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		job, err := s.store.ImportJobs().Find(mux.Vars(r)["job_id"], u.ID)
+		if err != nil {
+			if err == store.ErrRecordNotFound {
+				s.error(w, r, http.StatusNotFound, err)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, job)
+	}
+}
@@ -0,0 +1,191 @@
+package apiserver
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_HandleImport(t *testing.T) {
+	st := teststore.New()
+	u := model.TestUser(t)
+	st.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	st.User().Create(other)
+
+	secretKey := []byte("secret")
+	s := testServer(t, st, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+
+	archive := &bytes.Buffer{}
+	zw := zip.NewWriter(archive)
+	for name, content := range map[string]string{
+		"plan.md":  "---\nheader: Plan\ntags: [work]\n---\nbody",
+		"empty.md": "---\nheader: Empty\n---\n",
+	} {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	zw.Close()
+
+	b, contentType := multipartBody(t, "file", "notes.zip", archive.Bytes())
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/import", b)
+	req.Header.Set("Content-Type", contentType)
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	job := &model.ImportJob{}
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(job))
+	assert.Equal(t, "/import/"+job.ID, rec.Header().Get("Location"))
+
+	getJob := func(user *model.User) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/import/"+job.ID, nil)
+		setSessionCookie(t, req, secretKey, user)
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+	for deadline := time.Now().Add(5 * time.Second); job.Status == model.ImportJobRunning && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+		rec := getJob(u)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(job))
+	}
+	assert.Equal(t, model.ImportJobDone, job.Status)
+	assert.Equal(t, 1, job.Imported)
+	assert.Equal(t, 1, job.Failed)
+	for _, f := range job.Files {
+		if f.Name == "plan.md" {
+			assert.Equal(t, model.ImportFileImported, f.Status)
+			n, err := st.Notes().FindByID(f.NoteID)
+			assert.NoError(t, err)
+			assert.Equal(t, "Plan", n.Header)
+			assert.Equal(t, []string{"work"}, n.Tags)
+		} else {
+			assert.Equal(t, model.ImportFileFailed, f.Status)
+			assert.NotEmpty(t, f.Error)
+		}
+	}
+
+	assert.Equal(t, http.StatusNotFound, getJob(other).Code)
+
+	// jobs are kept in the store, so any instance reports them
+	s = testServer(t, st, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	assert.Equal(t, http.StatusOK, getJob(u).Code)
+}
+
+func TestServer_HandleImportCreate(t *testing.T) {
+	secretKey := []byte("secret")
+	config := NewConfig()
+	config.ImportMaxSize = 64
+	testCases := []struct {
+		name         string
+		field        string
+		filename     string
+		content      []byte
+		expectedCode int
+	}{
+		{
+			name:         "json",
+			field:        "file",
+			filename:     "notes.json",
+			content:      []byte(`[{"header": "h", "body": "b"}]`),
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name:         "unknown format",
+			field:        "file",
+			filename:     "notes.txt",
+			content:      []byte("text"),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "no file",
+			field:        "other",
+			filename:     "notes.json",
+			content:      []byte("[]"),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "too large",
+			field:        "file",
+			filename:     "notes.json",
+			content:      bytes.Repeat([]byte(" "), 100),
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			st := teststore.New()
+			u := model.TestUser(t)
+			st.User().Create(u)
+			s := testServer(t, st, memstore.New(), sessions.NewCookieStore(secretKey), config)
+
+			b, contentType := multipartBody(t, tc.field, tc.filename, tc.content)
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/import", b)
+			req.Header.Set("Content-Type", contentType)
+			setSessionCookie(t, req, secretKey, u)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if rec.Code == http.StatusAccepted {
+				job := &model.ImportJob{}
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(job))
+				waitImport(t, st, job.ID, u)
+			}
+		})
+	}
+}
+
+// waitImport waits until the import job is finished,
+// so the store isn't used after the test
+func TestServer_FailStaleImports(t *testing.T) {
+	st := teststore.New()
+	u := model.TestUser(t)
+	st.User().Create(u)
+	job := &model.ImportJob{ID: "job", UserID: u.ID, Format: "zip", Status: model.ImportJobRunning}
+	st.ImportJobs().Create(job)
+
+	s := testServer(t, st, memstore.New(), sessions.NewCookieStore([]byte("secret")), NewConfig())
+
+	s.failStaleImports(time.Now().Add(-time.Hour))
+	rj, err := st.ImportJobs().Find(job.ID, u.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ImportJobRunning, rj.Status)
+
+	s.failStaleImports(time.Now().Add(time.Second))
+	rj, err = st.ImportJobs().Find(job.ID, u.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ImportJobFailed, rj.Status)
+	assert.Equal(t, errImportInterrupted.Error(), rj.Error)
+	assert.NotNil(t, rj.FinishedAt)
+}
+
+func waitImport(t *testing.T, st store.Store, id string, u *model.User) {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+		job, err := st.ImportJobs().Find(id, u.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status != model.ImportJobRunning {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("import is not finished")
+}
@@ -0,0 +1,237 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// linkMaxFailedAttempts incorrect passwords in a row lock the link for linkLockout
+	linkMaxFailedAttempts = 5
+	linkLockout           = 15 * time.Minute
+
+	linkPasswordHeader = "X-Link-Password"
+	formatJSON         = "json"
+	formatHTML         = "html"
+)
+
+var (
+	errLinkExpired       = errors.New("link has expired")
+	errIncorrectPassword = errors.New("incorrect password")
+	errLinkLocked        = errors.New("too many incorrect passwords, try again later")
+	errIncorrectFormat   = errors.New("format must be json or html")
+	publicNoteTemplate   = template.Must(template.New("note").Parse(publicNoteHTML))
+)
+
+const publicNoteHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>{{.Header}}</title>
+</head>
+<body>
+<h1>{{.Header}}</h1>
+<div>{{.HTML}}</div>
+<p><small>Updated {{.UpdatedAt.Format "2006-01-02 15:04"}}</small></p>
+</body>
+</html>
+`
+
+type publicNote struct {
+	Header    string    `json:"header"`
+	Body      string    `json:"body"`
+	Tags      []string  `json:"tags,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// HTML is the rendered body for the HTML format
+	HTML template.HTML `json:"-"`
+}
+
+func (s *server) handleLinksCreate() http.HandlerFunc {
+	type request struct {
+		Password  string     `json:"password"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		n, ok := s.authorizeNote(w, r, model.RoleOwner)
+		if !ok {
+			return
+		}
+		if !s.allowSharing(w, r, r.Context().Value(ctxKeyUser).(*model.User)) {
+			return
+		}
+
+		l := &model.NoteLink{
+			NoteID:    n.ID,
+			Password:  req.Password,
+			ExpiresAt: req.ExpiresAt,
+		}
+		if err := s.store.Links().Create(l); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		l.Sanitize()
+		s.respond(w, r, http.StatusCreated, l)
+	}
+}
+
+func (s *server) handleLinksGetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.authorizeNote(w, r, model.RoleOwner)
+		if !ok {
+			return
+		}
+
+		ll, err := s.store.Links().FindByNote(n.ID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		for _, l := range ll {
+			l.Sanitize()
+		}
+		s.respond(w, r, http.StatusOK, ll)
+	}
+}
+
+func (s *server) handleLinksDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.authorizeNote(w, r, model.RoleOwner)
+		if !ok {
+			return
+		}
+
+		if err := s.store.Links().Delete(n.ID, mux.Vars(r)["token"]); err != nil {
+			if err == store.ErrRecordNotFound {
+				s.error(w, r, http.StatusNotFound, err)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// handlePublicNotesGet serves the note of the link without authentication.
+// Password of protected links is taken from X-Link-Password header
+// or from basic auth, so browsers can ask for it. Too many incorrect
+// passwords lock the link for a while to stop guessing
+func (s *server) handlePublicNotesGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format, ok := publicNoteFormat(r)
+		if !ok {
+			s.error(w, r, http.StatusBadRequest, errIncorrectFormat)
+			return
+		}
+
+		l, err := s.store.Links().Find(mux.Vars(r)["token"])
+		if err != nil {
+			if err == store.ErrRecordNotFound {
+				s.error(w, r, http.StatusNotFound, err)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if l.Expired(time.Now()) {
+			s.error(w, r, http.StatusGone, errLinkExpired)
+			return
+		}
+
+		if l.Locked(time.Now()) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(*l.LockedUntil).Seconds())+1))
+			s.error(w, r, http.StatusTooManyRequests, errLinkLocked)
+			return
+		}
+
+		password := r.Header.Get(linkPasswordHeader)
+		if _, p, ok := r.BasicAuth(); ok {
+			password = p
+		}
+		if !l.ComparePassword(password) {
+			if err := s.store.Links().RecordFailedAttempt(l, linkMaxFailedAttempts, linkLockout); err != nil {
+				s.error(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", `Basic realm="note"`)
+			s.error(w, r, http.StatusUnauthorized, errIncorrectPassword)
+			return
+		}
+
+		n, err := s.store.Notes().FindByID(l.NoteID)
+		if err != nil {
+			if err == store.ErrRecordNotFound {
+				s.error(w, r, http.StatusNotFound, err)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if err := s.store.Links().RecordView(l); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		pn := &publicNote{
+			Header:    n.Header,
+			Body:      n.Body,
+			Tags:      n.Tags,
+			CreatedAt: n.CreatedAt,
+			UpdatedAt: n.UpdatedAt,
+		}
+		w.Header().Set("X-Robots-Tag", "noindex")
+		if format == formatHTML {
+			html, err := s.renderNote(n)
+			if err != nil {
+				s.error(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			pn.HTML = template.HTML(html)
+
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			if err := publicNoteTemplate.Execute(w, pn); err != nil {
+				s.logger.Errorf("rendering public note %d: %v", n.ID, err)
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		s.respond(w, r, http.StatusOK, pn)
+	}
+}
+
+// publicNoteFormat returns the format from the query or chooses one by Accept header
+func publicNoteFormat(r *http.Request) (string, bool) {
+	switch format := r.URL.Query().Get("format"); format {
+	case formatJSON, formatHTML:
+		return format, true
+	case "":
+		if strings.Contains(r.Header.Get("Accept"), "text/html") {
+			return formatHTML, true
+		}
+		return formatJSON, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,237 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_HandleLinksCreate(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	editor := model.TestUser(t)
+	editor.Email = "editor@example.org"
+	store.User().Create(editor)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	store.Shares().Save(&model.NoteShare{NoteID: n.ID, UserID: editor.ID, Role: model.RoleEditor})
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name         string
+		user         *model.User
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:         "valid",
+			user:         u,
+			payload:      map[string]interface{}{},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "with password and expiry",
+			user: u,
+			payload: map[string]interface{}{
+				"password":   "password",
+				"expires_at": time.Now().Add(time.Hour),
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "expired",
+			user: u,
+			payload: map[string]interface{}{
+				"expires_at": time.Now().Add(-time.Hour),
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "not owner",
+			user:         editor,
+			payload:      map[string]interface{}{},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/notes/%d/links", n.ID), b)
+			setSessionCookie(t, req, secretKey, tc.user)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusCreated {
+				l := &model.NoteLink{}
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(l))
+				assert.NotEmpty(t, l.Token)
+				assert.Empty(t, l.Password)
+			}
+		})
+	}
+}
+
+func TestServer_HandlePublicNotesGet(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n := model.TestNote(t)
+	n.Header = "<script>header</script>"
+	store.Notes().Create(n, u)
+	l := &model.NoteLink{NoteID: n.ID}
+	store.Links().Create(l)
+	protected := &model.NoteLink{NoteID: n.ID, Password: "password"}
+	store.Links().Create(protected)
+
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore([]byte("secret")), NewConfig())
+	testCases := []struct {
+		name         string
+		path         string
+		header       map[string]string
+		password     string
+		expectedCode int
+		expectedType string
+	}{
+		{
+			name:         "json",
+			path:         "/public/notes/" + l.Token,
+			expectedCode: http.StatusOK,
+			expectedType: "application/json",
+		},
+		{
+			name:         "html",
+			path:         "/public/notes/" + l.Token + "?format=html",
+			expectedCode: http.StatusOK,
+			expectedType: "text/html; charset=utf-8",
+		},
+		{
+			name:         "html by accept",
+			path:         "/public/notes/" + l.Token,
+			header:       map[string]string{"Accept": "text/html,application/xhtml+xml"},
+			expectedCode: http.StatusOK,
+			expectedType: "text/html; charset=utf-8",
+		},
+		{
+			name:         "invalid format",
+			path:         "/public/notes/" + l.Token + "?format=pdf",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "unknown token",
+			path:         "/public/notes/unknown",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "protected without password",
+			path:         "/public/notes/" + protected.Token,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "protected with password header",
+			path:         "/public/notes/" + protected.Token,
+			header:       map[string]string{linkPasswordHeader: "password"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "protected with basic auth",
+			path:         "/public/notes/" + protected.Token,
+			password:     "password",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "protected with wrong password",
+			path:         "/public/notes/" + protected.Token,
+			password:     "wrong",
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			for k, v := range tc.header {
+				req.Header.Set(k, v)
+			}
+			if tc.password != "" {
+				req.SetBasicAuth("", tc.password)
+			}
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedType != "" {
+				assert.Equal(t, tc.expectedType, rec.Header().Get("Content-Type"))
+				assert.NotContains(t, rec.Body.String(), "<script>")
+			}
+		})
+	}
+
+	rl, _ := store.Links().Find(l.Token)
+	assert.Equal(t, 3, rl.Views)
+}
+
+func TestServer_HandlePublicNotesGetLockout(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	l := &model.NoteLink{NoteID: n.ID, Password: "password"}
+	store.Links().Create(l)
+
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore([]byte("secret")), NewConfig())
+	get := func(password string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/public/notes/"+l.Token, nil)
+		req.Header.Set(linkPasswordHeader, password)
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < linkMaxFailedAttempts; i++ {
+		assert.Equal(t, http.StatusUnauthorized, get("wrong").Code)
+	}
+
+	rec := get("password")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	rl, _ := store.Links().Find(l.Token)
+	assert.Equal(t, 0, rl.Views)
+}
+
+func TestServer_HandleLinksDelete(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	l := &model.NoteLink{NoteID: n.ID}
+	store.Links().Create(l)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/notes/%d/links/%s", n.ID, l.Token), nil)
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/public/notes/"+l.Token, nil)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
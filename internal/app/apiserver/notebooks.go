@@ -0,0 +1,246 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+var errNotebookNotFound = errors.New("notebook not found")
+
+func (s *server) handleNotebooksCreate() http.HandlerFunc {
+	type request struct {
+		Name     string `json:"name"`
+		ParentID *int   `json:"parent_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := s.checkNotebook(u, req.ParentID); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		nb := &model.Notebook{
+			UserID:   u.ID,
+			ParentID: req.ParentID,
+			Name:     req.Name,
+		}
+		if err := s.store.Notebooks().Create(nb); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusCreated, nb)
+	}
+}
+
+func (s *server) handleNotebooksGetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		nbl, err := s.store.Notebooks().FindByUser(u)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, nbl)
+	}
+}
+
+func (s *server) handleNotebooksGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		nb, ok := s.userNotebook(w, r)
+		if !ok {
+			return
+		}
+		s.respond(w, r, http.StatusOK, nb)
+	}
+}
+
+func (s *server) handleNotebooksUpdate() http.HandlerFunc {
+	type request struct {
+		Name string `json:"name"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		nb, ok := s.userNotebook(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.store.Notebooks().Rename(nb.ID, req.Name); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respondNotebook(w, r, nb.ID)
+	}
+}
+
+func (s *server) handleNotebooksMove() http.HandlerFunc {
+	type request struct {
+		ParentID *int `json:"parent_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		nb, ok := s.userNotebook(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.checkNotebook(u, req.ParentID); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		if err := s.store.Notebooks().Move(nb.ID, req.ParentID); err != nil {
+			if err == store.ErrNotebookCycle {
+				s.error(w, r, http.StatusUnprocessableEntity, err)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respondNotebook(w, r, nb.ID)
+	}
+}
+
+func (s *server) handleNotebooksDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mode := r.URL.Query().Get("mode")
+		if mode == "" {
+			mode = store.NotebookDeleteMoveToParent
+		}
+
+		nb, ok := s.userNotebook(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.store.Notebooks().Delete(nb.ID, mode); err != nil {
+			if err == store.ErrInvalidDeleteMode {
+				s.error(w, r, http.StatusBadRequest, err)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+func (s *server) handleNotesMove() http.HandlerFunc {
+	type request struct {
+		NotebookID *int `json:"notebook_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		n, ok := s.authorizeNote(w, r, model.RoleOwner)
+		if !ok {
+			return
+		}
+
+		if err := s.checkNotebook(u, req.NotebookID); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		if err := s.store.Notes().Move(n.ID, req.NotebookID); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		n.NotebookID = req.NotebookID
+		s.respond(w, r, http.StatusOK, n)
+	}
+}
+
+// userNotebook returns the notebook with id from the request path when it belongs
+// to the user. Otherwise it writes an error response and returns false
+func (s *server) userNotebook(w http.ResponseWriter, r *http.Request) (*model.Notebook, bool) {
+	id, err := pathInt(r, "id")
+	if err != nil {
+		s.error(w, r, http.StatusBadRequest, errIncorrectRequest)
+		return nil, false
+	}
+	u := r.Context().Value(ctxKeyUser).(*model.User)
+
+	nb, err := s.store.Notebooks().Find(id)
+	if err != nil {
+		if err == store.ErrRecordNotFound {
+			s.error(w, r, http.StatusNotFound, err)
+			return nil, false
+		}
+		s.error(w, r, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	if nb.UserID != u.ID {
+		s.error(w, r, http.StatusNotFound, store.ErrRecordNotFound)
+		return nil, false
+	}
+	return nb, true
+}
+
+// checkNotebook returns errNotebookNotFound unless the notebook is top level
+// or belongs to the user
+func (s *server) checkNotebook(u *model.User, id *int) error {
+	if id == nil {
+		return nil
+	}
+
+	nb, err := s.store.Notebooks().Find(*id)
+	if err != nil {
+		if err == store.ErrRecordNotFound {
+			return errNotebookNotFound
+		}
+		return err
+	}
+	if nb.UserID != u.ID {
+		return errNotebookNotFound
+	}
+	return nil
+}
+
+func (s *server) respondNotebook(w http.ResponseWriter, r *http.Request, id int) {
+	nb, err := s.store.Notebooks().Find(id)
+	if err != nil {
+		s.error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	s.respond(w, r, http.StatusOK, nb)
+}
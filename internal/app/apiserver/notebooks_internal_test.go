@@ -0,0 +1,178 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_HandleNotebooks(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	store.User().Create(other)
+
+	work := &model.Notebook{UserID: u.ID, Name: "Work"}
+	store.Notebooks().Create(work)
+	projects := &model.Notebook{UserID: u.ID, ParentID: &work.ID, Name: "Projects"}
+	store.Notebooks().Create(projects)
+	otherNotebook := &model.Notebook{UserID: other.ID, Name: "Other"}
+	store.Notebooks().Create(otherNotebook)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:   "create",
+			method: http.MethodPost,
+			path:   "/notebooks",
+			payload: map[string]interface{}{
+				"name":      "Q3",
+				"parent_id": projects.ID,
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "create in notebook of other user",
+			method: http.MethodPost,
+			path:   "/notebooks",
+			payload: map[string]interface{}{
+				"name":      "Q3",
+				"parent_id": otherNotebook.ID,
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "create without name",
+			method:       http.MethodPost,
+			path:         "/notebooks",
+			payload:      map[string]interface{}{},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "get",
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/notebooks/%d", projects.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "get notebook of other user",
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/notebooks/%d", otherNotebook.ID),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "rename",
+			method:       http.MethodPatch,
+			path:         fmt.Sprintf("/notebooks/%d", projects.ID),
+			payload:      map[string]interface{}{"name": "Plans"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "move into descendant",
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/notebooks/%d/move", work.ID),
+			payload:      map[string]interface{}{"parent_id": projects.ID},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "move to top level",
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/notebooks/%d/move", projects.ID),
+			payload:      map[string]interface{}{"parent_id": nil},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "delete with unknown mode",
+			method:       http.MethodDelete,
+			path:         fmt.Sprintf("/notebooks/%d?mode=unknown", projects.ID),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "delete",
+			method:       http.MethodDelete,
+			path:         fmt.Sprintf("/notebooks/%d?mode=cascade", projects.ID),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			if tc.payload != nil {
+				json.NewEncoder(b).Encode(tc.payload)
+			}
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, b)
+			setSessionCookie(t, req, secretKey, u)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	nbl, _ := store.Notebooks().FindByUser(u)
+	assert.Len(t, nbl, 1)
+}
+
+func TestServer_HandleNotesMove(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	nb := &model.Notebook{UserID: u.ID, Name: "Work"}
+	store.Notebooks().Create(nb)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name         string
+		notebookID   interface{}
+		expectedCode int
+	}{
+		{
+			name:         "to notebook",
+			notebookID:   nb.ID,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "to unknown notebook",
+			notebookID:   nb.ID + 1,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "to top level",
+			notebookID:   nil,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(map[string]interface{}{"notebook_id": tc.notebookID})
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/notes/%d/move", n.ID), b)
+			setSessionCookie(t, req, secretKey, u)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package apiserver
+
+import (
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// trashPurgeInterval is how often notes with expired retention are purged
+const trashPurgeInterval = time.Hour
+
+// startTrashPurger runs purgeTrash periodically until the returned function is called
+func (s *server) startTrashPurger(retention time.Duration) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(trashPurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			s.purgeTrash(time.Now().Add(-retention))
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
+
+// purgeTrash deletes notes moved to trash before the time with their attachments
+func (s *server) purgeTrash(before time.Time) {
+	al, err := s.store.Attachments().FindTrashedBefore(before)
+	if err != nil {
+		s.logger.Errorf("purging trash: %v", err)
+		return
+	}
+
+	count, err := s.store.Notes().PurgeTrash(before)
+	if err != nil {
+		s.logger.Errorf("purging trash: %v", err)
+		return
+	}
+	if count > 0 {
+		s.logger.Infof("purged %d notes from trash", count)
+	}
+
+	// notes restored in the meantime keep their attachments
+	purged := []*model.Attachment{}
+	for _, a := range al {
+		if _, err := s.store.Attachments().Find(a.ID); err == store.ErrRecordNotFound {
+			purged = append(purged, a)
+		}
+	}
+	s.deleteBlobs(purged)
+}
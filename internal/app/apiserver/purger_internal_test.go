@@ -0,0 +1,31 @@
+package apiserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_PurgeTrash(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	store.Notes().Trash(n.ID)
+
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore([]byte("secret")), NewConfig())
+
+	s.purgeTrash(time.Now().Add(-time.Hour))
+	_, err := store.Notes().FindTrashedByID(n.ID)
+	assert.NoError(t, err)
+
+	s.purgeTrash(time.Now().Add(time.Second))
+	_, err = store.Notes().FindTrashedByID(n.ID)
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// handleReferencesGetOutgoing returns wiki links of the note with their
+// status, targets inaccessible to the user are hidden
+func (s *server) handleReferencesGetOutgoing() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		n, ok := s.authorizeNote(w, r, model.RoleViewer)
+		if !ok {
+			return
+		}
+
+		refs, err := s.store.References().FindBySource(n.ID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		for _, ref := range refs {
+			if ref.TargetID == nil {
+				continue
+			}
+			visible, err := s.noteVisible(*ref.TargetID, u)
+			if err != nil {
+				s.error(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			if !visible {
+				ref.TargetID = nil
+				ref.TargetHeader = ""
+			}
+		}
+		s.respond(w, r, http.StatusOK, refs)
+	}
+}
+
+// handleReferencesGetIncoming returns links to the note from notes
+// accessible to the user
+func (s *server) handleReferencesGetIncoming() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		n, ok := s.authorizeNote(w, r, model.RoleViewer)
+		if !ok {
+			return
+		}
+
+		refs, err := s.store.References().FindByTarget(n.ID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		result := []*model.NoteReference{}
+		for _, ref := range refs {
+			visible, err := s.noteVisible(ref.SourceID, u)
+			if err != nil {
+				s.error(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			if visible {
+				result = append(result, ref)
+			}
+		}
+		s.respond(w, r, http.StatusOK, result)
+	}
+}
+
+// noteVisible reports whether the note exists and the user has any role for it
+func (s *server) noteVisible(id int, u *model.User) (bool, error) {
+	n, err := s.store.Notes().FindByID(id)
+	if err != nil {
+		if err == store.ErrRecordNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	role, err := s.noteRole(n, u)
+	return role != "", err
+}
@@ -0,0 +1,115 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_HandleReferences(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	viewer := model.TestUser(t)
+	viewer.Email = "viewer@example.org"
+	store.User().Create(viewer)
+	stranger := model.TestUser(t)
+	stranger.Email = "stranger@example.org"
+	store.User().Create(stranger)
+
+	target := model.TestNote(t)
+	target.Header = "Target"
+	store.Notes().Create(target, u)
+	source := model.TestNote(t)
+	source.Body = "see [[target]] and [[Missing]]"
+	store.Notes().Create(source, u)
+	hidden := model.TestNote(t)
+	hidden.Body = "[[Target]]"
+	store.Notes().Create(hidden, u)
+	store.Shares().Save(&model.NoteShare{NoteID: source.ID, UserID: viewer.ID, Role: model.RoleViewer})
+	store.Shares().Save(&model.NoteShare{NoteID: target.ID, UserID: viewer.ID, Role: model.RoleViewer})
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name         string
+		user         *model.User
+		path         string
+		expectedCode int
+		expected     []string
+	}{
+		{
+			name:         "outlinks",
+			user:         u,
+			path:         fmt.Sprintf("/notes/%d/outlinks", source.ID),
+			expectedCode: http.StatusOK,
+			expected:     []string{"target:ok", "Missing:broken"},
+		},
+		{
+			name:         "backlinks",
+			user:         u,
+			path:         fmt.Sprintf("/notes/%d/backlinks", target.ID),
+			expectedCode: http.StatusOK,
+			expected:     []string{"target:ok", "Target:ok"},
+		},
+		{
+			name:         "backlinks from shared notes only",
+			user:         viewer,
+			path:         fmt.Sprintf("/notes/%d/backlinks", target.ID),
+			expectedCode: http.StatusOK,
+			expected:     []string{"target:ok"},
+		},
+		{
+			name:         "stranger",
+			user:         stranger,
+			path:         fmt.Sprintf("/notes/%d/outlinks", source.ID),
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			setSessionCookie(t, req, secretKey, tc.user)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expected == nil {
+				return
+			}
+
+			refs := []*model.NoteReference{}
+			assert.NoError(t, json.NewDecoder(rec.Body).Decode(&refs))
+			got := []string{}
+			for _, ref := range refs {
+				got = append(got, ref.Link+":"+ref.Status)
+			}
+			assert.ElementsMatch(t, tc.expected, got)
+		})
+	}
+
+	t.Run("inaccessible target is hidden", func(t *testing.T) {
+		store.Shares().Delete(target.ID, viewer.ID)
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/notes/%d/outlinks", source.ID), nil)
+		setSessionCookie(t, req, secretKey, viewer)
+		s.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		refs := []*model.NoteReference{}
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(&refs))
+		for _, ref := range refs {
+			assert.Nil(t, ref.TargetID)
+			assert.Empty(t, ref.TargetHeader)
+		}
+	})
+}
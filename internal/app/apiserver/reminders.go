@@ -0,0 +1,255 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+var (
+	errChannelUnavailable = errors.New("channel is not configured on the server")
+	errIncorrectUnread    = errors.New("unread must be true or false")
+)
+
+func (s *server) handleRemindersCreate() http.HandlerFunc {
+	type request struct {
+		RemindAt   *time.Time `json:"remind_at"`
+		Recurrence string     `json:"recurrence"`
+		Channel    string     `json:"channel"`
+		Target     string     `json:"target"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		n, ok := s.authorizeNote(w, r, model.RoleViewer)
+		if !ok {
+			return
+		}
+
+		rm := &model.Reminder{
+			NoteID:     n.ID,
+			UserID:     u.ID,
+			RemindAt:   req.RemindAt,
+			Recurrence: req.Recurrence,
+			Channel:    req.Channel,
+			Target:     req.Target,
+		}
+		if rm.Channel == "" {
+			rm.Channel = model.ChannelInApp
+		}
+		rm.Reset()
+		if err := s.checkReminder(rm); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		if err := s.store.Reminders().Create(rm); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusCreated, rm)
+	}
+}
+
+func (s *server) handleRemindersGetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		n, ok := s.authorizeNote(w, r, model.RoleViewer)
+		if !ok {
+			return
+		}
+
+		rl, err := s.store.Reminders().FindByNote(n.ID, u)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, rl)
+	}
+}
+
+// handleRemindersUpdate changes the reminder, the recurrence starts over
+// when the time or the recurrence is changed
+func (s *server) handleRemindersUpdate() http.HandlerFunc {
+	type request struct {
+		RemindAt   *time.Time `json:"remind_at"`
+		Recurrence *string    `json:"recurrence"`
+		Channel    *string    `json:"channel"`
+		Target     *string    `json:"target"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		rm, ok := s.userReminder(w, r)
+		if !ok {
+			return
+		}
+
+		if req.RemindAt != nil || req.Recurrence != nil {
+			rm.Reset()
+		}
+		if req.RemindAt != nil {
+			rm.RemindAt = req.RemindAt
+		}
+		if req.Recurrence != nil {
+			rm.Recurrence = *req.Recurrence
+		}
+		if req.Channel != nil {
+			rm.Channel = *req.Channel
+		}
+		if req.Target != nil {
+			rm.Target = *req.Target
+		}
+		if err := s.checkReminder(rm); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		if err := s.store.Reminders().Update(rm); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, rm)
+	}
+}
+
+func (s *server) handleRemindersDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rm, ok := s.userReminder(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.store.Reminders().Delete(rm.ID); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// handleRemindersUpcoming returns reminders of the user for all notes,
+// the nearest first
+func (s *server) handleRemindersUpcoming() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		rl, err := s.store.Reminders().FindByUser(u)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, rl)
+	}
+}
+
+func (s *server) handleNotificationsGetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		unread := false
+		if v := r.URL.Query().Get("unread"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				s.error(w, r, http.StatusBadRequest, errIncorrectUnread)
+				return
+			}
+			unread = b
+		}
+
+		nl, err := s.store.Notifications().FindByUser(u, unread)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, nl)
+	}
+}
+
+func (s *server) handleNotificationsRead() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		id, err := pathInt(r, "id")
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, errIncorrectRequest)
+			return
+		}
+
+		if err := s.store.Notifications().MarkRead(id, u); err != nil {
+			if err == store.ErrRecordNotFound {
+				s.error(w, r, http.StatusNotFound, err)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// checkReminder validates the reminder and checks the server can deliver it
+func (s *server) checkReminder(rm *model.Reminder) error {
+	if err := rm.Validate(); err != nil {
+		return err
+	}
+	if _, ok := s.notifiers[rm.Channel]; !ok {
+		return errChannelUnavailable
+	}
+	return nil
+}
+
+// userReminder returns the reminder with rid from the request path when
+// it belongs to the user and the note with id which the user can still
+// view. Otherwise it writes an error response and returns false
+func (s *server) userReminder(w http.ResponseWriter, r *http.Request) (*model.Reminder, bool) {
+	u := r.Context().Value(ctxKeyUser).(*model.User)
+
+	n, ok := s.authorizeNote(w, r, model.RoleViewer)
+	if !ok {
+		return nil, false
+	}
+
+	id, err := pathInt(r, "rid")
+	if err != nil {
+		s.error(w, r, http.StatusBadRequest, errIncorrectRequest)
+		return nil, false
+	}
+
+	rm, err := s.store.Reminders().Find(id)
+	if err != nil {
+		if err == store.ErrRecordNotFound {
+			s.error(w, r, http.StatusNotFound, err)
+			return nil, false
+		}
+		s.error(w, r, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	if rm.NoteID != n.ID || rm.UserID != u.ID {
+		s.error(w, r, http.StatusNotFound, store.ErrRecordNotFound)
+		return nil, false
+	}
+	return rm, true
+}
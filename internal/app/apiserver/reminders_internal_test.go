@@ -0,0 +1,256 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_HandleReminders(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	viewer := model.TestUser(t)
+	viewer.Email = "viewer@example.org"
+	store.User().Create(viewer)
+	stranger := model.TestUser(t)
+	stranger.Email = "stranger@example.org"
+	store.User().Create(stranger)
+
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	store.Shares().Save(&model.NoteShare{NoteID: n.ID, UserID: viewer.ID, Role: model.RoleViewer})
+	rm := model.TestReminder(t)
+	rm.NoteID = n.ID
+	rm.UserID = u.ID
+	store.Reminders().Create(rm)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	remindAt := time.Now().Add(time.Hour).Format(time.RFC3339)
+	testCases := []struct {
+		name         string
+		user         *model.User
+		method       string
+		path         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:   "create",
+			user:   u,
+			method: http.MethodPost,
+			path:   fmt.Sprintf("/notes/%d/reminders", n.ID),
+			payload: map[string]interface{}{
+				"remind_at":  remindAt,
+				"recurrence": "FREQ=WEEKLY;BYDAY=MO",
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "create by viewer",
+			user:   viewer,
+			method: http.MethodPost,
+			path:   fmt.Sprintf("/notes/%d/reminders", n.ID),
+			payload: map[string]interface{}{
+				"remind_at": remindAt,
+				"channel":   "webhook",
+				"target":    "https://example.org/hook",
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "create invalid recurrence",
+			user:   u,
+			method: http.MethodPost,
+			path:   fmt.Sprintf("/notes/%d/reminders", n.ID),
+			payload: map[string]interface{}{
+				"remind_at":  remindAt,
+				"recurrence": "FREQ=SOMETIMES",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "create email without smtp",
+			user:   u,
+			method: http.MethodPost,
+			path:   fmt.Sprintf("/notes/%d/reminders", n.ID),
+			payload: map[string]interface{}{
+				"remind_at": remindAt,
+				"channel":   "email",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "create by stranger",
+			user:   stranger,
+			method: http.MethodPost,
+			path:   fmt.Sprintf("/notes/%d/reminders", n.ID),
+			payload: map[string]interface{}{
+				"remind_at": remindAt,
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "get all",
+			user:         u,
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/notes/%d/reminders", n.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "update",
+			user:   u,
+			method: http.MethodPatch,
+			path:   fmt.Sprintf("/notes/%d/reminders/%d", n.ID, rm.ID),
+			payload: map[string]interface{}{
+				"recurrence": "FREQ=DAILY;COUNT=3",
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "update reminder of other user",
+			user:   viewer,
+			method: http.MethodPatch,
+			path:   fmt.Sprintf("/notes/%d/reminders/%d", n.ID, rm.ID),
+			payload: map[string]interface{}{
+				"recurrence": "",
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "delete reminder of other user",
+			user:         viewer,
+			method:       http.MethodDelete,
+			path:         fmt.Sprintf("/notes/%d/reminders/%d", n.ID, rm.ID),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "upcoming",
+			user:         u,
+			method:       http.MethodGet,
+			path:         "/reminders",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "delete",
+			user:         u,
+			method:       http.MethodDelete,
+			path:         fmt.Sprintf("/notes/%d/reminders/%d", n.ID, rm.ID),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			if tc.payload != nil {
+				json.NewEncoder(b).Encode(tc.payload)
+			}
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, b)
+			setSessionCookie(t, req, secretKey, tc.user)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	rl, err := store.Reminders().FindByUser(u)
+	assert.NoError(t, err)
+	if assert.Len(t, rl, 1) {
+		assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO", rl[0].Recurrence)
+	}
+}
+
+func TestServer_HandleNotifications(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	store.User().Create(other)
+	nt := &model.Notification{UserID: u.ID, Kind: model.NotificationReminder, Message: "Reminder: header"}
+	store.Notifications().Create(nt)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name         string
+		user         *model.User
+		method       string
+		path         string
+		expectedCode int
+		expectedLen  int
+	}{
+		{
+			name:         "unread",
+			user:         u,
+			method:       http.MethodGet,
+			path:         "/notifications?unread=true",
+			expectedCode: http.StatusOK,
+			expectedLen:  1,
+		},
+		{
+			name:         "incorrect unread",
+			user:         u,
+			method:       http.MethodGet,
+			path:         "/notifications?unread=maybe",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "read by other user",
+			user:         other,
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/notifications/%d/read", nt.ID),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "read",
+			user:         u,
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/notifications/%d/read", nt.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "unread after read",
+			user:         u,
+			method:       http.MethodGet,
+			path:         "/notifications?unread=true",
+			expectedCode: http.StatusOK,
+			expectedLen:  0,
+		},
+		{
+			name:         "all",
+			user:         u,
+			method:       http.MethodGet,
+			path:         "/notifications",
+			expectedCode: http.StatusOK,
+			expectedLen:  1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			setSessionCookie(t, req, secretKey, tc.user)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.method == http.MethodGet && tc.expectedCode == http.StatusOK {
+				nl := []*model.Notification{}
+				json.NewDecoder(rec.Body).Decode(&nl)
+				assert.Len(t, nl, tc.expectedLen)
+			}
+		})
+	}
+}
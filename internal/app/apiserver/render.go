@@ -0,0 +1,48 @@
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/render"
+)
+
+func (s *server) handleNotesRender() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.authorizeNote(w, r, model.RoleViewer)
+		if !ok {
+			return
+		}
+
+		w.Header().Set("ETag", n.ETag())
+		if etagMatches(r.Header.Get("If-None-Match"), n) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		html, err := s.renderNote(n)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(html))
+	}
+}
+
+// renderNote returns sanitized HTML of the note body, notes are rendered
+// once per version
+func (s *server) renderNote(n *model.Note) (string, error) {
+	if html, ok := s.renderCache.Get(n.ID, n.Version); ok {
+		return html, nil
+	}
+
+	html, err := render.Markdown(n.Body)
+	if err != nil {
+		return "", err
+	}
+	s.renderCache.Add(n.ID, n.Version, html)
+	return html, nil
+}
@@ -0,0 +1,104 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_HandleNotesRender(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	stranger := model.TestUser(t)
+	stranger.Email = "stranger@example.org"
+	store.User().Create(stranger)
+	n := model.TestNote(t)
+	n.Body = "# Title\n\n- [x] done\n\n<script>alert(1)</script>"
+	store.Notes().Create(n, u)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/notes/%d/render", n.ID), nil)
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "<h1>Title</h1>")
+	assert.Contains(t, rec.Body.String(), "checkbox")
+	assert.NotContains(t, rec.Body.String(), "<script>")
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/notes/%d/render", n.ID), nil)
+	req.Header.Set("If-None-Match", n.ETag())
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/notes/%d/render", n.ID), nil)
+	setSessionCookie(t, req, secretKey, stranger)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServer_HandleNotesGetHTML(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n := model.TestNote(t)
+	n.Body = "**bold**"
+	store.Notes().Create(n, u)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name         string
+		format       string
+		expectedCode int
+		expectedHTML string
+	}{
+		{
+			name:         "json",
+			format:       "",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "html",
+			format:       "html",
+			expectedCode: http.StatusOK,
+			expectedHTML: "<p><strong>bold</strong></p>\n",
+		},
+		{
+			name:         "invalid format",
+			format:       "xml",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/notes/%d?format=%s", n.ID, tc.format), nil)
+			setSessionCookie(t, req, secretKey, u)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				res := map[string]interface{}{}
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+				html, _ := res["html"].(string)
+				assert.Equal(t, tc.expectedHTML, html)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/KapitanD/http-api-server/internal/app/diff"
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+func (s *server) handleRevisionsGetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.authorizeNote(w, r, model.RoleViewer)
+		if !ok {
+			return
+		}
+
+		rl, err := s.store.Revisions().FindByNote(n.ID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, rl)
+	}
+}
+
+func (s *server) handleRevisionsGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.authorizeNote(w, r, model.RoleViewer)
+		if !ok {
+			return
+		}
+
+		rev, ok := s.noteRevision(w, r, n, "rev")
+		if !ok {
+			return
+		}
+		s.respond(w, r, http.StatusOK, rev)
+	}
+}
+
+func (s *server) handleRevisionsDiff() http.HandlerFunc {
+	type response struct {
+		From int    `json:"from"`
+		To   int    `json:"to"`
+		Diff string `json:"diff"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.authorizeNote(w, r, model.RoleViewer)
+		if !ok {
+			return
+		}
+
+		a, ok := s.noteRevision(w, r, n, "a")
+		if !ok {
+			return
+		}
+		b, ok := s.noteRevision(w, r, n, "b")
+		if !ok {
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, &response{
+			From: a.Revision,
+			To:   b.Revision,
+			Diff: diff.Unified(
+				fmt.Sprintf("revision %d", a.Revision),
+				fmt.Sprintf("revision %d", b.Revision),
+				a.Text(),
+				b.Text(),
+			),
+		})
+	}
+}
+
+func (s *server) handleRevisionsRestore() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.authorizeNote(w, r, model.RoleEditor)
+		if !ok {
+			return
+		}
+
+		rev, ok := s.noteRevision(w, r, n, "rev")
+		if !ok {
+			return
+		}
+
+		version, ok := s.noteVersion(w, r, n)
+		if !ok {
+			return
+		}
+
+		un := &model.Note{
+			Header:  rev.Header,
+			Body:    rev.Body,
+			Version: version,
+		}
+		if err := s.store.Notes().Update(n.ID, un); err != nil {
+			if err == store.ErrVersionConflict {
+				s.error(w, r, http.StatusPreconditionFailed, err)
+				return
+			}
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+		if err := s.pruneRevisions(n.ID); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		n, err := s.store.Notes().FindByID(n.ID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		w.Header().Set("ETag", n.ETag())
+		s.respond(w, r, http.StatusOK, n)
+	}
+}
+
+// noteRevision writes an error response and returns false
+// when the revision from the path variable doesn't exist
+func (s *server) noteRevision(w http.ResponseWriter, r *http.Request, n *model.Note, name string) (*model.NoteRevision, bool) {
+	revision, err := pathInt(r, name)
+	if err != nil {
+		s.error(w, r, http.StatusBadRequest, errIncorrectRequest)
+		return nil, false
+	}
+
+	rev, err := s.store.Revisions().Find(n.ID, revision)
+	if err != nil {
+		if err == store.ErrRecordNotFound {
+			s.error(w, r, http.StatusNotFound, err)
+			return nil, false
+		}
+		s.error(w, r, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	return rev, true
+}
+
+func (s *server) pruneRevisions(noteID int) error {
+	if s.config.RevisionLimit <= 0 {
+		return nil
+	}
+	return s.store.Revisions().Prune(noteID, s.config.RevisionLimit)
+}
@@ -0,0 +1,205 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_HandleRevisions(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	store.User().Create(other)
+
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	store.Notes().Update(n.ID, &model.Note{Body: "body\nsecond line"})
+	otherNote := model.TestNote(t)
+	store.Notes().Create(otherNote, other)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		expectedCode int
+	}{
+		{
+			name:         "list",
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/notes/%d/revisions", n.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "list of other user note",
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/notes/%d/revisions", otherNote.ID),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "get",
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/notes/%d/revisions/1", n.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "get unknown",
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/notes/%d/revisions/10", n.ID),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "diff",
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/notes/%d/revisions/1/diff/2", n.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "restore",
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/notes/%d/revisions/1/restore", n.ID),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			setSessionCookie(t, req, secretKey, u)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	rn, _ := store.Notes().FindByID(n.ID)
+	assert.Equal(t, "body", rn.Body)
+	rl, _ := store.Revisions().FindByNote(n.ID)
+	assert.Len(t, rl, 3)
+}
+
+func TestServer_HandleRevisionsRestoreConditional(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	store.Notes().Update(n.ID, &model.Note{Body: "second"})
+
+	secretKey := []byte("secret")
+	strictConfig := NewConfig()
+	strictConfig.StrictConcurrency = true
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	strict := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), strictConfig)
+	path := fmt.Sprintf("/notes/%d/revisions/1/restore", n.ID)
+	testCases := []struct {
+		name         string
+		s            *server
+		ifMatch      string
+		expectedCode int
+		expectedETag string
+	}{
+		{
+			name:         "without If-Match in strict mode",
+			s:            strict,
+			expectedCode: http.StatusPreconditionRequired,
+		},
+		{
+			name:         "with stale If-Match",
+			s:            s,
+			ifMatch:      `"1"`,
+			expectedCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:         "with If-Match",
+			s:            strict,
+			ifMatch:      `"2"`,
+			expectedCode: http.StatusOK,
+			expectedETag: `"3"`,
+		},
+		{
+			name:         "without If-Match",
+			s:            s,
+			expectedCode: http.StatusOK,
+			expectedETag: `"4"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, path, nil)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			setSessionCookie(t, req, secretKey, u)
+			tc.s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			assert.Equal(t, tc.expectedETag, rec.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestServer_HandleRevisionsDiff(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	store.Notes().Update(n.ID, &model.Note{Body: "changed"})
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/notes/%d/revisions/1/diff/2", n.ID), nil)
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	res := map[string]interface{}{}
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	assert.Equal(
+		t,
+		"--- revision 1\n+++ revision 2\n@@ -1,3 +1,3 @@\n header\n \n-body\n+changed\n",
+		res["diff"],
+	)
+}
+
+func TestServer_PruneRevisions(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+
+	secretKey := []byte("secret")
+	config := NewConfig()
+	config.RevisionLimit = 2
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), config)
+	for _, body := range []string{"one", "two", "three"} {
+		rec := httptest.NewRecorder()
+		b, _ := json.Marshal(map[string]string{"body": body})
+		req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/notes/%d", n.ID), bytes.NewReader(b))
+		setSessionCookie(t, req, secretKey, u)
+		s.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	rl, _ := store.Revisions().FindByNote(n.ID)
+	assert.Len(t, rl, 2)
+	assert.Equal(t, 4, rl[0].Revision)
+	assert.Equal(t, "three", rl[0].Body)
+}
@@ -0,0 +1,116 @@
+package apiserver
+
+import (
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/mail"
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/notifier"
+)
+
+// reminderBatchSize is the number of reminders claimed and fired at once
+const reminderBatchSize = 100
+
+// webhookTimeout limits delivery of a single reminder by webhook
+const webhookTimeout = 10 * time.Second
+
+// newMailer returns the SMTP sender or the file sink for development,
+// nil when neither is configured
+func newMailer(config *Config) mail.Sender {
+	switch {
+	case config.SMTPAddr != "":
+		return mail.NewSMTP(config.SMTPAddr, config.SMTPUsername, config.SMTPPassword, config.MailFrom)
+	case config.MailFile != "":
+		return mail.NewFile(config.MailFile, config.MailFrom)
+	}
+	return nil
+}
+
+// newNotifiers returns notifiers of the channels configured on the server
+func newNotifiers(s *server) map[string]notifier.Notifier {
+	notifiers := map[string]notifier.Notifier{
+		model.ChannelWebhook: notifier.NewWebhook(webhookTimeout, s.config.WebhookAllowPrivate),
+		model.ChannelInApp:   notifier.NewInApp(s.store.Notifications()),
+	}
+	if s.mailer != nil {
+		notifiers[model.ChannelEmail] = notifier.NewEmail(s.mailer)
+	}
+	return notifiers
+}
+
+// startReminderScheduler runs fireReminders periodically until the returned
+// function is called. Replicas of the server poll the same reminders, the
+// store claims them so each one is fired once
+func (s *server) startReminderScheduler(interval time.Duration) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.fireReminders(time.Now())
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
+
+// fireReminders delivers reminders due at the time in batches
+func (s *server) fireReminders(now time.Time) {
+	for {
+		count, err := s.store.Reminders().FireDue(now, reminderBatchSize, s.deliverReminder)
+		if err != nil {
+			s.logger.Errorf("firing reminders: %v", err)
+			return
+		}
+		if count > 0 {
+			s.logger.Infof("fired %d reminders", count)
+		}
+		if count < reminderBatchSize {
+			return
+		}
+	}
+}
+
+// deliverReminder notifies the user about the note of the reminder,
+// reminders of notes the user can't access anymore are not delivered
+func (s *server) deliverReminder(rm *model.Reminder) error {
+	n, err := s.store.Notes().FindByID(rm.NoteID)
+	if err != nil {
+		return err
+	}
+	u, err := s.store.User().Find(rm.UserID)
+	if err != nil {
+		return err
+	}
+	role, err := s.noteRole(n, u)
+	if err != nil {
+		return err
+	}
+	if role == "" {
+		return errForbidden
+	}
+
+	nt, ok := s.notifiers[rm.Channel]
+	if !ok {
+		return errChannelUnavailable
+	}
+	return nt.Notify(&notifier.Message{
+		Kind:   model.NotificationReminder,
+		UserID: u.ID,
+		Email:  u.Email,
+		NoteID: n.ID,
+		Header: n.Header,
+		Text:   "Reminder: " + n.Header,
+		Target: rm.Target,
+		At:     *rm.RemindAt,
+	})
+}
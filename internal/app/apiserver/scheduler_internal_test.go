@@ -0,0 +1,77 @@
+package apiserver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/notifier"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+type testNotifier struct {
+	messages []*notifier.Message
+	err      error
+}
+
+func (n *testNotifier) Notify(m *notifier.Message) error {
+	n.messages = append(n.messages, m)
+	return n.err
+}
+
+func TestServer_FireReminders(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	store.User().Create(other)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	own := model.TestReminder(t)
+	own.UserID = u.ID
+	// the note was unshared after the reminder was set
+	unshared := model.TestReminder(t)
+	unshared.UserID = other.ID
+	for _, rm := range []*model.Reminder{own, unshared} {
+		rm.NoteID = n.ID
+		rm.RemindAt = &past
+		rm.Channel = model.ChannelWebhook
+		rm.Target = "https://example.org/hook"
+		store.Reminders().Create(rm)
+	}
+
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore([]byte("secret")), NewConfig())
+	webhook := &testNotifier{}
+	s.notifiers[model.ChannelWebhook] = webhook
+
+	s.fireReminders(now)
+	if assert.Len(t, webhook.messages, 1) {
+		assert.Equal(t, u.Email, webhook.messages[0].Email)
+		assert.Equal(t, "https://example.org/hook", webhook.messages[0].Target)
+	}
+	rm, err := store.Reminders().Find(unshared.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, rm.RemindAt)
+	assert.Equal(t, errForbidden.Error(), rm.LastError)
+
+	webhook.err = errors.New("unavailable")
+	later := now.Add(time.Minute)
+	rm, _ = store.Reminders().Find(own.ID)
+	rm.RemindAt = &later
+	rm.Recurrence = "FREQ=HOURLY"
+	store.Reminders().Update(rm)
+
+	s.fireReminders(later)
+	rm, err = store.Reminders().Find(own.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "unavailable", rm.LastError)
+	assert.WithinDuration(t, later.Add(time.Hour), *rm.RemindAt, time.Millisecond)
+}
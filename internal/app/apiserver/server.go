@@ -6,11 +6,17 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
+	"github.com/KapitanD/http-api-server/internal/app/jwt"
+	"github.com/KapitanD/http-api-server/internal/app/mail"
 	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/notifier"
+	"github.com/KapitanD/http-api-server/internal/app/render"
 	"github.com/KapitanD/http-api-server/internal/app/store"
 	"github.com/google/uuid"
 	"github.com/gorilla/handlers"
@@ -23,12 +29,21 @@ const (
 	sessionName        = "note-session"
 	ctxKeyUser  ctxKey = iota
 	ctxKeyRequestID
+	ctxKeyAPIToken
 )
 
 var (
 	errIncorrectEmailOrPassword = errors.New("incorrect email or password")
 	errNotAuthenticated         = errors.New("not authenticated")
 	errIncorrectRequest         = errors.New("incorrect request")
+	errIncorrectTagMatch        = errors.New("tag match must be any or all")
+	errIncorrectOrder           = errors.New("order must be asc or desc")
+	errIncorrectLimit           = errors.New("incorrect limit")
+	errIfMatchRequired          = errors.New("If-Match header is required")
+	errForbidden                = store.ErrForbidden
+	errIncorrectNotebookID      = errors.New("incorrect notebook id")
+	errIncorrectPinnedFirst     = errors.New("pinned_first must be true or false")
+	errIncorrectStarred         = errors.New("starred must be true or false")
 )
 
 type ctxKey int8
@@ -37,20 +52,37 @@ type server struct {
 	router       *mux.Router
 	logger       *logrus.Logger
 	store        store.Store
+	blobStore    store.BlobStore
 	sessionStore sessions.Store
+	config       *Config
+	renderCache  *render.Cache
+	notifiers    map[string]notifier.Notifier
+	tokenSigner  *jwt.Signer
+	mailer       mail.Sender
 }
 
-func newServer(store store.Store, sessionStore sessions.Store) *server {
+func newServer(store store.Store, blobStore store.BlobStore, sessionStore sessions.Store, config *Config) (*server, error) {
+	tokenSigner, err := newTokenSigner(config)
+	if err != nil {
+		return nil, err
+	}
+
 	s := &server{
 		router:       mux.NewRouter(),
 		logger:       logrus.New(),
 		store:        store,
+		blobStore:    blobStore,
 		sessionStore: sessionStore,
+		config:       config,
+		renderCache:  render.NewCache(config.RenderCacheSize),
+		tokenSigner:  tokenSigner,
 	}
+	s.mailer = newMailer(config)
+	s.notifiers = newNotifiers(s)
 
 	s.configureRouter()
 
-	return s
+	return s, nil
 }
 
 func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -74,19 +106,142 @@ func (s *server) configureRouter() {
 	s.router.HandleFunc("/healthz", s.handleHealthCheck())
 	s.router.HandleFunc("/readyz", s.handleReadyCheck(isReady))
 	s.router.HandleFunc("/users", s.handleUsersCreate()).Methods("POST")
+	s.router.HandleFunc("/users/verify/{token}", s.handleUsersVerify()).Methods("GET")
 	s.router.HandleFunc("/sessions", s.handleSessionCreate()).Methods("POST")
+	s.router.HandleFunc("/password-resets", s.handlePasswordResetsCreate()).Methods("POST")
+	s.router.HandleFunc("/password-resets/{token}", s.handlePasswordResetsComplete()).Methods("POST")
+
+	auth := s.router.PathPrefix("/auth").Subrouter()
+	auth.HandleFunc("/token", s.handleAuthToken()).Methods("POST")
+	auth.HandleFunc("/refresh", s.handleAuthRefresh()).Methods("POST")
+	auth.HandleFunc("/revoke", s.handleAuthRevoke()).Methods("POST")
+
+	userSessions := s.router.PathPrefix("/sessions").Subrouter()
+	userSessions.Use(s.authenticateUser)
+	userSessions.Use(s.requireScopes(model.ScopeAccount, model.ScopeAccount))
+	userSessions.HandleFunc("", s.handleSessionsGetAll()).Methods("GET")
+	userSessions.HandleFunc("", s.handleSessionsDeleteAll()).Methods("DELETE")
+	userSessions.HandleFunc("/current", s.handleSessionsDeleteCurrent()).Methods("DELETE")
+	userSessions.HandleFunc("/{id:[0-9]+}", s.handleSessionsDelete()).Methods("DELETE")
+
+	tokens := s.router.PathPrefix("/tokens").Subrouter()
+	tokens.Use(s.authenticateUser)
+	tokens.Use(s.requireScopes(model.ScopeAccount, model.ScopeAccount))
+	tokens.HandleFunc("", s.handleTokensCreate()).Methods("POST")
+	tokens.HandleFunc("", s.handleTokensGetAll()).Methods("GET")
+	tokens.HandleFunc("/{id:[0-9]+}", s.handleTokensDelete()).Methods("DELETE")
+
+	account := s.router.PathPrefix("/account").Subrouter()
+	account.Use(s.authenticateUser)
+	account.Use(s.requireScopes(model.ScopeAccount, model.ScopeAccount))
+	account.HandleFunc("/password", s.handleAccountPassword()).Methods("POST")
+	account.HandleFunc("/verification", s.handleVerificationResend()).Methods("POST")
 
 	private := s.router.PathPrefix("/private").Subrouter()
 	private.Use(s.authenticateUser)
+	private.Use(s.requireScopes(model.ScopeAccount, model.ScopeAccount))
 	private.HandleFunc("/whoami", s.handleWhoami()).Methods("GET")
 
 	notes := s.router.PathPrefix("/notes").Subrouter()
 	notes.Use(s.authenticateUser)
+	notes.Use(s.requireScopes(model.ScopeNotesRead, model.ScopeNotesWrite))
 	notes.HandleFunc("/", s.handleNotesCreate()).Methods("POST")
 	notes.HandleFunc("/{id:[0-9]+}", s.handleNotesUpdate()).Methods("PATCH")
 	notes.HandleFunc("/{id:[0-9]+}", s.handleNotesDelete()).Methods("DELETE")
 	notes.HandleFunc("/", s.handleNotesGetAll()).Methods("GET")
+	notes.HandleFunc("/bulk", s.handleNotesBulk()).Methods("POST")
+	notes.HandleFunc("/from-template/{tid:[0-9]+}", s.handleNotesFromTemplate()).Methods("POST")
+	notes.HandleFunc("/search", s.handleNotesSearch()).Methods("GET")
+	notes.HandleFunc("/trash", s.handleNotesGetTrash()).Methods("GET")
+	notes.HandleFunc("/shared-with-me", s.handleNotesSharedWithMe()).Methods("GET")
+	notes.HandleFunc("/{id:[0-9]+}/shares", s.handleSharesGetAll()).Methods("GET")
+	notes.HandleFunc("/{id:[0-9]+}/shares", s.handleSharesCreate()).Methods("POST")
+	notes.HandleFunc("/{id:[0-9]+}/shares", s.handleSharesDelete()).Methods("DELETE")
+	notes.HandleFunc("/{id:[0-9]+}/links", s.handleLinksGetAll()).Methods("GET")
+	notes.HandleFunc("/{id:[0-9]+}/links", s.handleLinksCreate()).Methods("POST")
+	notes.HandleFunc("/{id:[0-9]+}/links/{token}", s.handleLinksDelete()).Methods("DELETE")
+	notes.HandleFunc("/{id:[0-9]+}/outlinks", s.handleReferencesGetOutgoing()).Methods("GET")
+	notes.HandleFunc("/{id:[0-9]+}/backlinks", s.handleReferencesGetIncoming()).Methods("GET")
+	notes.HandleFunc("/{id:[0-9]+}/items", s.handleChecklistGetAll()).Methods("GET")
+	notes.HandleFunc("/{id:[0-9]+}/items", s.handleChecklistCreate()).Methods("POST")
+	notes.HandleFunc("/{id:[0-9]+}/items/{iid:[0-9]+}", s.handleChecklistUpdate()).Methods("PATCH")
+	notes.HandleFunc("/{id:[0-9]+}/items/{iid:[0-9]+}", s.handleChecklistDelete()).Methods("DELETE")
+	notes.HandleFunc("/{id:[0-9]+}/items/{iid:[0-9]+}/toggle", s.handleChecklistToggle()).Methods("POST")
+	notes.HandleFunc("/{id:[0-9]+}/comments", s.handleCommentsGetAll()).Methods("GET")
+	notes.HandleFunc("/{id:[0-9]+}/comments", s.handleCommentsCreate()).Methods("POST")
+	notes.HandleFunc("/{id:[0-9]+}/comments/{cid:[0-9]+}", s.handleCommentsUpdate()).Methods("PATCH")
+	notes.HandleFunc("/{id:[0-9]+}/comments/{cid:[0-9]+}", s.handleCommentsDelete()).Methods("DELETE")
+	notes.HandleFunc("/{id:[0-9]+}/reminders", s.handleRemindersGetAll()).Methods("GET")
+	notes.HandleFunc("/{id:[0-9]+}/reminders", s.handleRemindersCreate()).Methods("POST")
+	notes.HandleFunc("/{id:[0-9]+}/reminders/{rid:[0-9]+}", s.handleRemindersUpdate()).Methods("PATCH")
+	notes.HandleFunc("/{id:[0-9]+}/reminders/{rid:[0-9]+}", s.handleRemindersDelete()).Methods("DELETE")
+	notes.HandleFunc("/{id:[0-9]+}/attachments", s.handleAttachmentsGetAll()).Methods("GET")
+	notes.HandleFunc("/{id:[0-9]+}/attachments", s.handleAttachmentsCreate()).Methods("POST")
+	notes.HandleFunc("/{id:[0-9]+}/attachments/{aid:[0-9]+}", s.handleAttachmentsGet()).Methods("GET")
+	notes.HandleFunc("/{id:[0-9]+}/attachments/{aid:[0-9]+}", s.handleAttachmentsDelete()).Methods("DELETE")
+	notes.HandleFunc("/{id:[0-9]+}/pin", s.handleNotesSetFlag(store.FlagPinned, true)).Methods("POST")
+	notes.HandleFunc("/{id:[0-9]+}/pin", s.handleNotesSetFlag(store.FlagPinned, false)).Methods("DELETE")
+	notes.HandleFunc("/{id:[0-9]+}/archive", s.handleNotesSetFlag(store.FlagArchived, true)).Methods("POST")
+	notes.HandleFunc("/{id:[0-9]+}/archive", s.handleNotesSetFlag(store.FlagArchived, false)).Methods("DELETE")
+	notes.HandleFunc("/{id:[0-9]+}/star", s.handleNotesSetFlag(store.FlagStarred, true)).Methods("POST")
+	notes.HandleFunc("/{id:[0-9]+}/star", s.handleNotesSetFlag(store.FlagStarred, false)).Methods("DELETE")
+	notes.HandleFunc("/{id:[0-9]+}/render", s.handleNotesRender()).Methods("GET")
+	notes.HandleFunc("/{id:[0-9]+}/move", s.handleNotesMove()).Methods("POST")
+	notes.HandleFunc("/{id:[0-9]+}/restore", s.handleNotesRestore()).Methods("POST")
+	notes.HandleFunc("/{id:[0-9]+}/revisions", s.handleRevisionsGetAll()).Methods("GET")
+	notes.HandleFunc("/{id:[0-9]+}/revisions/{rev:[0-9]+}", s.handleRevisionsGet()).Methods("GET")
+	notes.HandleFunc("/{id:[0-9]+}/revisions/{a:[0-9]+}/diff/{b:[0-9]+}", s.handleRevisionsDiff()).Methods("GET")
+	notes.HandleFunc("/{id:[0-9]+}/revisions/{rev:[0-9]+}/restore", s.handleRevisionsRestore()).Methods("POST")
 	notes.HandleFunc("/{id:[0-9]+}", s.handleNotesGet()).Methods("GET")
+
+	notebooks := s.router.PathPrefix("/notebooks").Subrouter()
+	notebooks.Use(s.authenticateUser)
+	notebooks.Use(s.requireScopes(model.ScopeNotesRead, model.ScopeNotesWrite))
+	notebooks.HandleFunc("", s.handleNotebooksCreate()).Methods("POST")
+	notebooks.HandleFunc("", s.handleNotebooksGetAll()).Methods("GET")
+	notebooks.HandleFunc("/{id:[0-9]+}", s.handleNotebooksGet()).Methods("GET")
+	notebooks.HandleFunc("/{id:[0-9]+}", s.handleNotebooksUpdate()).Methods("PATCH")
+	notebooks.HandleFunc("/{id:[0-9]+}", s.handleNotebooksDelete()).Methods("DELETE")
+	notebooks.HandleFunc("/{id:[0-9]+}/move", s.handleNotebooksMove()).Methods("POST")
+
+	templates := s.router.PathPrefix("/templates").Subrouter()
+	templates.Use(s.authenticateUser)
+	templates.Use(s.requireScopes(model.ScopeNotesRead, model.ScopeNotesWrite))
+	templates.HandleFunc("", s.handleTemplatesCreate()).Methods("POST")
+	templates.HandleFunc("", s.handleTemplatesGetAll()).Methods("GET")
+	templates.HandleFunc("/{id:[0-9]+}", s.handleTemplatesGet()).Methods("GET")
+	templates.HandleFunc("/{id:[0-9]+}", s.handleTemplatesUpdate()).Methods("PATCH")
+	templates.HandleFunc("/{id:[0-9]+}", s.handleTemplatesDelete()).Methods("DELETE")
+
+	public := s.router.PathPrefix("/public").Subrouter()
+	public.HandleFunc("/notes/{token}", s.handlePublicNotesGet()).Methods("GET")
+
+	export := s.router.PathPrefix("/export").Subrouter()
+	export.Use(s.authenticateUser)
+	export.Use(s.requireScopes(model.ScopeNotesRead, model.ScopeNotesWrite))
+	export.HandleFunc("", s.handleExport()).Methods("GET")
+
+	imports := s.router.PathPrefix("/import").Subrouter()
+	imports.Use(s.authenticateUser)
+	imports.Use(s.requireScopes(model.ScopeNotesRead, model.ScopeNotesWrite))
+	imports.HandleFunc("", s.handleImportCreate()).Methods("POST")
+	imports.HandleFunc("/{job_id}", s.handleImportGet()).Methods("GET")
+
+	reminders := s.router.PathPrefix("/reminders").Subrouter()
+	reminders.Use(s.authenticateUser)
+	reminders.Use(s.requireScopes(model.ScopeNotesRead, model.ScopeNotesWrite))
+	reminders.HandleFunc("", s.handleRemindersUpcoming()).Methods("GET")
+
+	notifications := s.router.PathPrefix("/notifications").Subrouter()
+	notifications.Use(s.authenticateUser)
+	notifications.Use(s.requireScopes(model.ScopeNotesRead, model.ScopeNotesWrite))
+	notifications.HandleFunc("", s.handleNotificationsGetAll()).Methods("GET")
+	notifications.HandleFunc("/{id:[0-9]+}/read", s.handleNotificationsRead()).Methods("POST")
+
+	tags := s.router.PathPrefix("/tags").Subrouter()
+	tags.Use(s.authenticateUser)
+	tags.Use(s.requireScopes(model.ScopeNotesRead, model.ScopeNotesWrite))
+	tags.HandleFunc("", s.handleTagsGetAll()).Methods("GET")
 }
 
 func (s *server) setRequestID(next http.Handler) http.Handler {
@@ -130,6 +285,34 @@ func (s *server) logRequest(next http.Handler) http.Handler {
 
 func (s *server) authenticateUser(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token, ok := bearerToken(r); ok {
+			if strings.HasPrefix(token, model.APITokenPrefix) {
+				t, err := s.verifyAPIToken(token)
+				if err != nil && err != errInvalidAPIToken {
+					s.error(w, r, http.StatusInternalServerError, err)
+					return
+				}
+				if err != nil {
+					w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+					s.error(w, r, http.StatusUnauthorized, err)
+					return
+				}
+
+				s.serveUser(w, r.WithContext(context.WithValue(r.Context(), ctxKeyAPIToken, t)), next, t.UserID)
+				return
+			}
+
+			id, err := s.verifyAccessToken(token)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				s.error(w, r, http.StatusUnauthorized, err)
+				return
+			}
+
+			s.serveUser(w, r, next, id)
+			return
+		}
+
 		session, err := s.sessionStore.Get(r, sessionName)
 		if err != nil {
 			s.error(w, r, http.StatusInternalServerError, err)
@@ -142,16 +325,21 @@ func (s *server) authenticateUser(next http.Handler) http.Handler {
 			return
 		}
 
-		u, err := s.store.User().Find(id.(int))
-		if err != nil {
-			s.error(w, r, http.StatusUnauthorized, errNotAuthenticated)
-			return
-		}
-
-		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKeyUser, u)))
+		s.serveUser(w, r, next, id.(int))
 	})
 }
 
+// serveUser passes the request of the authenticated user to the handler
+func (s *server) serveUser(w http.ResponseWriter, r *http.Request, next http.Handler, id int) {
+	u, err := s.store.User().Find(id)
+	if err != nil {
+		s.error(w, r, http.StatusUnauthorized, errNotAuthenticated)
+		return
+	}
+
+	next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKeyUser, u)))
+}
+
 func (s *server) handleWhoami() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		s.respond(w, r, http.StatusOK, r.Context().Value(ctxKeyUser).(*model.User))
@@ -180,6 +368,12 @@ func (s *server) handleUsersCreate() http.HandlerFunc {
 			return
 		}
 
+		if s.mailer != nil {
+			if err := s.sendVerification(u); err != nil {
+				s.logger.Warnf("sending verification to user %d: %v", u.ID, err)
+			}
+		}
+
 		u.Sanitize()
 		s.respond(w, r, http.StatusCreated, u)
 	}
@@ -223,8 +417,10 @@ func (s *server) handleSessionCreate() http.HandlerFunc {
 
 func (s *server) handleNotesCreate() http.HandlerFunc {
 	type request struct {
-		Header string `json:"header"`
-		Body   string `json:"body"`
+		Header     string   `json:"header"`
+		Body       string   `json:"body"`
+		Tags       []string `json:"tags"`
+		NotebookID *int     `json:"notebook_id"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -236,11 +432,22 @@ func (s *server) handleNotesCreate() http.HandlerFunc {
 			return
 		}
 
+		if !s.allowNoteCreate(w, r, u) {
+			return
+		}
+
+		if err := s.checkNotebook(u, req.NotebookID); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
 		n := &model.Note{
-			Header:    req.Header,
-			Body:      req.Body,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+			NotebookID: req.NotebookID,
+			Header:     req.Header,
+			Body:       req.Body,
+			Tags:       model.NormalizeTags(req.Tags),
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
 		}
 
 		if err := s.store.Notes().Create(n, u); err != nil {
@@ -248,6 +455,11 @@ func (s *server) handleNotesCreate() http.HandlerFunc {
 			return
 		}
 
+		if err := s.store.Tags().SetNoteTags(n, n.Tags); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
 		s.respond(w, r, http.StatusCreated, n)
 	}
 }
@@ -255,122 +467,279 @@ func (s *server) handleNotesCreate() http.HandlerFunc {
 func (s *server) handleNotesUpdate() http.HandlerFunc {
 
 	type request struct {
-		Header string `json:"header"`
-		Body   string `json:"body"`
+		Header string    `json:"header"`
+		Body   string    `json:"body"`
+		Tags   *[]string `json:"tags"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		tmp, ok := mux.Vars(r)["id"]
-		if !ok {
-			s.error(w, r, http.StatusBadRequest, errIncorrectRequest)
-			return
-		}
-		id, err := strconv.Atoi(tmp)
-		if err != nil {
-			s.error(w, r, http.StatusInternalServerError, err)
-			return
-		}
-
-		u := r.Context().Value(ctxKeyUser).(*model.User)
-
 		req := &request{}
 		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
 			s.error(w, r, http.StatusBadRequest, err)
 			return
 		}
 
-		n, err := s.store.Notes().FindByID(id)
-		if err != nil {
-			s.error(w, r, http.StatusNotFound, store.ErrRecordNotFound)
+		n, ok := s.authorizeNote(w, r, model.RoleEditor)
+		if !ok {
 			return
 		}
-		if n.AuthorID != u.ID {
-			s.error(w, r, http.StatusNotFound, store.ErrRecordNotFound)
+		id := n.ID
+
+		version, ok := s.noteVersion(w, r, n)
+		if !ok {
 			return
 		}
 
 		un := &model.Note{
 			Header:    req.Header,
 			Body:      req.Body,
+			Version:   version,
 			UpdatedAt: time.Now(),
 		}
+		if req.Tags != nil {
+			un.Tags = model.NormalizeTags(*req.Tags)
+		}
 
 		if err := s.store.Notes().Update(id, un); err != nil {
+			if err == store.ErrVersionConflict {
+				s.error(w, r, http.StatusPreconditionFailed, err)
+				return
+			}
 			s.error(w, r, http.StatusUnprocessableEntity, err)
 			return
 		}
+		if err := s.pruneRevisions(id); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if req.Tags != nil {
+			if err := s.store.Tags().SetNoteTags(n, un.Tags); err != nil {
+				s.error(w, r, http.StatusUnprocessableEntity, err)
+				return
+			}
+		}
 
+		if n, err := s.store.Notes().FindByID(id); err == nil {
+			w.Header().Set("ETag", n.ETag())
+		}
 		s.respond(w, r, http.StatusOK, nil)
 	}
 }
 
 func (s *server) handleNotesDelete() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		tmp, ok := mux.Vars(r)["id"]
-		if !ok {
+		id, err := pathInt(r, "id")
+		if err != nil {
 			s.error(w, r, http.StatusBadRequest, errIncorrectRequest)
 			return
 		}
-		id, err := strconv.Atoi(tmp)
-		if err != nil {
-			s.error(w, r, http.StatusInternalServerError, err)
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		if r.URL.Query().Get("permanent") == "true" {
+			n, err := s.store.Notes().FindTrashedByID(id)
+			if err == nil && n.AuthorID == u.ID {
+				s.deleteNote(w, r, n.ID)
+				return
+			}
+		}
+
+		n, ok := s.authorizeNote(w, r, model.RoleOwner)
+		if !ok {
 			return
 		}
 
+		if r.URL.Query().Get("permanent") == "true" {
+			s.deleteNote(w, r, n.ID)
+			return
+		}
+
+		if err := s.store.Notes().Trash(n.ID); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+func (s *server) deleteNote(w http.ResponseWriter, r *http.Request, id int) {
+	al, err := s.store.Attachments().FindByNote(id)
+	if err != nil {
+		s.error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := s.store.Notes().Delete(id); err != nil {
+		s.error(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+	s.deleteBlobs(al)
+
+	s.respond(w, r, http.StatusOK, nil)
+}
+
+func (s *server) handleNotesGetTrash() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
 		u := r.Context().Value(ctxKeyUser).(*model.User)
 
-		n, err := s.store.Notes().FindByID(id)
+		nl, err := s.store.Notes().FindTrash(u)
 		if err != nil {
-			s.respond(w, r, http.StatusOK, nil)
+			s.error(w, r, http.StatusInternalServerError, err)
 			return
 		}
+		s.respond(w, r, http.StatusOK, nl)
+	}
+}
 
-		if n.AuthorID != u.ID {
-			s.respond(w, r, http.StatusOK, nil)
+func (s *server) handleNotesRestore() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathInt(r, "id")
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, errIncorrectRequest)
+			return
+		}
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		n, err := s.store.Notes().FindTrashedByID(id)
+		if err != nil || n.AuthorID != u.ID {
+			s.error(w, r, http.StatusNotFound, store.ErrRecordNotFound)
 			return
 		}
 
-		if err := s.store.Notes().Delete(id); err != nil {
+		if err := s.store.Notes().Restore(id); err != nil {
 			s.error(w, r, http.StatusUnprocessableEntity, err)
 			return
 		}
 
-		s.respond(w, r, http.StatusOK, nil)
+		n.DeletedAt = nil
+		s.respond(w, r, http.StatusOK, n)
+	}
+}
+
+// handleNotesSetFlag sets the flag of the note to the value,
+// flags are changed by the note author only
+func (s *server) handleNotesSetFlag(flag string, value bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.authorizeNote(w, r, model.RoleOwner)
+		if !ok {
+			return
+		}
+
+		if err := s.store.Notes().SetFlag(n.ID, flag, value); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		n, err := s.store.Notes().FindByID(n.ID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, n)
 	}
 }
 
 func (s *server) handleNotesGetAll() http.HandlerFunc {
+	type response struct {
+		Notes      []*model.Note `json:"notes"`
+		NextCursor string        `json:"next_cursor,omitempty"`
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		u := r.Context().Value(ctxKeyUser).(*model.User)
 
-		nl, err := s.store.Notes().FindByUser(u)
+		q, err := parseNoteQuery(r.URL.Query())
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		nl, next, err := s.store.Notes().FindPage(u, q)
 		if err != nil {
+			if err == store.ErrInvalidCursor {
+				s.error(w, r, http.StatusBadRequest, err)
+				return
+			}
 			s.error(w, r, http.StatusInternalServerError, err)
 			return
 		}
-		s.respond(w, r, http.StatusOK, nl)
+		s.respond(w, r, http.StatusOK, &response{
+			Notes:      nl,
+			NextCursor: next,
+		})
 	}
 }
 
-func (s *server) handleNotesGet() http.HandlerFunc {
+func (s *server) handleNotesSearch() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		tmp, ok := mux.Vars(r)["id"]
-		if !ok {
-			s.error(w, r, http.StatusBadRequest, errIncorrectRequest)
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		sq, err := store.ParseSearchQuery(r.URL.Query().Get("q"))
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
 			return
 		}
-		id, err := strconv.Atoi(tmp)
+		if limit := r.URL.Query().Get("limit"); limit != "" {
+			l, err := strconv.Atoi(limit)
+			if err != nil || l < 1 || l > store.MaxNoteLimit {
+				s.error(w, r, http.StatusBadRequest, errIncorrectLimit)
+				return
+			}
+			sq.Limit = l
+		}
+
+		res, err := s.store.Notes().Search(u, sq)
 		if err != nil {
 			s.error(w, r, http.StatusInternalServerError, err)
 			return
 		}
+		s.respond(w, r, http.StatusOK, res)
+	}
+}
+
+func (s *server) handleTagsGetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
 		u := r.Context().Value(ctxKeyUser).(*model.User)
 
-		n, err := s.store.Notes().FindByID(id)
-		if err != nil || n.AuthorID != u.ID {
-			s.error(w, r, http.StatusNotFound, store.ErrRecordNotFound)
+		tl, err := s.store.Tags().FindByUser(u)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, tl)
+	}
+}
+
+func (s *server) handleNotesGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get("format")
+		if format != "" && format != formatJSON && format != formatHTML {
+			s.error(w, r, http.StatusBadRequest, errIncorrectFormat)
+			return
+		}
+
+		n, ok := s.authorizeNote(w, r, model.RoleViewer)
+		if !ok {
 			return
 		}
+
+		w.Header().Set("ETag", n.ETag())
+		if etagMatches(r.Header.Get("If-None-Match"), n) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		if format == formatHTML {
+			html, err := s.renderNote(n)
+			if err != nil {
+				s.error(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			rn := *n
+			rn.HTML = html
+			n = &rn
+		}
 		s.respond(w, r, http.StatusOK, n)
 	}
 }
@@ -391,6 +760,136 @@ func (s *server) handleReadyCheck(isReady *atomic.Value) http.HandlerFunc {
 	}
 }
 
+// noteVersion writes an error response and returns false when If-Match
+// header doesn't allow the note to be changed, the version is passed
+// to the update to detect concurrent changes
+func (s *server) noteVersion(w http.ResponseWriter, r *http.Request, n *model.Note) (int, bool) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" && s.config.StrictConcurrency {
+		s.error(w, r, http.StatusPreconditionRequired, errIfMatchRequired)
+		return 0, false
+	}
+	version, ok := ifMatchVersion(ifMatch, n)
+	if !ok {
+		s.error(w, r, http.StatusPreconditionFailed, store.ErrVersionConflict)
+		return 0, false
+	}
+	return version, true
+}
+
+// ifMatchVersion returns the version the note must have to be updated
+// according to the If-Match header, zero means any version
+func ifMatchVersion(header string, n *model.Note) (int, bool) {
+	if header == "" || strings.TrimSpace(header) == "*" {
+		return 0, true
+	}
+	if etagMatches(header, n) {
+		return n.Version, true
+	}
+	return 0, false
+}
+
+// etagMatches reports whether the list of entity tags from
+// If-Match or If-None-Match header contains the note tag
+func etagMatches(header string, n *model.Note) bool {
+	for _, etag := range strings.Split(header, ",") {
+		if strings.TrimSpace(etag) == "*" {
+			return true
+		}
+		if v, err := model.ParseETag(etag); err == nil && v == n.Version {
+			return true
+		}
+	}
+	return false
+}
+
+func pathInt(r *http.Request, name string) (int, error) {
+	tmp, ok := mux.Vars(r)[name]
+	if !ok {
+		return 0, errIncorrectRequest
+	}
+	return strconv.Atoi(tmp)
+}
+
+func parseNoteQuery(v url.Values) (*store.NoteQuery, error) {
+	q := store.NewNoteQuery()
+	q.Tags = model.NormalizeTags(v["tag"])
+	if notebook := v.Get("notebook_id"); notebook != "" {
+		id, err := strconv.Atoi(notebook)
+		if err != nil || id < 1 {
+			return nil, errIncorrectNotebookID
+		}
+		q.NotebookID = id
+	}
+	switch v.Get("match") {
+	case "", "any":
+	case "all":
+		q.MatchAllTags = true
+	default:
+		return nil, errIncorrectTagMatch
+	}
+
+	if archived := v.Get("archived"); archived != "" {
+		q.Archived = archived
+	}
+	if starred := v.Get("starred"); starred != "" {
+		b, err := strconv.ParseBool(starred)
+		if err != nil {
+			return nil, errIncorrectStarred
+		}
+		q.StarredOnly = b
+	}
+	if pinnedFirst := v.Get("pinned_first"); pinnedFirst != "" {
+		b, err := strconv.ParseBool(pinnedFirst)
+		if err != nil {
+			return nil, errIncorrectPinnedFirst
+		}
+		q.PinnedFirst = b
+	}
+
+	if sort := v.Get("sort"); sort != "" {
+		q.Sort = sort
+	}
+	switch v.Get("order") {
+	case "":
+	case "asc":
+		q.Desc = false
+	case "desc":
+		q.Desc = true
+	default:
+		return nil, errIncorrectOrder
+	}
+
+	if limit := v.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return nil, errIncorrectLimit
+		}
+		q.Limit = l
+	}
+	q.Cursor = v.Get("cursor")
+
+	if after := v.Get("created_after"); after != "" {
+		t, err := time.Parse(time.RFC3339, after)
+		if err != nil {
+			return nil, err
+		}
+		q.CreatedAfter = t
+	}
+	if before := v.Get("updated_before"); before != "" {
+		t, err := time.Parse(time.RFC3339, before)
+		if err != nil {
+			return nil, err
+		}
+		q.UpdatedBefore = t
+	}
+
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
 func (s *server) error(w http.ResponseWriter, r *http.Request, code int, err error) {
 	s.respond(w, r, code, map[string]string{"error": err.Error()})
 }
@@ -7,14 +7,27 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
 	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
 	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
 	"github.com/stretchr/testify/assert"
 )
 
+func testServer(t *testing.T, st store.Store, blobStore store.BlobStore, sessionStore sessions.Store, config *Config) *server {
+	t.Helper()
+
+	s, err := newServer(st, blobStore, sessionStore, config)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return s
+}
+
 func TestServer_AuthenticateUser(t *testing.T) {
 	store := teststore.New()
 	u := model.TestUser(t)
@@ -40,7 +53,7 @@ func TestServer_AuthenticateUser(t *testing.T) {
 	}
 
 	secretKey := []byte("secret")
-	s := newServer(store, sessions.NewCookieStore(secretKey))
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
 	sc := securecookie.New(secretKey, nil)
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -59,7 +72,7 @@ func TestServer_AuthenticateUser(t *testing.T) {
 }
 
 func TestServer_HandleUserCreate(t *testing.T) {
-	s := newServer(teststore.New(), sessions.NewCookieStore([]byte("secret")))
+	s := testServer(t, teststore.New(), memstore.New(), sessions.NewCookieStore([]byte("secret")), NewConfig())
 	testCases := []struct {
 		name         string
 		payload      interface{}
@@ -103,7 +116,7 @@ func TestServer_HandleSessionCreate(t *testing.T) {
 	u := model.TestUser(t)
 	store := teststore.New()
 	store.User().Create(u)
-	s := newServer(store, sessions.NewCookieStore([]byte("secret")))
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore([]byte("secret")), NewConfig())
 	testCases := []struct {
 		name         string
 		payload      interface{}
@@ -153,7 +166,7 @@ func TestServer_HandleSessionCreate(t *testing.T) {
 }
 
 func TestServer_HandleNotesCreate(t *testing.T) {
-	s := newServer(teststore.New(), sessions.NewCookieStore([]byte("secret")))
+	s := testServer(t, teststore.New(), memstore.New(), sessions.NewCookieStore([]byte("secret")), NewConfig())
 	testCases := []struct {
 		name         string
 		payload      interface{}
@@ -192,3 +205,585 @@ func TestServer_HandleNotesCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_HandleNotesGetAll(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n1 := model.TestNote(t)
+	n2 := model.TestNote(t)
+	store.Notes().Create(n1, u)
+	store.Notes().Create(n2, u)
+	store.Tags().SetNoteTags(n1, []string{"work", "urgent"})
+	store.Tags().SetNoteTags(n2, []string{"work"})
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name          string
+		query         string
+		expectedCode  int
+		expectedCount int
+	}{
+		{
+			name:          "without tags",
+			query:         "",
+			expectedCode:  http.StatusOK,
+			expectedCount: 2,
+		},
+		{
+			name:          "any tag",
+			query:         "?tag=urgent&tag=home",
+			expectedCode:  http.StatusOK,
+			expectedCount: 1,
+		},
+		{
+			name:          "all tags",
+			query:         "?tag=work&tag=urgent&match=all",
+			expectedCode:  http.StatusOK,
+			expectedCount: 1,
+		},
+		{
+			name:         "invalid match",
+			query:        "?tag=work&match=some",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:          "limit",
+			query:         "?limit=1&sort=created_at&order=asc",
+			expectedCode:  http.StatusOK,
+			expectedCount: 1,
+		},
+		{
+			name:         "invalid sort",
+			query:        "?sort=body",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid limit",
+			query:        "?limit=1000",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid cursor",
+			query:        "?cursor=invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:          "created after",
+			query:         "?created_after=" + time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+			expectedCode:  http.StatusOK,
+			expectedCount: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/notes/"+tc.query, nil)
+			setSessionCookie(t, req, secretKey, u)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				res := struct {
+					Notes []*model.Note `json:"notes"`
+				}{}
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+				assert.Len(t, res.Notes, tc.expectedCount)
+			}
+		})
+	}
+}
+
+func TestServer_HandleNotesGetAllPagination(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	for i := 0; i < 5; i++ {
+		store.Notes().Create(model.TestNote(t), u)
+	}
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+
+	ids := []int{}
+	cursor := ""
+	for {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/notes/?limit=2&cursor="+cursor, nil)
+		setSessionCookie(t, req, secretKey, u)
+		s.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		res := struct {
+			Notes      []*model.Note `json:"notes"`
+			NextCursor string        `json:"next_cursor"`
+		}{}
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		for _, n := range res.Notes {
+			ids = append(ids, n.ID)
+		}
+		if res.NextCursor == "" {
+			break
+		}
+		cursor = res.NextCursor
+	}
+	assert.Equal(t, []int{5, 4, 3, 2, 1}, ids)
+}
+
+func TestServer_HandleNotesSearch(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n := model.TestNote(t)
+	n.Body = "Discuss the project plan"
+	store.Notes().Create(n, u)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name          string
+		query         string
+		expectedCode  int
+		expectedCount int
+	}{
+		{
+			name:          "found",
+			query:         "?q=proj*",
+			expectedCode:  http.StatusOK,
+			expectedCount: 1,
+		},
+		{
+			name:          "negation",
+			query:         "?q=plan+-project",
+			expectedCode:  http.StatusOK,
+			expectedCount: 0,
+		},
+		{
+			name:         "empty query",
+			query:        "",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid limit",
+			query:        "?q=plan&limit=0",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/notes/search"+tc.query, nil)
+			setSessionCookie(t, req, secretKey, u)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				res := []*model.SearchResult{}
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+				assert.Len(t, res, tc.expectedCount)
+			}
+		})
+	}
+}
+
+func TestServer_HandleTagsGetAll(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	store.Tags().SetNoteTags(n, []string{"work"})
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	tl := []*model.Tag{}
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&tl))
+	assert.Equal(t, []*model.Tag{{ID: 1, Name: "work", NoteCount: 1}}, tl)
+}
+
+func setSessionCookie(t *testing.T, req *http.Request, secretKey []byte, u *model.User) {
+	t.Helper()
+
+	sc := securecookie.New(secretKey, nil)
+	cookieStr, err := sc.Encode(sessionName, map[interface{}]interface{}{
+		"user_id": u.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Cookie", fmt.Sprintf("%s=%s", sessionName, cookieStr))
+}
+
+func TestServer_HandleNotesCreateWithTags(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			payload: map[string]interface{}{
+				"header": "header",
+				"body":   "body",
+				"tags":   []string{"work", "work", "urgent"},
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "invalid tag",
+			payload: map[string]interface{}{
+				"header": "header",
+				"body":   "body",
+				"tags":   []string{"tagtagtagtagtagtagtagtagtagtagtagtagtagtagtagtagtagtag"},
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req := httptest.NewRequest(http.MethodPost, "/notes/", b)
+			setSessionCookie(t, req, secretKey, u)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	tl, err := store.Tags().FindByUser(u)
+	assert.NoError(t, err)
+	assert.Len(t, tl, 2)
+}
+
+func TestServer_HandleNotesDelete(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	store.User().Create(other)
+
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	otherNote := model.TestNote(t)
+	store.Notes().Create(otherNote, other)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		expectedCode int
+	}{
+		{
+			name:         "other user note",
+			method:       http.MethodDelete,
+			path:         fmt.Sprintf("/notes/%d", otherNote.ID),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "restore not trashed",
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/notes/%d/restore", n.ID),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "trash",
+			method:       http.MethodDelete,
+			path:         fmt.Sprintf("/notes/%d", n.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "get trashed",
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/notes/%d", n.ID),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "trash again",
+			method:       http.MethodDelete,
+			path:         fmt.Sprintf("/notes/%d", n.ID),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "restore",
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/notes/%d/restore", n.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "get restored",
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/notes/%d", n.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "trash before permanent delete",
+			method:       http.MethodDelete,
+			path:         fmt.Sprintf("/notes/%d", n.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "permanent delete from trash",
+			method:       http.MethodDelete,
+			path:         fmt.Sprintf("/notes/%d?permanent=true", n.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "restore deleted",
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/notes/%d/restore", n.ID),
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			setSessionCookie(t, req, secretKey, u)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleNotesGetTrash(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	store.Notes().Create(model.TestNote(t), u)
+	store.Notes().Trash(n.ID)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/notes/trash", nil)
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	nl := []*model.Note{}
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&nl))
+	assert.Len(t, nl, 1)
+	assert.Equal(t, n.ID, nl[0].ID)
+}
+
+func TestServer_HandleNotesConditionalRequests(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+
+	secretKey := []byte("secret")
+	strictConfig := NewConfig()
+	strictConfig.StrictConcurrency = true
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	strict := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), strictConfig)
+	path := fmt.Sprintf("/notes/%d", n.ID)
+	testCases := []struct {
+		name         string
+		s            *server
+		method       string
+		header       string
+		value        string
+		expectedCode int
+		expectedETag string
+	}{
+		{
+			name:         "get",
+			s:            s,
+			method:       http.MethodGet,
+			expectedCode: http.StatusOK,
+			expectedETag: `"1"`,
+		},
+		{
+			name:         "get not modified",
+			s:            s,
+			method:       http.MethodGet,
+			header:       "If-None-Match",
+			value:        `"1"`,
+			expectedCode: http.StatusNotModified,
+			expectedETag: `"1"`,
+		},
+		{
+			name:         "update without If-Match in strict mode",
+			s:            strict,
+			method:       http.MethodPatch,
+			expectedCode: http.StatusPreconditionRequired,
+		},
+		{
+			name:         "update with stale If-Match",
+			s:            s,
+			method:       http.MethodPatch,
+			header:       "If-Match",
+			value:        `"5"`,
+			expectedCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:         "update with If-Match",
+			s:            strict,
+			method:       http.MethodPatch,
+			header:       "If-Match",
+			value:        `"1"`,
+			expectedCode: http.StatusOK,
+			expectedETag: `"2"`,
+		},
+		{
+			name:         "update without If-Match",
+			s:            s,
+			method:       http.MethodPatch,
+			expectedCode: http.StatusOK,
+			expectedETag: `"3"`,
+		},
+		{
+			name:         "get modified",
+			s:            s,
+			method:       http.MethodGet,
+			header:       "If-None-Match",
+			value:        `"1"`,
+			expectedCode: http.StatusOK,
+			expectedETag: `"3"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			body := &bytes.Buffer{}
+			if tc.method == http.MethodPatch {
+				json.NewEncoder(body).Encode(map[string]string{"body": tc.name})
+			}
+			req := httptest.NewRequest(tc.method, path, body)
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.value)
+			}
+			setSessionCookie(t, req, secretKey, u)
+			tc.s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			assert.Equal(t, tc.expectedETag, rec.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestServer_HandleNotesFlags(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	editor := model.TestUser(t)
+	editor.Email = "editor@example.org"
+	store.User().Create(editor)
+	n1 := model.TestNote(t)
+	n2 := model.TestNote(t)
+	n2.UpdatedAt = n1.UpdatedAt.Add(time.Minute)
+	n3 := model.TestNote(t)
+	store.Notes().Create(n1, u)
+	store.Notes().Create(n2, u)
+	store.Notes().Create(n3, u)
+	store.Shares().Save(&model.NoteShare{NoteID: n1.ID, UserID: editor.ID, Role: model.RoleEditor})
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	serve := func(user *model.User, method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		setSessionCookie(t, req, secretKey, user)
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, serve(u, http.MethodPost, fmt.Sprintf("/notes/%d/pin", n1.ID)).Code)
+	assert.Equal(t, http.StatusOK, serve(u, http.MethodPost, fmt.Sprintf("/notes/%d/archive", n3.ID)).Code)
+	assert.Equal(t, http.StatusOK, serve(u, http.MethodPost, fmt.Sprintf("/notes/%d/star", n2.ID)).Code)
+	assert.Equal(t, http.StatusForbidden, serve(editor, http.MethodPost, fmt.Sprintf("/notes/%d/star", n1.ID)).Code)
+
+	testCases := []struct {
+		name         string
+		query        string
+		expectedCode int
+		expectedIDs  []int
+	}{
+		{
+			name:         "default",
+			query:        "",
+			expectedCode: http.StatusOK,
+			expectedIDs:  []int{n1.ID, n2.ID},
+		},
+		{
+			name:         "without pinned first",
+			query:        "?pinned_first=false",
+			expectedCode: http.StatusOK,
+			expectedIDs:  []int{n2.ID, n1.ID},
+		},
+		{
+			name:         "only archived",
+			query:        "?archived=only",
+			expectedCode: http.StatusOK,
+			expectedIDs:  []int{n3.ID},
+		},
+		{
+			name:         "include archived",
+			query:        "?archived=include&sort=created_at&order=asc&pinned_first=false",
+			expectedCode: http.StatusOK,
+			expectedIDs:  []int{n1.ID, n2.ID, n3.ID},
+		},
+		{
+			name:         "starred",
+			query:        "?starred=true",
+			expectedCode: http.StatusOK,
+			expectedIDs:  []int{n2.ID},
+		},
+		{
+			name:         "invalid archived",
+			query:        "?archived=all",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid pinned first",
+			query:        "?pinned_first=maybe",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serve(u, http.MethodGet, "/notes/"+tc.query)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				res := struct {
+					Notes []*model.Note `json:"notes"`
+				}{}
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+				ids := []int{}
+				for _, n := range res.Notes {
+					ids = append(ids, n.ID)
+				}
+				assert.Equal(t, tc.expectedIDs, ids)
+			}
+		})
+	}
+
+	assert.Equal(t, http.StatusOK, serve(u, http.MethodDelete, fmt.Sprintf("/notes/%d/pin", n1.ID)).Code)
+	rn, _ := store.Notes().FindByID(n1.ID)
+	assert.False(t, rn.Pinned)
+}
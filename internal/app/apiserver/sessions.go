@@ -0,0 +1,104 @@
+package apiserver
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+func (s *server) handleSessionsGetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		session, err := s.sessionStore.Get(r, sessionName)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		sl, err := s.store.Sessions().FindByUser(u)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		for _, ss := range sl {
+			ss.Current = strconv.Itoa(ss.ID) == session.ID
+		}
+		s.respond(w, r, http.StatusOK, sl)
+	}
+}
+
+func (s *server) handleSessionsDeleteCurrent() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := s.endSession(w, r); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+func (s *server) handleSessionsDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathInt(r, "id")
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, errIncorrectRequest)
+			return
+		}
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		ss, err := s.store.Sessions().Find(id)
+		if err != nil {
+			if err == store.ErrRecordNotFound {
+				s.error(w, r, http.StatusNotFound, err)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if ss.UserID != u.ID {
+			s.error(w, r, http.StatusNotFound, store.ErrRecordNotFound)
+			return
+		}
+
+		if err := s.store.Sessions().Delete(id); err != nil && err != store.ErrRecordNotFound {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+func (s *server) handleSessionsDeleteAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		if _, err := s.store.Sessions().DeleteByUser(u.ID, 0); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if err := s.store.RefreshTokens().RevokeByUser(u.ID, time.Now()); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if err := s.endSession(w, r); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// endSession deletes the session of the request and expires its cookie
+func (s *server) endSession(w http.ResponseWriter, r *http.Request) error {
+	session, err := s.sessionStore.Get(r, sessionName)
+	if err != nil {
+		return err
+	}
+
+	session.Options.MaxAge = -1
+	return s.sessionStore.Save(r, w, session)
+}
@@ -0,0 +1,167 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/sessionstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/stretchr/testify/assert"
+)
+
+func loginCookie(t *testing.T, s *server, u *model.User) *http.Cookie {
+	t.Helper()
+
+	b := &bytes.Buffer{}
+	json.NewEncoder(b).Encode(map[string]string{
+		"email":    u.Email,
+		"password": u.Password,
+	})
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/sessions", b))
+	cookies := rec.Result().Cookies()
+	if !assert.Len(t, cookies, 1) {
+		t.FailNow()
+	}
+	return cookies[0]
+}
+
+func TestServer_HandleSessions(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	store.User().Create(other)
+
+	s := testServer(t, store, memstore.New(), sessionstore.New(store.Sessions(), time.Hour, []byte("secret")), NewConfig())
+	current := loginCookie(t, s, u)
+	second := loginCookie(t, s, u)
+	third := loginCookie(t, s, u)
+	otherCookie := loginCookie(t, s, other)
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		cookie       *http.Cookie
+		expectedCode int
+	}{
+		{
+			name:         "list",
+			method:       http.MethodGet,
+			path:         "/sessions",
+			cookie:       current,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "not authenticated",
+			method:       http.MethodGet,
+			path:         "/sessions",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "revoke session of another user",
+			method:       http.MethodDelete,
+			path:         "/sessions/4",
+			cookie:       current,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "revoke",
+			method:       http.MethodDelete,
+			path:         "/sessions/2",
+			cookie:       current,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "revoked",
+			method:       http.MethodGet,
+			path:         "/sessions",
+			cookie:       second,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "logout",
+			method:       http.MethodDelete,
+			path:         "/sessions/current",
+			cookie:       third,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "logged out",
+			method:       http.MethodGet,
+			path:         "/sessions",
+			cookie:       third,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "another user is logged in",
+			method:       http.MethodGet,
+			path:         "/sessions",
+			cookie:       otherCookie,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.cookie != nil {
+				req.AddCookie(tc.cookie)
+			}
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/sessions", nil)
+	req.AddCookie(current)
+	s.ServeHTTP(rec, req)
+	sl := []*model.Session{}
+	json.NewDecoder(rec.Body).Decode(&sl)
+	if assert.Len(t, sl, 1) {
+		assert.Equal(t, 1, sl[0].ID)
+		assert.True(t, sl[0].Current)
+	}
+}
+
+func TestServer_HandleSessionsDeleteAll(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+
+	s := testServer(t, store, memstore.New(), sessionstore.New(store.Sessions(), time.Hour, []byte("secret")), NewConfig())
+	cookies := []*http.Cookie{}
+	for i := 0; i < 3; i++ {
+		cookies = append(cookies, loginCookie(t, s, u))
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/sessions", nil)
+	req.AddCookie(cookies[0])
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.Len(t, rec.Result().Cookies(), 1) {
+		assert.Equal(t, -1, rec.Result().Cookies()[0].MaxAge)
+	}
+
+	for i, c := range cookies {
+		t.Run(fmt.Sprintf("session %d", i), func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/private/whoami", nil)
+			req.AddCookie(c)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		})
+	}
+}
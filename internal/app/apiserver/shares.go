@@ -0,0 +1,138 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+var (
+	errShareWithSelf = errors.New("note can't be shared with its author")
+	errUserNotFound  = errors.New("user not found")
+)
+
+func (s *server) handleSharesCreate() http.HandlerFunc {
+	type request struct {
+		Email string `json:"email"`
+		Role  string `json:"role"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		n, ok := s.authorizeNote(w, r, model.RoleOwner)
+		if !ok {
+			return
+		}
+		if !s.allowSharing(w, r, r.Context().Value(ctxKeyUser).(*model.User)) {
+			return
+		}
+
+		su, ok := s.shareUser(w, r, n, req.Email)
+		if !ok {
+			return
+		}
+
+		sh := &model.NoteShare{
+			NoteID: n.ID,
+			UserID: su.ID,
+			Email:  su.Email,
+			Role:   req.Role,
+		}
+		if err := s.store.Shares().Save(sh); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusCreated, sh)
+	}
+}
+
+func (s *server) handleSharesDelete() http.HandlerFunc {
+	type request struct {
+		Email string `json:"email"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		n, ok := s.authorizeNote(w, r, model.RoleOwner)
+		if !ok {
+			return
+		}
+
+		su, ok := s.shareUser(w, r, n, req.Email)
+		if !ok {
+			return
+		}
+
+		if err := s.store.Shares().Delete(n.ID, su.ID); err != nil {
+			if err == store.ErrRecordNotFound {
+				s.error(w, r, http.StatusNotFound, err)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+func (s *server) handleSharesGetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.authorizeNote(w, r, model.RoleOwner)
+		if !ok {
+			return
+		}
+
+		sl, err := s.store.Shares().FindByNote(n.ID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, sl)
+	}
+}
+
+func (s *server) handleNotesSharedWithMe() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		sl, err := s.store.Shares().FindByUser(u)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, sl)
+	}
+}
+
+// shareUser returns the user with the email to share the note with
+func (s *server) shareUser(w http.ResponseWriter, r *http.Request, n *model.Note, email string) (*model.User, bool) {
+	su, err := s.store.User().FindByEmail(email)
+	if err != nil {
+		if err == store.ErrRecordNotFound {
+			s.error(w, r, http.StatusUnprocessableEntity, errUserNotFound)
+			return nil, false
+		}
+		s.error(w, r, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	if su.ID == n.AuthorID {
+		s.error(w, r, http.StatusUnprocessableEntity, errShareWithSelf)
+		return nil, false
+	}
+	return su, true
+}
@@ -0,0 +1,198 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_HandleSharesCreate(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	store.User().Create(other)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name         string
+		user         *model.User
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			user: u,
+			payload: map[string]string{
+				"email": other.Email,
+				"role":  model.RoleViewer,
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "invalid role",
+			user: u,
+			payload: map[string]string{
+				"email": other.Email,
+				"role":  model.RoleOwner,
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "with self",
+			user: u,
+			payload: map[string]string{
+				"email": u.Email,
+				"role":  model.RoleEditor,
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "unknown user",
+			user: u,
+			payload: map[string]string{
+				"email": "unknown@example.org",
+				"role":  model.RoleEditor,
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "not owner",
+			user: other,
+			payload: map[string]string{
+				"email": other.Email,
+				"role":  model.RoleEditor,
+			},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/notes/%d/shares", n.ID), b)
+			setSessionCookie(t, req, secretKey, tc.user)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_NoteAuthorization(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	viewer := model.TestUser(t)
+	viewer.Email = "viewer@example.org"
+	store.User().Create(viewer)
+	editor := model.TestUser(t)
+	editor.Email = "editor@example.org"
+	store.User().Create(editor)
+	stranger := model.TestUser(t)
+	stranger.Email = "stranger@example.org"
+	store.User().Create(stranger)
+
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	store.Shares().Save(&model.NoteShare{NoteID: n.ID, UserID: viewer.ID, Role: model.RoleViewer})
+	store.Shares().Save(&model.NoteShare{NoteID: n.ID, UserID: editor.ID, Role: model.RoleEditor})
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name         string
+		user         *model.User
+		method       string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:         "viewer get",
+			user:         viewer,
+			method:       http.MethodGet,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "viewer update",
+			user:         viewer,
+			method:       http.MethodPatch,
+			payload:      map[string]string{"body": "changed"},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "editor update",
+			user:         editor,
+			method:       http.MethodPatch,
+			payload:      map[string]string{"body": "changed"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "editor delete",
+			user:         editor,
+			method:       http.MethodDelete,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "stranger get",
+			user:         stranger,
+			method:       http.MethodGet,
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			if tc.payload != nil {
+				json.NewEncoder(b).Encode(tc.payload)
+			}
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, fmt.Sprintf("/notes/%d", n.ID), b)
+			setSessionCookie(t, req, secretKey, tc.user)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleNotesSharedWithMe(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	store.User().Create(other)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+	store.Shares().Save(&model.NoteShare{NoteID: n.ID, UserID: other.ID, Role: model.RoleEditor})
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/notes/shared-with-me", nil)
+	setSessionCookie(t, req, secretKey, other)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	res := []*model.NoteShare{}
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	if assert.Len(t, res, 1) {
+		assert.Equal(t, model.RoleEditor, res[0].Role)
+		assert.Equal(t, n.ID, res[0].Note.ID)
+	}
+}
@@ -0,0 +1,216 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/notetemplate"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+func (s *server) handleTemplatesCreate() http.HandlerFunc {
+	type request struct {
+		Name   string `json:"name"`
+		Header string `json:"header"`
+		Body   string `json:"body"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		t := &model.Template{
+			UserID: u.ID,
+			Name:   req.Name,
+			Header: req.Header,
+			Body:   req.Body,
+		}
+		if err := s.store.Templates().Create(t); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusCreated, t)
+	}
+}
+
+func (s *server) handleTemplatesGetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		tl, err := s.store.Templates().FindByUser(u)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, tl)
+	}
+}
+
+func (s *server) handleTemplatesGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.userTemplate(w, r, "id")
+		if !ok {
+			return
+		}
+		s.respond(w, r, http.StatusOK, t)
+	}
+}
+
+func (s *server) handleTemplatesUpdate() http.HandlerFunc {
+	type request struct {
+		Name   *string `json:"name"`
+		Header *string `json:"header"`
+		Body   *string `json:"body"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		t, ok := s.userTemplate(w, r, "id")
+		if !ok {
+			return
+		}
+
+		ut := *t
+		if req.Name != nil {
+			ut.Name = *req.Name
+		}
+		if req.Header != nil {
+			ut.Header = *req.Header
+		}
+		if req.Body != nil {
+			ut.Body = *req.Body
+		}
+		if err := s.store.Templates().Update(&ut); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, &ut)
+	}
+}
+
+func (s *server) handleTemplatesDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.userTemplate(w, r, "id")
+		if !ok {
+			return
+		}
+
+		if err := s.store.Templates().Delete(t.ID); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// handleNotesFromTemplate creates a note with the expanded header and body
+// of the template, the note is validated as any other one
+func (s *server) handleNotesFromTemplate() http.HandlerFunc {
+	type request struct {
+		Vars       map[string]string `json:"vars"`
+		Tags       []string          `json:"tags"`
+		NotebookID *int              `json:"notebook_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		t, ok := s.userTemplate(w, r, "tid")
+		if !ok {
+			return
+		}
+
+		if err := s.checkNotebook(u, req.NotebookID); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		ctx := &notetemplate.Context{
+			Now:       time.Now(),
+			UserEmail: u.Email,
+			Vars:      req.Vars,
+		}
+		header, err := notetemplate.Execute(t.Header, ctx)
+		if err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+		body, err := notetemplate.Execute(t.Body, ctx)
+		if err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		if !s.allowNoteCreate(w, r, u) {
+			return
+		}
+
+		n := &model.Note{
+			NotebookID: req.NotebookID,
+			Header:     header,
+			Body:       body,
+			Tags:       model.NormalizeTags(req.Tags),
+			CreatedAt:  ctx.Now,
+			UpdatedAt:  ctx.Now,
+		}
+		if err := s.store.Notes().Create(n, u); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		if err := s.store.Tags().SetNoteTags(n, n.Tags); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusCreated, n)
+	}
+}
+
+// userTemplate returns the template with id from the request path variable
+// when it belongs to the user. Otherwise it writes an error response and
+// returns false
+func (s *server) userTemplate(w http.ResponseWriter, r *http.Request, name string) (*model.Template, bool) {
+	id, err := pathInt(r, name)
+	if err != nil {
+		s.error(w, r, http.StatusBadRequest, errIncorrectRequest)
+		return nil, false
+	}
+	u := r.Context().Value(ctxKeyUser).(*model.User)
+
+	t, err := s.store.Templates().Find(id)
+	if err != nil {
+		if err == store.ErrRecordNotFound {
+			s.error(w, r, http.StatusNotFound, err)
+			return nil, false
+		}
+		s.error(w, r, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	if t.UserID != u.ID {
+		s.error(w, r, http.StatusNotFound, store.ErrRecordNotFound)
+		return nil, false
+	}
+	return t, true
+}
@@ -0,0 +1,212 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_HandleTemplates(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	store.User().Create(other)
+
+	tmpl := model.TestTemplate(t)
+	tmpl.UserID = u.ID
+	store.Templates().Create(tmpl)
+	otherTemplate := model.TestTemplate(t)
+	otherTemplate.UserID = other.ID
+	store.Templates().Create(otherTemplate)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:   "create",
+			method: http.MethodPost,
+			path:   "/templates",
+			payload: map[string]interface{}{
+				"name":   "retro",
+				"header": "Retro {{date}}",
+				"body":   "Went well: {{.good}}",
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "create invalid template",
+			method: http.MethodPost,
+			path:   "/templates",
+			payload: map[string]interface{}{
+				"name":   "retro",
+				"header": "Retro {{date",
+				"body":   "body",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "get all",
+			method:       http.MethodGet,
+			path:         "/templates",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "get",
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/templates/%d", tmpl.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "get template of other user",
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/templates/%d", otherTemplate.ID),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "update",
+			method:       http.MethodPatch,
+			path:         fmt.Sprintf("/templates/%d", tmpl.ID),
+			payload:      map[string]interface{}{"name": "daily"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "update invalid",
+			method:       http.MethodPatch,
+			path:         fmt.Sprintf("/templates/%d", tmpl.ID),
+			payload:      map[string]interface{}{"body": ""},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "delete template of other user",
+			method:       http.MethodDelete,
+			path:         fmt.Sprintf("/templates/%d", otherTemplate.ID),
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			if tc.payload != nil {
+				json.NewEncoder(b).Encode(tc.payload)
+			}
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, b)
+			setSessionCookie(t, req, secretKey, u)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	rt, _ := store.Templates().Find(tmpl.ID)
+	assert.Equal(t, "daily", rt.Name)
+	assert.Equal(t, "Notes of {{user.email}}", rt.Body)
+	tl, _ := store.Templates().FindByUser(u)
+	assert.Len(t, tl, 2)
+}
+
+func TestServer_HandleNotesFromTemplate(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	store.User().Create(other)
+
+	tmpl := &model.Template{
+		UserID: u.ID,
+		Name:   "standup",
+		Header: "Standup {{date}} {{.team}}",
+		Body:   "Author: {{user.email}}",
+	}
+	store.Templates().Create(tmpl)
+	long := &model.Template{
+		UserID: u.ID,
+		Name:   "long",
+		Header: "Long",
+		Body:   strings.Repeat("{{.team}}", 300),
+	}
+	store.Templates().Create(long)
+	otherTemplate := model.TestTemplate(t)
+	otherTemplate.UserID = other.ID
+	store.Templates().Create(otherTemplate)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name           string
+		templateID     int
+		payload        interface{}
+		expectedCode   int
+		expectedHeader string
+	}{
+		{
+			name:       "valid",
+			templateID: tmpl.ID,
+			payload: map[string]interface{}{
+				"vars": map[string]string{"team": "core"},
+				"tags": []string{"standup"},
+			},
+			expectedCode:   http.StatusCreated,
+			expectedHeader: "Standup " + time.Now().Format("2006-01-02") + " core",
+		},
+		{
+			name:         "missing var",
+			templateID:   tmpl.ID,
+			payload:      map[string]interface{}{},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "invalid note",
+			templateID: long.ID,
+			payload: map[string]interface{}{
+				"vars": map[string]string{"team": "core"},
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "template of other user",
+			templateID:   otherTemplate.ID,
+			payload:      map[string]interface{}{},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/notes/from-template/%d", tc.templateID), b)
+			setSessionCookie(t, req, secretKey, u)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusCreated {
+				n := &model.Note{}
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(n))
+				assert.Equal(t, tc.expectedHeader, n.Header)
+				assert.Equal(t, "Author: "+u.Email, n.Body)
+				assert.Equal(t, []string{"standup"}, n.Tags)
+			}
+		})
+	}
+}
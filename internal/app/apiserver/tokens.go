@@ -0,0 +1,151 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/gorilla/mux"
+)
+
+// apiTokenTouchInterval limits how often the last use of a token is written
+const apiTokenTouchInterval = time.Minute
+
+var (
+	errInvalidAPIToken   = errors.New("invalid api token")
+	errInsufficientScope = errors.New("token scope is insufficient")
+	errScopeNotDelegable = errors.New("token can't grant scopes it doesn't have")
+)
+
+func (s *server) handleTokensCreate() http.HandlerFunc {
+	type request struct {
+		Name      string     `json:"name"`
+		Scopes    []string   `json:"scopes"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		// a token with the account scope can't create tokens with more scopes
+		if current, ok := r.Context().Value(ctxKeyAPIToken).(*model.APIToken); ok {
+			for _, scope := range req.Scopes {
+				if !current.HasScope(scope) {
+					s.error(w, r, http.StatusForbidden, errScopeNotDelegable)
+					return
+				}
+			}
+		}
+
+		t := &model.APIToken{
+			UserID:    u.ID,
+			Name:      req.Name,
+			Scopes:    req.Scopes,
+			ExpiresAt: req.ExpiresAt,
+		}
+		if err := s.store.APITokens().Create(t); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusCreated, t)
+	}
+}
+
+func (s *server) handleTokensGetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		tl, err := s.store.APITokens().FindByUser(u)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, tl)
+	}
+}
+
+func (s *server) handleTokensDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathInt(r, "id")
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, errIncorrectRequest)
+			return
+		}
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		t, err := s.store.APITokens().Find(id)
+		if err != nil {
+			if err == store.ErrRecordNotFound {
+				s.error(w, r, http.StatusNotFound, err)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if t.UserID != u.ID {
+			s.error(w, r, http.StatusNotFound, store.ErrRecordNotFound)
+			return
+		}
+
+		if err := s.store.APITokens().Delete(id); err != nil && err != store.ErrRecordNotFound {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// requireScopes limits requests authenticated with API tokens to the
+// scopes of the token: safe methods need the read scope, others need
+// the write scope. Sessions and access tokens aren't limited
+func (s *server) requireScopes(read, write string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if t, ok := r.Context().Value(ctxKeyAPIToken).(*model.APIToken); ok {
+				scope := write
+				switch r.Method {
+				case http.MethodGet, http.MethodHead, http.MethodOptions:
+					scope = read
+				}
+
+				if !t.HasScope(scope) {
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope="%s"`, scope))
+					s.error(w, r, http.StatusForbidden, errInsufficientScope)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// verifyAPIToken returns the token unless it's unknown or expired
+func (s *server) verifyAPIToken(token string) (*model.APIToken, error) {
+	t, err := s.store.APITokens().FindByToken(model.HashToken(token))
+	if err != nil {
+		if err == store.ErrRecordNotFound {
+			return nil, errInvalidAPIToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) >= apiTokenTouchInterval {
+		if err := s.store.APITokens().Touch(t.ID, now); err != nil {
+			s.logger.Warnf("touching api token %d: %v", t.ID, err)
+		}
+	}
+	return t, nil
+}
@@ -0,0 +1,240 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_HandleTokens(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	store.User().Create(other)
+
+	otherToken := model.TestAPIToken(t)
+	otherToken.UserID = other.ID
+	store.APITokens().Create(otherToken)
+	accountToken := model.TestAPIToken(t)
+	accountToken.UserID = u.ID
+	accountToken.Scopes = []string{model.ScopeAccount, model.ScopeNotesRead}
+	store.APITokens().Create(accountToken)
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		token        string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:   "create",
+			method: http.MethodPost,
+			path:   "/tokens",
+			payload: map[string]interface{}{
+				"name":   "backup",
+				"scopes": []string{"notes:read", "notes:write"},
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "unknown scope",
+			method: http.MethodPost,
+			path:   "/tokens",
+			payload: map[string]interface{}{
+				"name":   "backup",
+				"scopes": []string{"admin"},
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "create with a token",
+			method: http.MethodPost,
+			path:   "/tokens",
+			token:  accountToken.Token,
+			payload: map[string]interface{}{
+				"name":   "reader",
+				"scopes": []string{"notes:read"},
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "create with more scopes than the token",
+			method: http.MethodPost,
+			path:   "/tokens",
+			token:  accountToken.Token,
+			payload: map[string]interface{}{
+				"name":   "writer",
+				"scopes": []string{"notes:write"},
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "list",
+			method:       http.MethodGet,
+			path:         "/tokens",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "revoke token of another user",
+			method:       http.MethodDelete,
+			path:         "/tokens/1",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "revoke",
+			method:       http.MethodDelete,
+			path:         "/tokens/3",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "revoke unknown",
+			method:       http.MethodDelete,
+			path:         "/tokens/3",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req := httptest.NewRequest(tc.method, tc.path, b)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			} else {
+				setSessionCookie(t, req, secretKey, u)
+			}
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/tokens", nil)
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	tl := []map[string]interface{}{}
+	json.NewDecoder(rec.Body).Decode(&tl)
+	if assert.Len(t, tl, 2) {
+		assert.NotContains(t, tl[0], "token")
+		assert.Equal(t, "reader", tl[0]["name"])
+	}
+}
+
+func TestServer_RequireScopes(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	n := model.TestNote(t)
+	store.Notes().Create(n, u)
+
+	token := func(scopes ...string) string {
+		tok := model.TestAPIToken(t)
+		tok.UserID = u.ID
+		tok.Scopes = scopes
+		assert.NoError(t, store.APITokens().Create(tok))
+		return tok.Token
+	}
+	reader := token(model.ScopeNotesRead)
+	writer := token(model.ScopeNotesWrite)
+	account := token(model.ScopeAccount)
+
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore([]byte("secret")), NewConfig())
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		token        string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:         "read notes",
+			method:       http.MethodGet,
+			path:         "/notes/1",
+			token:        reader,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "write notes without scope",
+			method: http.MethodPatch,
+			path:   "/notes/1",
+			token:  reader,
+			payload: map[string]string{
+				"header": "updated",
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:   "write notes",
+			method: http.MethodPatch,
+			path:   "/notes/1",
+			token:  writer,
+			payload: map[string]string{
+				"header": "updated",
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "read tags without scope",
+			method:       http.MethodGet,
+			path:         "/tags",
+			token:        account,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "account without scope",
+			method:       http.MethodGet,
+			path:         "/private/whoami",
+			token:        reader,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "account",
+			method:       http.MethodGet,
+			path:         "/private/whoami",
+			token:        account,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "unknown token",
+			method:       http.MethodGet,
+			path:         "/notes/1",
+			token:        model.APITokenPrefix + "unknown",
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req := httptest.NewRequest(tc.method, tc.path, b)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	tl, err := store.APITokens().FindByUser(u)
+	assert.NoError(t, err)
+	for _, tok := range tl {
+		assert.NotNil(t, tok.LastUsedAt)
+	}
+}
@@ -0,0 +1,157 @@
+package apiserver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/mail"
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/gorilla/mux"
+)
+
+var (
+	errInvalidVerificationToken = errors.New("invalid or expired verification token")
+	errAlreadyVerified          = errors.New("email is already verified")
+	errVerificationThrottled    = errors.New("verification email was sent recently")
+	errEmailNotVerified         = errors.New("email is not verified")
+	errNoteLimitReached         = errors.New("note limit of unverified users is reached")
+)
+
+func (s *server) handleUsersVerify() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		v, err := s.store.EmailVerifications().FindByToken(model.HashToken(mux.Vars(r)["token"]))
+		if err != nil {
+			if err == store.ErrRecordNotFound {
+				s.error(w, r, http.StatusNotFound, errInvalidVerificationToken)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if err := s.store.User().MarkVerified(v.UserID, time.Now()); err != nil {
+			if err == store.ErrRecordNotFound {
+				s.error(w, r, http.StatusNotFound, errInvalidVerificationToken)
+				return
+			}
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if err := s.store.EmailVerifications().DeleteByUser(v.UserID); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// handleVerificationResend sends a new verification email, at most once
+// in VerificationResendSeconds
+func (s *server) handleVerificationResend() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*model.User)
+
+		if u.Verified() {
+			s.error(w, r, http.StatusConflict, errAlreadyVerified)
+			return
+		}
+		if s.mailer == nil {
+			s.error(w, r, http.StatusNotImplemented, errMailUnavailable)
+			return
+		}
+
+		v, err := s.store.EmailVerifications().FindLatest(u.ID)
+		if err != nil && err != store.ErrRecordNotFound {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if v != nil {
+			interval := time.Duration(s.config.VerificationResendSeconds) * time.Second
+			if wait := interval - time.Since(v.CreatedAt); wait > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+				s.error(w, r, http.StatusTooManyRequests, errVerificationThrottled)
+				return
+			}
+		}
+
+		if err := s.sendVerification(u); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		s.respond(w, r, http.StatusAccepted, nil)
+	}
+}
+
+// sendVerification emails the link confirming the email of the user
+func (s *server) sendVerification(u *model.User) error {
+	ttl := time.Duration(s.config.VerificationHours) * time.Hour
+	v := &model.EmailVerification{
+		UserID:    u.ID,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.store.EmailVerifications().Create(v); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(&mail.Message{
+		To:      u.Email,
+		Subject: "Confirm your email",
+		Body: fmt.Sprintf(
+			"To confirm your email, open %s/users/verify/%s within %d hours.\n\n"+
+				"If you didn't sign up, ignore this email.",
+			s.config.BaseURL,
+			v.Token,
+			s.config.VerificationHours,
+		),
+	})
+}
+
+// unverified reports whether restrictions of unverified users apply to the
+// user, they don't without email as there is no way to verify
+func (s *server) unverified(u *model.User) bool {
+	return s.mailer != nil && !u.Verified()
+}
+
+// checkNoteLimit returns errNoteLimitReached when an unverified user
+// can't create more notes
+func (s *server) checkNoteLimit(u *model.User) error {
+	if !s.unverified(u) || s.config.UnverifiedNoteLimit <= 0 {
+		return nil
+	}
+
+	count, err := s.store.Notes().CountByUser(u)
+	if err != nil {
+		return err
+	}
+	if count >= s.config.UnverifiedNoteLimit {
+		return errNoteLimitReached
+	}
+	return nil
+}
+
+// allowNoteCreate responds with 403 when the user can't create notes
+func (s *server) allowNoteCreate(w http.ResponseWriter, r *http.Request, u *model.User) bool {
+	if err := s.checkNoteLimit(u); err != nil {
+		if err == errNoteLimitReached {
+			s.error(w, r, http.StatusForbidden, err)
+			return false
+		}
+		s.error(w, r, http.StatusInternalServerError, err)
+		return false
+	}
+	return true
+}
+
+// allowSharing responds with 403 when the user can't share notes
+func (s *server) allowSharing(w http.ResponseWriter, r *http.Request, u *model.User) bool {
+	if !s.config.UnverifiedCanShare && s.unverified(u) {
+		s.error(w, r, http.StatusForbidden, errEmailNotVerified)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,211 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/mail"
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_HandleUsersVerify(t *testing.T) {
+	store := teststore.New()
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore([]byte("secret")), NewConfig())
+	mailer := mail.NewMemory()
+	s.mailer = mailer
+
+	rec := postJSON(s, "/users", map[string]string{
+		"email":    "user@example.org",
+		"password": "password",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	messages := mailer.Messages()
+	if !assert.Len(t, messages, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "user@example.org", messages[0].To)
+	m := regexp.MustCompile(`/users/verify/(\S+) `).FindStringSubmatch(messages[0].Body)
+	if !assert.Len(t, m, 2) {
+		t.FailNow()
+	}
+
+	testCases := []struct {
+		name         string
+		token        string
+		expectedCode int
+	}{
+		{
+			name:         "unknown token",
+			token:        "unknown",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "valid",
+			token:        m[1],
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "used token",
+			token:        m[1],
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/verify/"+tc.token, nil))
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	u, err := store.User().FindByEmail("user@example.org")
+	assert.NoError(t, err)
+	assert.True(t, u.Verified())
+}
+
+func TestServer_HandleVerificationResend(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	verified := model.TestUser(t)
+	verified.Email = "verified@example.org"
+	store.User().Create(verified)
+	store.User().MarkVerified(verified.ID, time.Now())
+
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), NewConfig())
+	mailer := mail.NewMemory()
+	s.mailer = mailer
+
+	testCases := []struct {
+		name         string
+		user         *model.User
+		expectedCode int
+	}{
+		{
+			name:         "valid",
+			user:         u,
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name:         "throttled",
+			user:         u,
+			expectedCode: http.StatusTooManyRequests,
+		},
+		{
+			name:         "already verified",
+			user:         verified,
+			expectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/account/verification", nil)
+			setSessionCookie(t, req, secretKey, tc.user)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusTooManyRequests {
+				assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+			}
+		})
+	}
+	assert.Len(t, mailer.Messages(), 1)
+}
+
+func TestServer_UnverifiedRestrictions(t *testing.T) {
+	store := teststore.New()
+	u := model.TestUser(t)
+	store.User().Create(u)
+	verified := model.TestUser(t)
+	verified.Email = "verified@example.org"
+	store.User().Create(verified)
+	store.User().MarkVerified(verified.ID, time.Now())
+
+	config := NewConfig()
+	config.UnverifiedNoteLimit = 1
+	config.UnverifiedCanShare = false
+	secretKey := []byte("secret")
+	s := testServer(t, store, memstore.New(), sessions.NewCookieStore(secretKey), config)
+	s.mailer = mail.NewMemory()
+
+	n := model.TestNote(t)
+	store.Notes().Create(n, verified)
+
+	testCases := []struct {
+		name         string
+		user         *model.User
+		path         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:         "first note",
+			user:         u,
+			path:         "/notes/",
+			payload:      map[string]string{"header": "first", "body": "text"},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "note limit",
+			user:         u,
+			path:         "/notes/",
+			payload:      map[string]string{"header": "second", "body": "text"},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "share",
+			user:         u,
+			path:         "/notes/2/shares",
+			payload:      map[string]string{"email": verified.Email, "role": model.RoleViewer},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "public link",
+			user:         u,
+			path:         "/notes/2/links",
+			payload:      map[string]string{},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "verified user",
+			user:         verified,
+			path:         "/notes/1/shares",
+			payload:      map[string]string{"email": u.Email, "role": model.RoleViewer},
+			expectedCode: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req := httptest.NewRequest(http.MethodPost, tc.path, b)
+			setSessionCookie(t, req, secretKey, tc.user)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	// restrictions don't apply when there is no way to verify the email
+	s.mailer = nil
+	rec := httptest.NewRecorder()
+	b := &bytes.Buffer{}
+	json.NewEncoder(b).Encode(map[string]string{"header": "second", "body": "text"})
+	req := httptest.NewRequest(http.MethodPost, "/notes/", b)
+	setSessionCookie(t, req, secretKey, u)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
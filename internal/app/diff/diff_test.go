@@ -0,0 +1,50 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/diff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnified(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a        string
+		b        string
+		expected string
+	}{
+		{
+			name:     "equal",
+			a:        "one\ntwo",
+			b:        "one\ntwo",
+			expected: "",
+		},
+		{
+			name:     "changed line",
+			a:        "one\ntwo\nthree",
+			b:        "one\n2\nthree",
+			expected: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n one\n-two\n+2\n three\n",
+		},
+		{
+			name:     "added lines",
+			a:        "",
+			b:        "one\ntwo",
+			expected: "--- a\n+++ b\n@@ -1 +1,2 @@\n-\n+one\n+two\n",
+		},
+		{
+			name: "separate hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12",
+			b:    "0\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n13",
+			expected: "--- a\n+++ b\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+0\n 2\n 3\n 4\n" +
+				"@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+13\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, diff.Unified("a", "b", tc.a, tc.b))
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+)
+
+const enexTimeLayout = "20060102T150405Z"
+
+type enexNote struct {
+	Title   string   `xml:"title"`
+	Content string   `xml:"content"`
+	Created string   `xml:"created"`
+	Updated string   `xml:"updated"`
+	Tags    []string `xml:"tag"`
+}
+
+// readENEX reads notes of the Evernote export one by one, so resources
+// of a single note are the most held in memory
+func readENEX(r io.Reader, fn func(*Item) error) error {
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+
+	for i := 0; ; {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "note" {
+			continue
+		}
+
+		i++
+		en := &enexNote{}
+		if err := dec.DecodeElement(en, &se); err != nil {
+			return err
+		}
+
+		it := &Item{Name: fmt.Sprintf("#%d", i)}
+		if en.Title != "" {
+			it.Name += " " + en.Title
+		}
+		it.Note, it.Err = en.note()
+		if err := fn(it); err != nil {
+			return err
+		}
+	}
+}
+
+func (en *enexNote) note() (*model.Note, error) {
+	body, err := enmlToMarkdown(en.Content)
+	if err != nil {
+		return nil, fmt.Errorf("content: %v", err)
+	}
+
+	n := &model.Note{
+		Header: strings.TrimSpace(en.Title),
+		Body:   body,
+		Tags:   en.Tags,
+	}
+	if en.Created != "" {
+		if n.CreatedAt, err = time.Parse(enexTimeLayout, en.Created); err != nil {
+			return nil, fmt.Errorf("created: %v", err)
+		}
+	}
+	if en.Updated != "" {
+		if n.UpdatedAt, err = time.Parse(enexTimeLayout, en.Updated); err != nil {
+			return nil, fmt.Errorf("updated: %v", err)
+		}
+	}
+	return n, nil
+}
+
+// enmlToMarkdown converts the note content in ENML, which is restricted
+// XHTML, to Markdown keeping paragraphs, headings, lists and checkboxes.
+// Other markup is dropped
+func enmlToMarkdown(content string) (string, error) {
+	dec := xml.NewDecoder(strings.NewReader(content))
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+
+	b := &strings.Builder{}
+	newLine := func() {
+		if s := b.String(); s != "" && !strings.HasSuffix(s, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	lists := []string{}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "div", "p":
+				newLine()
+			case "br":
+				b.WriteString("\n")
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				newLine()
+				b.WriteString(strings.Repeat("#", int(t.Name.Local[1]-'0')) + " ")
+			case "ul", "ol":
+				newLine()
+				lists = append(lists, t.Name.Local)
+			case "li":
+				newLine()
+				b.WriteString(strings.Repeat("  ", max(len(lists)-1, 0)))
+				if len(lists) > 0 && lists[len(lists)-1] == "ol" {
+					b.WriteString("1. ")
+				} else {
+					b.WriteString("- ")
+				}
+			case "en-todo":
+				mark := "[ ] "
+				for _, a := range t.Attr {
+					if a.Name.Local == "checked" && a.Value == "true" {
+						mark = "[x] "
+					}
+				}
+				if !strings.HasSuffix(b.String(), "- ") {
+					newLine()
+					b.WriteString("- ")
+				}
+				b.WriteString(mark)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "div", "p", "li", "h1", "h2", "h3", "h4", "h5", "h6":
+				newLine()
+			case "ul", "ol":
+				if len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+			}
+		case xml.CharData:
+			// formatting between block elements is not a part of the text
+			if strings.TrimSpace(string(t)) == "" && strings.Contains(string(t), "\n") {
+				continue
+			}
+			b.Write(t)
+		}
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
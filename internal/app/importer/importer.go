@@ -0,0 +1,225 @@
+// Package importer reads notes from Markdown archives, JSON exports
+// and Evernote ENEX files
+package importer
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/notefile"
+)
+
+// Formats of imported files
+const (
+	FormatZip  = "zip"
+	FormatJSON = "json"
+	FormatENEX = "enex"
+)
+
+// Limits of zip archives, the upload size only limits compressed data
+const (
+	// MaxFileSize is the size of a single note file, enough for
+	// the longest note body with front-matter
+	MaxFileSize = 64 << 10
+	// MaxArchiveSize is the total size of note files of the archive
+	MaxArchiveSize = 32 << 20
+	// MaxArchiveFiles is the number of entries of the archive
+	MaxArchiveFiles = 10000
+)
+
+var (
+	// ErrUnknownFormat ...
+	ErrUnknownFormat = errors.New("format must be zip, json or enex")
+	// ErrFileTooLarge ...
+	ErrFileTooLarge = fmt.Errorf("file is larger than %d bytes", MaxFileSize)
+	// ErrArchiveTooLarge ...
+	ErrArchiveTooLarge = fmt.Errorf("archive files are larger than %d bytes in total", MaxArchiveSize)
+	// ErrTooManyFiles ...
+	ErrTooManyFiles = fmt.Errorf("archive has more than %d files", MaxArchiveFiles)
+)
+
+// Item is a note read from the imported file, Name identifies the source
+// of the note in the report and Err is set when it can't be read
+type Item struct {
+	Name string
+	Note *model.Note
+	Err  error
+}
+
+// Format returns the format of the file by its extension
+func Format(name string) (string, error) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".zip":
+		return FormatZip, nil
+	case ".json", ".ndjson":
+		return FormatJSON, nil
+	case ".enex":
+		return FormatENEX, nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// Read calls fn for every note of the file in the format. Errors of single
+// notes are reported in items, Read fails only when the file is unreadable
+// or fn returns an error
+func Read(format string, r io.ReaderAt, size int64, fn func(*Item) error) error {
+	switch format {
+	case FormatZip:
+		return readZip(r, size, fn)
+	case FormatJSON:
+		return readJSON(io.NewSectionReader(r, 0, size), fn)
+	case FormatENEX:
+		return readENEX(io.NewSectionReader(r, 0, size), fn)
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// readZip reads .md files of the archive, other files are skipped.
+// Files larger than MaxFileSize are reported in items, the archive fails
+// when it has more than MaxArchiveFiles entries or its files decompress
+// to more than MaxArchiveSize
+func readZip(r io.ReaderAt, size int64, fn func(*Item) error) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	if len(zr.File) > MaxArchiveFiles {
+		return ErrTooManyFiles
+	}
+
+	var total int64
+	for _, f := range zr.File {
+		ext := strings.ToLower(path.Ext(f.Name))
+		if f.FileInfo().IsDir() || ext != ".md" && ext != ".markdown" {
+			continue
+		}
+
+		it := &Item{Name: f.Name}
+		b, err := readZipFile(f)
+		total += int64(len(b))
+		if total > MaxArchiveSize {
+			return ErrArchiveTooLarge
+		}
+		if it.Err = err; err == nil {
+			it.Note, it.Err = readNote(f, b)
+		}
+		if err := fn(it); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readZipFile reads at most MaxFileSize bytes of the file, the size in the
+// header isn't trusted as it's written by the client
+func readZipFile(f *zip.File) ([]byte, error) {
+	if f.UncompressedSize64 > MaxFileSize {
+		return nil, ErrFileTooLarge
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	b, err := ioutil.ReadAll(io.LimitReader(rc, MaxFileSize+1))
+	if err != nil {
+		return b, err
+	}
+	if len(b) > MaxFileSize {
+		return b, ErrFileTooLarge
+	}
+	return b, nil
+}
+
+func readNote(f *zip.File, b []byte) (*model.Note, error) {
+	n, err := notefile.Read(f.Name, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	if n.UpdatedAt.IsZero() {
+		n.UpdatedAt = f.Modified
+	}
+	return n, nil
+}
+
+// readJSON reads an array of notes as written by the JSON export
+// or notes one by one as written by the NDJSON export
+func readJSON(r io.Reader, fn func(*Item) error) error {
+	br := bufio.NewReader(r)
+	b, err := peek(br)
+	if err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+
+	dec := json.NewDecoder(br)
+	array := b == '['
+	if array {
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; dec.More(); i++ {
+		raw := json.RawMessage{}
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+
+		it := &Item{Name: fmt.Sprintf("#%d", i+1)}
+		n := &model.Note{}
+		if it.Err = json.Unmarshal(raw, n); it.Err == nil {
+			it.Note = &model.Note{
+				Header:    n.Header,
+				Body:      n.Body,
+				Tags:      n.Tags,
+				Pinned:    n.Pinned,
+				Archived:  n.Archived,
+				Starred:   n.Starred,
+				CreatedAt: n.CreatedAt,
+				UpdatedAt: n.UpdatedAt,
+			}
+		}
+		if err := fn(it); err != nil {
+			return err
+		}
+	}
+
+	if array {
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// peek returns the first non-space byte of the input
+func peek(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			br.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
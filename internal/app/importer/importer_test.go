@@ -0,0 +1,186 @@
+package importer_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/importer"
+	"github.com/stretchr/testify/assert"
+)
+
+func readAll(t *testing.T, format string, data []byte) []*importer.Item {
+	t.Helper()
+
+	items := []*importer.Item{}
+	assert.NoError(t, importer.Read(format, bytes.NewReader(data), int64(len(data)), func(it *importer.Item) error {
+		items = append(items, it)
+		return nil
+	}))
+	return items
+}
+
+func TestFormat(t *testing.T) {
+	f, err := importer.Format("notes.ZIP")
+	assert.NoError(t, err)
+	assert.Equal(t, importer.FormatZip, f)
+	f, err = importer.Format("export.enex")
+	assert.NoError(t, err)
+	assert.Equal(t, importer.FormatENEX, f)
+	_, err = importer.Format("notes.txt")
+	assert.EqualError(t, err, importer.ErrUnknownFormat.Error())
+}
+
+func TestRead_Zip(t *testing.T) {
+	b := &bytes.Buffer{}
+	zw := zip.NewWriter(b)
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"work/plan.md", "---\nheader: Plan\ntags: [work]\n---\nbody"},
+		{"readme.txt", "skipped"},
+		{"broken.md", "---\nheader: Broken"},
+	}
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		assert.NoError(t, err)
+		w.Write([]byte(f.content))
+	}
+	assert.NoError(t, zw.Close())
+
+	items := readAll(t, importer.FormatZip, b.Bytes())
+	assert.Len(t, items, 2)
+	assert.Equal(t, "work/plan.md", items[0].Name)
+	assert.NoError(t, items[0].Err)
+	assert.Equal(t, "Plan", items[0].Note.Header)
+	assert.Equal(t, []string{"work"}, items[0].Note.Tags)
+	assert.Equal(t, "broken.md", items[1].Name)
+	assert.Error(t, items[1].Err)
+}
+
+func TestRead_ZipLimits(t *testing.T) {
+	zipped := func(names []string, size int) []byte {
+		b := &bytes.Buffer{}
+		zw := zip.NewWriter(b)
+		content := bytes.Repeat([]byte("a"), size)
+		for _, name := range names {
+			w, err := zw.Create(name)
+			assert.NoError(t, err)
+			w.Write(content)
+		}
+		assert.NoError(t, zw.Close())
+		return b.Bytes()
+	}
+	read := func(data []byte) ([]*importer.Item, error) {
+		items := []*importer.Item{}
+		err := importer.Read(importer.FormatZip, bytes.NewReader(data), int64(len(data)), func(it *importer.Item) error {
+			items = append(items, it)
+			return nil
+		})
+		return items, err
+	}
+
+	// 10 MB of a single letter compress to a few kilobytes
+	bomb := zipped([]string{"bomb.md", "note.md"}, 10<<20)
+	assert.True(t, len(bomb) < 100<<10)
+	items, err := read(bomb)
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.EqualError(t, items[0].Err, importer.ErrFileTooLarge.Error())
+
+	names := []string{}
+	for i := 0; i <= importer.MaxArchiveSize/importer.MaxFileSize; i++ {
+		names = append(names, fmt.Sprintf("%d.md", i))
+	}
+	_, err = read(zipped(names, importer.MaxFileSize))
+	assert.EqualError(t, err, importer.ErrArchiveTooLarge.Error())
+
+	names = []string{}
+	for i := 0; i <= importer.MaxArchiveFiles; i++ {
+		names = append(names, fmt.Sprintf("%d.txt", i))
+	}
+	_, err = read(zipped(names, 0))
+	assert.EqualError(t, err, importer.ErrTooManyFiles.Error())
+}
+
+func TestRead_JSON(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "array",
+			data: ` [{"id": 5, "header": "first", "body": "a", "tags": ["work"], "pinned": true}, {"header": 1}]`,
+		},
+		{
+			name: "ndjson",
+			data: "{\"id\": 5, \"header\": \"first\", \"body\": \"a\", \"tags\": [\"work\"], \"pinned\": true}\n{\"header\": 1}\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			items := readAll(t, importer.FormatJSON, []byte(tc.data))
+			assert.Len(t, items, 2)
+			assert.NoError(t, items[0].Err)
+			assert.Equal(t, 0, items[0].Note.ID)
+			assert.Equal(t, "first", items[0].Note.Header)
+			assert.Equal(t, []string{"work"}, items[0].Note.Tags)
+			assert.True(t, items[0].Note.Pinned)
+			assert.Equal(t, "#2", items[1].Name)
+			assert.Error(t, items[1].Err)
+		})
+	}
+
+	assert.Len(t, readAll(t, importer.FormatJSON, []byte("[]")), 0)
+	assert.Error(t, importer.Read(importer.FormatJSON, strings.NewReader("[{"), 2, func(*importer.Item) error {
+		return nil
+	}))
+}
+
+func TestRead_ENEX(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE en-export SYSTEM "http://xml.evernote.com/pub/evernote-export3.dtd">
+<en-export>
+  <note>
+    <title>Groceries</title>
+    <content><![CDATA[<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE en-note SYSTEM "http://xml.evernote.com/pub/enml2.dtd">
+<en-note>
+<h1>Shop</h1>
+<div>Buy&nbsp;this:</div>
+<ul>
+<li>milk</li>
+<li><en-todo checked="true"/>bread</li>
+</ul>
+<div><en-todo/>pay</div>
+</en-note>]]></content>
+    <created>20210301T100000Z</created>
+    <updated>20210302T120000Z</updated>
+    <tag>home</tag>
+    <tag>shopping</tag>
+    <resource><data encoding="base64">aGVsbG8=</data></resource>
+  </note>
+  <note>
+    <title>Broken</title>
+    <content></content>
+    <created>yesterday</created>
+  </note>
+</en-export>`
+
+	items := readAll(t, importer.FormatENEX, []byte(data))
+	assert.Len(t, items, 2)
+	assert.NoError(t, items[0].Err)
+	n := items[0].Note
+	assert.Equal(t, "Groceries", n.Header)
+	assert.Equal(t, "# Shop\nBuy this:\n- milk\n- [x] bread\n- [ ] pay", n.Body)
+	assert.Equal(t, []string{"home", "shopping"}, n.Tags)
+	assert.Equal(t, time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC), n.CreatedAt)
+	assert.Equal(t, time.Date(2021, 3, 2, 12, 0, 0, 0, time.UTC), n.UpdatedAt)
+	assert.Equal(t, "#2 Broken", items[1].Name)
+	assert.Error(t, items[1].Err)
+}
@@ -0,0 +1,124 @@
+// Package jwt signs and verifies JSON Web Tokens (RFC 7519) with HS256,
+// the only algorithm the server needs for its access tokens
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const algorithm = "HS256"
+
+var (
+	// ErrInvalidToken is returned for malformed tokens, tokens with a wrong
+	// signature or an unknown key
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrExpiredToken ...
+	ErrExpiredToken = errors.New("token is expired")
+	// ErrUnknownKey is returned when the signing key isn't among the keys
+	ErrUnknownKey = errors.New("unknown signing key")
+)
+
+var encoding = base64.RawURLEncoding
+
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid"`
+}
+
+// Claims of access tokens, times are unix seconds
+type Claims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Signer signs tokens with HMAC-SHA256. Tokens carry the id of their key
+// in the kid header, so keys can be rotated: a new key signs tokens while
+// the previous ones still verify tokens issued before the rotation
+type Signer struct {
+	keys  map[string][]byte
+	keyID string
+}
+
+// NewSigner returns the signer of tokens with the key keyID
+func NewSigner(keys map[string][]byte, keyID string) (*Signer, error) {
+	if len(keys[keyID]) == 0 {
+		return nil, ErrUnknownKey
+	}
+
+	return &Signer{
+		keys:  keys,
+		keyID: keyID,
+	}, nil
+}
+
+// Sign ...
+func (s *Signer) Sign(c *Claims) (string, error) {
+	h, err := json.Marshal(&header{
+		Algorithm: algorithm,
+		Type:      "JWT",
+		KeyID:     s.keyID,
+	})
+	if err != nil {
+		return "", err
+	}
+	p, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := encoding.EncodeToString(h) + "." + encoding.EncodeToString(p)
+	return unsigned + "." + encoding.EncodeToString(sign(s.keys[s.keyID], unsigned)), nil
+}
+
+// Verify checks the signature and the expiration time of the token
+func (s *Signer) Verify(token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	h := &header{}
+	if err := decode(parts[0], h); err != nil {
+		return nil, ErrInvalidToken
+	}
+	key, ok := s.keys[h.KeyID]
+	if !ok || h.Algorithm != algorithm {
+		return nil, ErrInvalidToken
+	}
+
+	sig, err := encoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, sign(key, parts[0]+"."+parts[1])) {
+		return nil, ErrInvalidToken
+	}
+
+	c := &Claims{}
+	if err := decode(parts[1], c); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if now.Unix() >= c.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+	return c, nil
+}
+
+func sign(key []byte, s string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(s))
+	return mac.Sum(nil)
+}
+
+func decode(s string, v interface{}) error {
+	b, err := encoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,83 @@
+package jwt_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner(t *testing.T) {
+	now := time.Now()
+	old, err := jwt.NewSigner(map[string][]byte{"k1": []byte("old")}, "k1")
+	assert.NoError(t, err)
+	s, err := jwt.NewSigner(map[string][]byte{"k1": []byte("old"), "k2": []byte("new")}, "k2")
+	assert.NoError(t, err)
+	other, err := jwt.NewSigner(map[string][]byte{"k2": []byte("other")}, "k2")
+	assert.NoError(t, err)
+
+	claims := &jwt.Claims{Subject: "1", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Minute).Unix()}
+	sign := func(s *jwt.Signer, c *jwt.Claims) string {
+		token, err := s.Sign(c)
+		assert.NoError(t, err)
+		return token
+	}
+	token := sign(s, claims)
+
+	testCases := []struct {
+		name        string
+		token       string
+		expectedErr error
+	}{
+		{
+			name:  "valid",
+			token: token,
+		},
+		{
+			name:  "rotated key",
+			token: sign(old, claims),
+		},
+		{
+			name:        "another key",
+			token:       sign(other, claims),
+			expectedErr: jwt.ErrInvalidToken,
+		},
+		{
+			name:        "expired",
+			token:       sign(s, &jwt.Claims{Subject: "1", ExpiresAt: now.Unix()}),
+			expectedErr: jwt.ErrExpiredToken,
+		},
+		{
+			name:        "tampered payload",
+			token:       strings.Join([]string{strings.Split(token, ".")[0], strings.Split(sign(s, &jwt.Claims{Subject: "2", ExpiresAt: claims.ExpiresAt}), ".")[1], strings.Split(token, ".")[2]}, "."),
+			expectedErr: jwt.ErrInvalidToken,
+		},
+		{
+			name:        "malformed",
+			token:       "a.b",
+			expectedErr: jwt.ErrInvalidToken,
+		},
+		{
+			name:        "unsigned",
+			token:       "eyJhbGciOiJub25lIiwidHlwIjoiSldUIiwia2lkIjoiazIifQ.eyJzdWIiOiIxIiwiZXhwIjo0MTAyNDQ0ODAwfQ.",
+			expectedErr: jwt.ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := s.Verify(tc.token, now)
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, claims, c)
+		})
+	}
+
+	_, err = jwt.NewSigner(map[string][]byte{"k1": []byte("old")}, "k2")
+	assert.EqualError(t, err, jwt.ErrUnknownKey.Error())
+}
@@ -0,0 +1,126 @@
+// Package mail sends plain text emails through an SMTP server or keeps
+// them locally for development and tests
+package mail
+
+import (
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender ...
+type Sender interface {
+	Send(*Message) error
+}
+
+var headerReplacer = strings.NewReplacer("\r", " ", "\n", " ")
+
+// Bytes formats the message with the headers, line breaks are removed
+// from header values so they can't add headers
+func (m *Message) Bytes(from string) []byte {
+	return []byte("From: " + headerReplacer.Replace(from) + "\r\n" +
+		"To: " + headerReplacer.Replace(m.To) + "\r\n" +
+		"Subject: " + headerReplacer.Replace(m.Subject) + "\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" +
+		m.Body + "\r\n")
+}
+
+// SMTP sends messages through the SMTP server
+type SMTP struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTP authenticates on the server only if the username is set
+func NewSMTP(addr, username, password, from string) *SMTP {
+	s := &SMTP{
+		addr: addr,
+		from: from,
+	}
+	if username != "" {
+		host := strings.Split(addr, ":")[0]
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return s
+}
+
+// Send ...
+func (s *SMTP) Send(m *Message) error {
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{m.To}, m.Bytes(s.from))
+}
+
+// Memory keeps sent messages in memory
+type Memory struct {
+	mu       sync.Mutex
+	messages []*Message
+}
+
+// NewMemory ...
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Send ...
+func (s *Memory) Send(m *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := *m
+	s.messages = append(s.messages, &c)
+	return nil
+}
+
+// Messages returns sent messages in order of sending
+func (s *Memory) Messages() []*Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]*Message, len(s.messages))
+	copy(result, s.messages)
+	return result
+}
+
+// File appends messages to the file, so they can be read during development
+// without a mail server
+type File struct {
+	mu   sync.Mutex
+	path string
+	from string
+}
+
+// NewFile ...
+func NewFile(path, from string) *File {
+	return &File{
+		path: path,
+		from: from,
+	}
+}
+
+// Send ...
+func (s *File) Send(m *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(f, "Date: %s\r\n%s\r\n", time.Now().Format(time.RFC1123Z), m.Bytes(s.from)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,56 @@
+package mail_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/mail"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage_Bytes(t *testing.T) {
+	m := &mail.Message{
+		To:      "user@example.org",
+		Subject: "Reset\r\nBcc: evil@example.org",
+		Body:    "line 1\nline 2",
+	}
+
+	b := string(m.Bytes("notes@localhost"))
+	assert.True(t, strings.HasPrefix(b, "From: notes@localhost\r\nTo: user@example.org\r\n"))
+	assert.Contains(t, b, "Subject: Reset  Bcc: evil@example.org\r\n")
+	assert.NotContains(t, b, "\r\nBcc:")
+	assert.True(t, strings.HasSuffix(b, "\r\n\r\nline 1\nline 2\r\n"))
+}
+
+func TestMemory(t *testing.T) {
+	s := mail.NewMemory()
+	m := &mail.Message{To: "user@example.org", Subject: "Hi", Body: "text"}
+	assert.NoError(t, s.Send(m))
+	m.Body = "changed"
+
+	messages := s.Messages()
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, "text", messages[0].Body)
+	}
+}
+
+func TestFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mail.log")
+
+	s := mail.NewFile(path, "notes@localhost")
+	assert.NoError(t, s.Send(&mail.Message{To: "a@example.org", Subject: "First", Body: "one"}))
+	assert.NoError(t, s.Send(&mail.Message{To: "b@example.org", Subject: "Second", Body: "two"}))
+
+	b, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(b), "From: notes@localhost"))
+	assert.Contains(t, string(b), "Subject: Second")
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Scopes of API tokens
+const (
+	// ScopeNotesRead allows reading notes, notebooks, templates and tags
+	ScopeNotesRead = "notes:read"
+	// ScopeNotesWrite allows changing them
+	ScopeNotesWrite = "notes:write"
+	// ScopeAccount allows managing sessions and tokens of the user
+	ScopeAccount = "account"
+)
+
+// APITokenPrefix tells API tokens from access tokens in the Authorization header
+const APITokenPrefix = "pat_"
+
+const apiTokenSize = 32
+
+// APIToken is a long-lived personal access token for scripts and
+// integrations. The token is returned only on creation, its hash is stored
+type APIToken struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"-"`
+	Name       string     `json:"name"`
+	Token      string     `json:"token,omitempty"`
+	TokenHash  string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Validate ...
+func (t *APIToken) Validate() error {
+	return validation.ValidateStruct(
+		t,
+		validation.Field(&t.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&t.Scopes, validation.Required, validation.Each(validation.In(ScopeNotesRead, ScopeNotesWrite, ScopeAccount))),
+		validation.Field(&t.ExpiresAt, validation.By(func(interface{}) error {
+			if t.ExpiresAt != nil && !t.ExpiresAt.After(time.Now()) {
+				return errors.New("must be in the future")
+			}
+			return nil
+		})),
+	)
+}
+
+// BeforeCreate generates the token and removes duplicate scopes
+func (t *APIToken) BeforeCreate() error {
+	token, err := randomToken(apiTokenSize)
+	if err != nil {
+		return err
+	}
+	t.Token = APITokenPrefix + token
+	t.TokenHash = HashToken(t.Token)
+
+	seen := make(map[string]bool)
+	scopes := []string{}
+	for _, s := range t.Scopes {
+		if !seen[s] {
+			seen[s] = true
+			scopes = append(scopes, s)
+		}
+	}
+	sort.Strings(scopes)
+	t.Scopes = scopes
+	return nil
+}
+
+// HasScope ...
+func (t *APIToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
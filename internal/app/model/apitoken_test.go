@@ -0,0 +1,93 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIToken_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		token   func() *model.APIToken
+		isValid bool
+	}{
+		{
+			name: "valid",
+			token: func() *model.APIToken {
+				return model.TestAPIToken(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "no name",
+			token: func() *model.APIToken {
+				tok := model.TestAPIToken(t)
+				tok.Name = ""
+				return tok
+			},
+			isValid: false,
+		},
+		{
+			name: "no scopes",
+			token: func() *model.APIToken {
+				tok := model.TestAPIToken(t)
+				tok.Scopes = nil
+				return tok
+			},
+			isValid: false,
+		},
+		{
+			name: "unknown scope",
+			token: func() *model.APIToken {
+				tok := model.TestAPIToken(t)
+				tok.Scopes = []string{model.ScopeNotesRead, "admin"}
+				return tok
+			},
+			isValid: false,
+		},
+		{
+			name: "expires in the future",
+			token: func() *model.APIToken {
+				tok := model.TestAPIToken(t)
+				at := time.Now().Add(time.Hour)
+				tok.ExpiresAt = &at
+				return tok
+			},
+			isValid: true,
+		},
+		{
+			name: "expired",
+			token: func() *model.APIToken {
+				tok := model.TestAPIToken(t)
+				at := time.Now().Add(-time.Hour)
+				tok.ExpiresAt = &at
+				return tok
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.isValid {
+				assert.NoError(t, tc.token().Validate())
+			} else {
+				assert.Error(t, tc.token().Validate())
+			}
+		})
+	}
+}
+
+func TestAPIToken_BeforeCreate(t *testing.T) {
+	tok := model.TestAPIToken(t)
+	tok.Scopes = []string{model.ScopeNotesWrite, model.ScopeNotesRead, model.ScopeNotesWrite}
+	assert.NoError(t, tok.BeforeCreate())
+	assert.Regexp(t, "^pat_", tok.Token)
+	assert.Equal(t, model.HashToken(tok.Token), tok.TokenHash)
+	assert.Equal(t, []string{model.ScopeNotesRead, model.ScopeNotesWrite}, tok.Scopes)
+	assert.True(t, tok.HasScope(model.ScopeNotesWrite))
+	assert.False(t, tok.HasScope(model.ScopeAccount))
+}
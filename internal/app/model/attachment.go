@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Attachment describes a file attached to a note,
+// the file content is kept in a blob store by the storage key
+type Attachment struct {
+	ID          int       `json:"id"`
+	NoteID      int       `json:"note_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Validate ...
+func (a *Attachment) Validate() error {
+	return validation.ValidateStruct(
+		a,
+		validation.Field(&a.Filename, validation.Required, validation.Length(1, 255)),
+		validation.Field(&a.ContentType, validation.Required),
+		validation.Field(&a.Size, validation.Min(0)),
+		validation.Field(&a.StorageKey, validation.Required),
+	)
+}
@@ -0,0 +1,69 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachment_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		a       func() *model.Attachment
+		isValid bool
+	}{
+		{
+			name: "valid",
+			a: func() *model.Attachment {
+				return model.TestAttachment(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "empty filename",
+			a: func() *model.Attachment {
+				a := model.TestAttachment(t)
+				a.Filename = ""
+				return a
+			},
+			isValid: false,
+		},
+		{
+			name: "long filename",
+			a: func() *model.Attachment {
+				a := model.TestAttachment(t)
+				a.Filename = strings.Repeat("a", 256)
+				return a
+			},
+			isValid: false,
+		},
+		{
+			name: "empty content type",
+			a: func() *model.Attachment {
+				a := model.TestAttachment(t)
+				a.ContentType = ""
+				return a
+			},
+			isValid: false,
+		},
+		{
+			name: "without storage key",
+			a: func() *model.Attachment {
+				a := model.TestAttachment(t)
+				a.StorageKey = ""
+				return a
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.isValid {
+			assert.NoError(t, tc.a().Validate())
+		} else {
+			assert.Error(t, tc.a().Validate())
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// ChecklistItem is a to-do item of a note, items of the note
+// are ordered by position starting from 1
+type ChecklistItem struct {
+	ID        int       `json:"id"`
+	NoteID    int       `json:"note_id"`
+	Text      string    `json:"text"`
+	Done      bool      `json:"done"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate ...
+func (i *ChecklistItem) Validate() error {
+	return validation.ValidateStruct(
+		i,
+		validation.Field(&i.Text, validation.Required, validation.Length(1, 1000)),
+	)
+}
+
+// Completion returns the percentage of done items rounded down,
+// nil when there are no items
+func Completion(items []*ChecklistItem) *int {
+	if len(items) == 0 {
+		return nil
+	}
+
+	done := 0
+	for _, i := range items {
+		if i.Done {
+			done++
+		}
+	}
+	c := 100 * done / len(items)
+	return &c
+}
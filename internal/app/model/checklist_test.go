@@ -0,0 +1,62 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecklistItem_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		item    func() *model.ChecklistItem
+		isValid bool
+	}{
+		{
+			name: "valid",
+			item: func() *model.ChecklistItem {
+				return model.TestChecklistItem(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "empty text",
+			item: func() *model.ChecklistItem {
+				i := model.TestChecklistItem(t)
+				i.Text = ""
+				return i
+			},
+			isValid: false,
+		},
+		{
+			name: "long text",
+			item: func() *model.ChecklistItem {
+				i := model.TestChecklistItem(t)
+				i.Text = strings.Repeat("a", 1001)
+				return i
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.isValid {
+				assert.NoError(t, tc.item().Validate())
+			} else {
+				assert.Error(t, tc.item().Validate())
+			}
+		})
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	assert.Nil(t, model.Completion(nil))
+
+	items := []*model.ChecklistItem{{Done: true}, {}, {}}
+	if c := model.Completion(items); assert.NotNil(t, c) {
+		assert.Equal(t, 33, *c)
+	}
+}
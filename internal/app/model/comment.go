@@ -0,0 +1,48 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+var mentionRe = regexp.MustCompile(`(?:^|[^\w.@])@([\w.%+-]+@[\w-]+(?:\.[\w-]+)+)`)
+
+// Comment is a message about a note, replies refer to the comment
+// they answer by ParentID
+type Comment struct {
+	ID          int       `json:"id"`
+	NoteID      int       `json:"note_id"`
+	AuthorID    int       `json:"author_id"`
+	AuthorEmail string    `json:"author_email,omitempty"`
+	ParentID    *int      `json:"parent_id"`
+	Body        string    `json:"body"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Validate ...
+func (c *Comment) Validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Body, validation.Required, validation.Length(1, 5000)),
+	)
+}
+
+// Mentions returns distinct lower case emails mentioned in the body
+// as @user@example.org in order of appearance
+func (c *Comment) Mentions() []string {
+	result := []string{}
+	seen := make(map[string]bool)
+	for _, m := range mentionRe.FindAllStringSubmatch(c.Body, -1) {
+		email := strings.ToLower(strings.TrimRight(m[1], "."))
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+		result = append(result, email)
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComment_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		c       func() *model.Comment
+		isValid bool
+	}{
+		{
+			name: "valid",
+			c: func() *model.Comment {
+				return model.TestComment(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "empty body",
+			c: func() *model.Comment {
+				c := model.TestComment(t)
+				c.Body = ""
+				return c
+			},
+			isValid: false,
+		},
+		{
+			name: "long body",
+			c: func() *model.Comment {
+				c := model.TestComment(t)
+				c.Body = strings.Repeat("a", 5001)
+				return c
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.isValid {
+				assert.NoError(t, tc.c().Validate())
+			} else {
+				assert.Error(t, tc.c().Validate())
+			}
+		})
+	}
+}
+
+func TestComment_Mentions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		expected []string
+	}{
+		{
+			name:     "none",
+			body:     "write to user@example.org",
+			expected: []string{},
+		},
+		{
+			name:     "mentions",
+			body:     "@Alice@example.org and @bob@example.org., again @alice@example.org",
+			expected: []string{"alice@example.org", "bob@example.org"},
+		},
+		{
+			name:     "in brackets",
+			body:     "(cc @bob@example.co.uk)",
+			expected: []string{"bob@example.co.uk"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := model.TestComment(t)
+			c.Body = tc.body
+			assert.Equal(t, tc.expected, c.Mentions())
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+const emailVerificationTokenSize = 32
+
+// EmailVerification confirms that the user owns the email, the token
+// is sent in the verification link and only its hash is stored
+type EmailVerification struct {
+	ID        int
+	UserID    int
+	Token     string
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// BeforeCreate generates the token of the verification
+func (v *EmailVerification) BeforeCreate() error {
+	token, err := randomToken(emailVerificationTokenSize)
+	if err != nil {
+		return err
+	}
+	v.Token = token
+	v.TokenHash = HashToken(token)
+	return nil
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Statuses of import jobs and their files
+const (
+	ImportJobRunning = "running"
+	ImportJobDone    = "done"
+	ImportJobFailed  = "failed"
+
+	ImportFileImported = "imported"
+	ImportFileFailed   = "failed"
+)
+
+// ImportJob is a background import of notes from a file,
+// Files report the result of every note read from it. HeartbeatAt is
+// updated while the job runs, a stale one means the server running it
+// has stopped
+type ImportJob struct {
+	ID          string              `json:"id"`
+	UserID      int                 `json:"-"`
+	Format      string              `json:"format"`
+	Status      string              `json:"status"`
+	Imported    int                 `json:"imported"`
+	Failed      int                 `json:"failed"`
+	Files       []*ImportFileResult `json:"files"`
+	Error       string              `json:"error,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	FinishedAt  *time.Time          `json:"finished_at,omitempty"`
+	HeartbeatAt time.Time           `json:"-"`
+}
+
+// ImportFileResult ...
+type ImportFileResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	NoteID int    `json:"note_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const linkTokenSize = 24
+
+// NoteLink publishes a note for reading by anyone who knows its token
+type NoteLink struct {
+	Token             string     `json:"token"`
+	NoteID            int        `json:"note_id"`
+	Password          string     `json:"password,omitempty"`
+	EncryptedPassword string     `json:"-"`
+	Protected         bool       `json:"protected"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	Views             int        `json:"views"`
+	FailedAttempts    int        `json:"-"`
+	LockedUntil       *time.Time `json:"-"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// Validate ...
+func (l *NoteLink) Validate() error {
+	return validation.ValidateStruct(
+		l,
+		validation.Field(&l.Password, validation.Length(6, 100)),
+		validation.Field(&l.ExpiresAt, validation.By(func(interface{}) error {
+			if l.ExpiresAt != nil && !l.ExpiresAt.After(time.Now()) {
+				return errors.New("must be in the future")
+			}
+			return nil
+		})),
+	)
+}
+
+// BeforeCreate generates the token and encrypts the password of the link
+func (l *NoteLink) BeforeCreate() error {
+	token, err := randomToken(linkTokenSize)
+	if err != nil {
+		return err
+	}
+	l.Token = token
+
+	if len(l.Password) > 0 {
+		enc, err := encryptString(l.Password)
+		if err != nil {
+			return err
+		}
+
+		l.EncryptedPassword = enc
+	}
+	return nil
+}
+
+// Sanitize ...
+func (l *NoteLink) Sanitize() {
+	l.Password = ""
+	l.Protected = l.EncryptedPassword != ""
+}
+
+// ComparePassword reports whether the password opens the link,
+// links without password are opened by any
+func (l *NoteLink) ComparePassword(password string) bool {
+	if l.EncryptedPassword == "" {
+		return true
+	}
+	return bcrypt.CompareHashAndPassword([]byte(l.EncryptedPassword), []byte(password)) == nil
+}
+
+// Expired ...
+func (l *NoteLink) Expired(now time.Time) bool {
+	return l.ExpiresAt != nil && !l.ExpiresAt.After(now)
+}
+
+// Locked reports whether the link refuses passwords after too many failed attempts
+func (l *NoteLink) Locked(now time.Time) bool {
+	return l.LockedUntil != nil && l.LockedUntil.After(now)
+}
+
+func randomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,101 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoteLink_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		l       func() *model.NoteLink
+		isValid bool
+	}{
+		{
+			name: "valid",
+			l: func() *model.NoteLink {
+				return model.TestNoteLink(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "with password and expiry",
+			l: func() *model.NoteLink {
+				l := model.TestNoteLink(t)
+				l.Password = "password"
+				expiresAt := time.Now().Add(time.Hour)
+				l.ExpiresAt = &expiresAt
+				return l
+			},
+			isValid: true,
+		},
+		{
+			name: "short password",
+			l: func() *model.NoteLink {
+				l := model.TestNoteLink(t)
+				l.Password = "pass"
+				return l
+			},
+			isValid: false,
+		},
+		{
+			name: "expires in the past",
+			l: func() *model.NoteLink {
+				l := model.TestNoteLink(t)
+				expiresAt := time.Now().Add(-time.Hour)
+				l.ExpiresAt = &expiresAt
+				return l
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.isValid {
+			assert.NoError(t, tc.l().Validate())
+		} else {
+			assert.Error(t, tc.l().Validate())
+		}
+	}
+}
+
+func TestNoteLink_BeforeCreate(t *testing.T) {
+	l1 := model.TestNoteLink(t)
+	assert.NoError(t, l1.BeforeCreate())
+	assert.NotEmpty(t, l1.Token)
+	assert.True(t, l1.ComparePassword(""))
+
+	l2 := model.TestNoteLink(t)
+	l2.Password = "password"
+	assert.NoError(t, l2.BeforeCreate())
+	assert.NotEqual(t, l1.Token, l2.Token)
+	assert.True(t, l2.ComparePassword("password"))
+	assert.False(t, l2.ComparePassword("wrong"))
+
+	l2.Sanitize()
+	assert.Empty(t, l2.Password)
+	assert.True(t, l2.Protected)
+}
+
+func TestNoteLink_Expired(t *testing.T) {
+	l := model.TestNoteLink(t)
+	assert.False(t, l.Expired(time.Now()))
+
+	expiresAt := time.Now().Add(time.Hour)
+	l.ExpiresAt = &expiresAt
+	assert.False(t, l.Expired(time.Now()))
+	assert.True(t, l.Expired(expiresAt.Add(time.Second)))
+}
+
+func TestNoteLink_Locked(t *testing.T) {
+	l := model.TestNoteLink(t)
+	assert.False(t, l.Locked(time.Now()))
+
+	lockedUntil := time.Now().Add(time.Minute)
+	l.LockedUntil = &lockedUntil
+	assert.True(t, l.Locked(time.Now()))
+	assert.False(t, l.Locked(lockedUntil))
+}
@@ -1,19 +1,38 @@
 package model
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 )
 
+// ErrInvalidETag ...
+var ErrInvalidETag = errors.New("invalid entity tag")
+
 // Note ...
 type Note struct {
-	ID        int       `json:"id"`
-	AuthorID  int       `json:"author_id"`
-	Header    string    `json:"header"`
-	Body      string    `json:"body"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         int        `json:"id"`
+	AuthorID   int        `json:"author_id"`
+	NotebookID *int       `json:"notebook_id"`
+	Header     string     `json:"header"`
+	Body       string     `json:"body"`
+	Tags       []string   `json:"tags,omitempty"`
+	Version    int        `json:"version"`
+	Pinned     bool       `json:"pinned"`
+	Archived   bool       `json:"archived"`
+	Starred    bool       `json:"starred"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	HTML       string     `json:"html,omitempty"`
+	// Completion is the percentage of done checklist items,
+	// nil when the note has none
+	Completion *int `json:"completion,omitempty"`
 }
 
 // Validate ...
@@ -22,6 +41,7 @@ func (n *Note) Validate() error {
 		n,
 		validation.Field(&n.Header, validation.Required, validation.Length(1, 100)),
 		validation.Field(&n.Body, validation.Required, validation.Length(1, 1000)),
+		validation.Field(&n.Tags, validation.Each(validation.Required, validation.Length(1, 50))),
 	)
 }
 
@@ -31,5 +51,37 @@ func (n *Note) ValidateUpdate() error {
 		n,
 		validation.Field(&n.Header, validation.Length(0, 100)),
 		validation.Field(&n.Body, validation.Length(0, 1000)),
+		validation.Field(&n.Tags, validation.Each(validation.Required, validation.Length(1, 50))),
 	)
 }
+
+// ETag returns the entity tag of the note version
+func (n *Note) ETag() string {
+	return fmt.Sprintf(`"%d"`, n.Version)
+}
+
+// MarshalJSON adds the entity tag to the note
+func (n *Note) MarshalJSON() ([]byte, error) {
+	type note Note
+	return json.Marshal(&struct {
+		*note
+		ETag string `json:"etag"`
+	}{
+		note: (*note)(n),
+		ETag: n.ETag(),
+	})
+}
+
+// ParseETag returns the note version from its entity tag, weak tags are accepted
+func ParseETag(etag string) (int, error) {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	if len(etag) < 2 || !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `"`) {
+		return 0, ErrInvalidETag
+	}
+
+	v, err := strconv.Atoi(etag[1 : len(etag)-1])
+	if err != nil || v < 1 {
+		return 0, ErrInvalidETag
+	}
+	return v, nil
+}
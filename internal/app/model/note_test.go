@@ -1,6 +1,7 @@
 package model_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/KapitanD/http-api-server/internal/app/model"
@@ -52,6 +53,24 @@ func TestNote_Validate(t *testing.T) {
 			},
 			isValid: false,
 		},
+		{
+			name: "with tags",
+			n: func() *model.Note {
+				n := model.TestNote(t)
+				n.Tags = []string{"work", "urgent"}
+				return n
+			},
+			isValid: true,
+		},
+		{
+			name: "empty tag",
+			n: func() *model.Note {
+				n := model.TestNote(t)
+				n.Tags = []string{"work", ""}
+				return n
+			},
+			isValid: false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -62,3 +81,34 @@ func TestNote_Validate(t *testing.T) {
 		}
 	}
 }
+
+func TestNote_ETag(t *testing.T) {
+	n := model.TestNote(t)
+	n.Version = 3
+	assert.Equal(t, `"3"`, n.ETag())
+
+	v, err := model.ParseETag(n.ETag())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, v)
+
+	v, err = model.ParseETag(`W/"4"`)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, v)
+
+	for _, etag := range []string{"", "3", `"abc"`, `"0"`} {
+		_, err := model.ParseETag(etag)
+		assert.EqualError(t, err, model.ErrInvalidETag.Error())
+	}
+}
+
+func TestNote_MarshalJSON(t *testing.T) {
+	n := model.TestNote(t)
+	n.Version = 2
+	b, err := json.Marshal(n)
+	assert.NoError(t, err)
+
+	res := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(b, &res))
+	assert.Equal(t, `"2"`, res["etag"])
+	assert.Equal(t, "header", res["header"])
+}
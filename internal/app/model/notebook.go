@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Notebook groups notes of the user, notebooks without parent are top-level
+type Notebook struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"-"`
+	ParentID  *int      `json:"parent_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate ...
+func (nb *Notebook) Validate() error {
+	return validation.ValidateStruct(
+		nb,
+		validation.Field(&nb.Name, validation.Required, validation.Length(1, 100)),
+	)
+}
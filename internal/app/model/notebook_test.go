@@ -0,0 +1,51 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotebook_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		nb      func() *model.Notebook
+		isValid bool
+	}{
+		{
+			name: "valid",
+			nb: func() *model.Notebook {
+				return model.TestNotebook(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "empty name",
+			nb: func() *model.Notebook {
+				nb := model.TestNotebook(t)
+				nb.Name = ""
+				return nb
+			},
+			isValid: false,
+		},
+		{
+			name: "long name",
+			nb: func() *model.Notebook {
+				nb := model.TestNotebook(t)
+				nb.Name = strings.Repeat("a", 101)
+				return nb
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.isValid {
+			assert.NoError(t, tc.nb().Validate())
+		} else {
+			assert.Error(t, tc.nb().Validate())
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Kinds of notifications
+const (
+	NotificationReminder = "reminder"
+	NotificationMention  = "mention"
+)
+
+// Notification is an in-app message for the user
+type Notification struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"-"`
+	NoteID    *int       `json:"note_id,omitempty"`
+	Kind      string     `json:"kind"`
+	Message   string     `json:"message"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
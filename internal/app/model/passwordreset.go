@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const passwordResetTokenSize = 32
+
+// PasswordReset lets the user who forgot the password set a new one,
+// the token is sent by email and only its hash is stored
+type PasswordReset struct {
+	ID        int
+	UserID    int
+	Token     string
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// BeforeCreate generates the token of the reset
+func (p *PasswordReset) BeforeCreate() error {
+	token, err := randomToken(passwordResetTokenSize)
+	if err != nil {
+		return err
+	}
+	p.Token = token
+	p.TokenHash = HashToken(token)
+	return nil
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Statuses of note references
+const (
+	// ReferenceOK means the link points to an existing note
+	ReferenceOK = "ok"
+	// ReferenceBroken means no note matches the link or the note is deleted
+	ReferenceBroken = "broken"
+	// ReferenceRenamed means the note was renamed after the link was resolved
+	ReferenceRenamed = "renamed"
+)
+
+var wikiLinkRe = regexp.MustCompile(`\[\[([^\[\]\n]+)\]\]`)
+
+// NoteReference is a wiki link from the source note to the target note,
+// the target is nil when no note matches the link
+type NoteReference struct {
+	SourceID     int    `json:"source_id"`
+	SourceHeader string `json:"source_header"`
+	Link         string `json:"link"`
+	TargetID     *int   `json:"target_id"`
+	TargetHeader string `json:"target_header,omitempty"`
+	Status       string `json:"status"`
+}
+
+// WikiLink is a link to a note by title, [[Title]], or by id, [[#123]]
+type WikiLink struct {
+	Link   string
+	Title  string
+	NoteID int
+}
+
+// ParseWikiLink parses text between the brackets, text after | is
+// the caption and isn't a part of the link
+func ParseWikiLink(s string) *WikiLink {
+	if i := strings.Index(s, "|"); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+
+	l := &WikiLink{Link: s}
+	if strings.HasPrefix(s, "#") {
+		if id, err := strconv.Atoi(s[1:]); err == nil && id > 0 {
+			l.NoteID = id
+			return l
+		}
+	}
+	l.Title = s
+	return l
+}
+
+// ParseWikiLinks returns distinct links of the text in order of appearance
+func ParseWikiLinks(text string) []*WikiLink {
+	result := []*WikiLink{}
+	seen := make(map[string]bool)
+	for _, m := range wikiLinkRe.FindAllStringSubmatch(text, -1) {
+		l := ParseWikiLink(m[1])
+		if l == nil || seen[l.Link] {
+			continue
+		}
+		seen[l.Link] = true
+		result = append(result, l)
+	}
+	return result
+}
+
+// Matches reports whether the link points to the note by its id or header
+func (l *WikiLink) Matches(n *Note) bool {
+	if l.NoteID != 0 {
+		return n.ID == l.NoteID
+	}
+	return strings.EqualFold(strings.TrimSpace(n.Header), l.Title)
+}
+
+// ResolveStatus sets the status and the target header of the reference,
+// target is nil when the link is unresolved
+func (r *NoteReference) ResolveStatus(target *Note) {
+	r.TargetHeader = ""
+	switch {
+	case target == nil || target.DeletedAt != nil:
+		r.Status = ReferenceBroken
+	case !ParseWikiLink(r.Link).Matches(target):
+		r.Status = ReferenceRenamed
+		r.TargetHeader = target.Header
+	default:
+		r.Status = ReferenceOK
+		r.TargetHeader = target.Header
+	}
+}
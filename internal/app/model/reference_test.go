@@ -0,0 +1,73 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWikiLinks(t *testing.T) {
+	links := model.ParseWikiLinks("See [[Weekly plan]], [[#12]] and [[ weekly plan | the plan ]].\n" +
+		"Not links: [[]], [[#]], [single], [[broken\nlink]]. Again [[#12]]")
+
+	assert.Equal(t, []*model.WikiLink{
+		{Link: "Weekly plan", Title: "Weekly plan"},
+		{Link: "#12", NoteID: 12},
+		{Link: "weekly plan", Title: "weekly plan"},
+		{Link: "#", Title: "#"},
+	}, links)
+}
+
+func TestNoteReference_ResolveStatus(t *testing.T) {
+	n := model.TestNote(t)
+	n.ID = 12
+	n.Header = "Weekly plan"
+	deleted := time.Now()
+
+	testCases := []struct {
+		name           string
+		link           string
+		target         *model.Note
+		expectedStatus string
+	}{
+		{
+			name:           "title",
+			link:           "weekly PLAN",
+			target:         n,
+			expectedStatus: model.ReferenceOK,
+		},
+		{
+			name:           "id",
+			link:           "#12",
+			target:         n,
+			expectedStatus: model.ReferenceOK,
+		},
+		{
+			name:           "renamed",
+			link:           "Daily plan",
+			target:         n,
+			expectedStatus: model.ReferenceRenamed,
+		},
+		{
+			name:           "unresolved",
+			link:           "Weekly plan",
+			expectedStatus: model.ReferenceBroken,
+		},
+		{
+			name:           "trashed",
+			link:           "Weekly plan",
+			target:         &model.Note{ID: 12, Header: "Weekly plan", DeletedAt: &deleted},
+			expectedStatus: model.ReferenceBroken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &model.NoteReference{Link: tc.link}
+			r.ResolveStatus(tc.target)
+			assert.Equal(t, tc.expectedStatus, r.Status)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const refreshTokenSize = 32
+
+// RefreshToken is exchanged for a new access token and a new refresh
+// token once. Tokens issued from one login share the family, reuse of a
+// used token revokes the whole family
+type RefreshToken struct {
+	ID        int
+	UserID    int
+	Family    string
+	Token     string
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+// BeforeCreate generates the token and starts a new family
+// unless the family is set
+func (t *RefreshToken) BeforeCreate() error {
+	token, err := randomToken(refreshTokenSize)
+	if err != nil {
+		return err
+	}
+	t.Token = token
+	t.TokenHash = HashToken(token)
+
+	if t.Family == "" {
+		t.Family = uuid.New().String()
+	}
+	return nil
+}
+
+// Expired ...
+func (t *RefreshToken) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
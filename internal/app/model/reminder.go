@@ -0,0 +1,108 @@
+package model
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/rrule"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+// Channels of reminder delivery
+const (
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+	ChannelInApp   = "inapp"
+)
+
+// Reminder fires at RemindAt and then at every occurrence of Recurrence,
+// RemindAt is nil when there are no more occurrences
+type Reminder struct {
+	ID          int        `json:"id"`
+	NoteID      int        `json:"note_id"`
+	UserID      int        `json:"-"`
+	RemindAt    *time.Time `json:"remind_at"`
+	Recurrence  string     `json:"recurrence,omitempty"`
+	Channel     string     `json:"channel"`
+	Target      string     `json:"target,omitempty"`
+	Occurrence  int        `json:"-"`
+	Fired       int        `json:"fired"`
+	LastFiredAt *time.Time `json:"last_fired_at,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Validate ...
+func (r *Reminder) Validate() error {
+	return validation.ValidateStruct(
+		r,
+		validation.Field(&r.RemindAt, validation.Required),
+		validation.Field(&r.Recurrence, validation.Length(0, 200), validation.By(checkRecurrence)),
+		validation.Field(&r.Channel, validation.Required, validation.In(ChannelWebhook, ChannelEmail, ChannelInApp)),
+		validation.Field(&r.Target, validation.Length(0, 2000), validation.By(func(interface{}) error {
+			// email and in-app reminders are delivered to the user only
+			if r.Channel != ChannelWebhook {
+				if r.Target != "" {
+					return errors.New("must be blank")
+				}
+				return nil
+			}
+			if r.Target == "" {
+				return errors.New("cannot be blank")
+			}
+			if err := is.URL.Validate(r.Target); err != nil {
+				return err
+			}
+			if u, err := url.Parse(r.Target); err != nil || u.Scheme != "http" && u.Scheme != "https" {
+				return errors.New("must be an http or https URL")
+			}
+			return nil
+		})),
+	)
+}
+
+func checkRecurrence(value interface{}) error {
+	if s := value.(string); s != "" {
+		if _, err := rrule.Parse(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reset makes RemindAt the first occurrence of the reminder
+func (r *Reminder) Reset() {
+	r.Occurrence = 1
+	r.LastError = ""
+}
+
+// Advance records firing of the reminder at the time and moves RemindAt
+// to the next occurrence after it, occurrences missed in the meantime
+// are skipped
+func (r *Reminder) Advance(at time.Time) {
+	r.Fired++
+	r.LastFiredAt = &at
+
+	rule, err := rrule.Parse(r.Recurrence)
+	if r.Recurrence == "" || err != nil {
+		r.RemindAt = nil
+		return
+	}
+
+	prev := *r.RemindAt
+	for {
+		next, ok := rule.Next(prev, r.Occurrence)
+		if !ok {
+			r.RemindAt = nil
+			return
+		}
+		r.Occurrence++
+		prev = next
+		if next.After(at) {
+			r.RemindAt = &next
+			return
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReminder_Validate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		reminder func() *model.Reminder
+		isValid  bool
+	}{
+		{
+			name: "valid",
+			reminder: func() *model.Reminder {
+				return model.TestReminder(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "no time",
+			reminder: func() *model.Reminder {
+				r := model.TestReminder(t)
+				r.RemindAt = nil
+				return r
+			},
+			isValid: false,
+		},
+		{
+			name: "recurrence",
+			reminder: func() *model.Reminder {
+				r := model.TestReminder(t)
+				r.Recurrence = "FREQ=DAILY;COUNT=5"
+				return r
+			},
+			isValid: true,
+		},
+		{
+			name: "invalid recurrence",
+			reminder: func() *model.Reminder {
+				r := model.TestReminder(t)
+				r.Recurrence = "FREQ=SOMETIMES"
+				return r
+			},
+			isValid: false,
+		},
+		{
+			name: "unknown channel",
+			reminder: func() *model.Reminder {
+				r := model.TestReminder(t)
+				r.Channel = "pigeon"
+				return r
+			},
+			isValid: false,
+		},
+		{
+			name: "webhook",
+			reminder: func() *model.Reminder {
+				r := model.TestReminder(t)
+				r.Channel = model.ChannelWebhook
+				r.Target = "https://example.org/hook"
+				return r
+			},
+			isValid: true,
+		},
+		{
+			name: "webhook without scheme",
+			reminder: func() *model.Reminder {
+				r := model.TestReminder(t)
+				r.Channel = model.ChannelWebhook
+				r.Target = "example.org/hook"
+				return r
+			},
+			isValid: false,
+		},
+		{
+			name: "webhook with other scheme",
+			reminder: func() *model.Reminder {
+				r := model.TestReminder(t)
+				r.Channel = model.ChannelWebhook
+				r.Target = "ftp://example.org/hook"
+				return r
+			},
+			isValid: false,
+		},
+		{
+			name: "webhook without url",
+			reminder: func() *model.Reminder {
+				r := model.TestReminder(t)
+				r.Channel = model.ChannelWebhook
+				return r
+			},
+			isValid: false,
+		},
+		{
+			name: "email with target",
+			reminder: func() *model.Reminder {
+				r := model.TestReminder(t)
+				r.Channel = model.ChannelEmail
+				r.Target = "other@example.org"
+				return r
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.isValid {
+				assert.NoError(t, tc.reminder().Validate())
+			} else {
+				assert.Error(t, tc.reminder().Validate())
+			}
+		})
+	}
+}
+
+func TestReminder_Advance(t *testing.T) {
+	start := time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	r := model.TestReminder(t)
+	r.RemindAt = &start
+	r.Advance(start)
+	assert.Nil(t, r.RemindAt)
+	assert.Equal(t, 1, r.Fired)
+
+	r = model.TestReminder(t)
+	r.RemindAt = &start
+	r.Recurrence = "FREQ=DAILY;COUNT=3"
+	r.Advance(start)
+	assert.Equal(t, start.AddDate(0, 0, 1), *r.RemindAt)
+
+	// the second occurrence is missed
+	r.Advance(start.AddDate(0, 0, 1).Add(25 * time.Hour))
+	assert.Nil(t, r.RemindAt)
+	assert.Equal(t, 2, r.Fired)
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// NoteRevision is a snapshot of note content saved on every change
+type NoteRevision struct {
+	ID        int       `json:"-"`
+	NoteID    int       `json:"note_id"`
+	Revision  int       `json:"revision"`
+	Header    string    `json:"header"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Text returns the revision content as the header line followed by the body
+func (r *NoteRevision) Text() string {
+	return r.Header + "\n\n" + r.Body
+}
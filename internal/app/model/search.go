@@ -0,0 +1,10 @@
+package model
+
+// SearchResult is a note found by full-text search with its relevance
+// and fragments of header and body with highlighted matches
+type SearchResult struct {
+	Note          *Note   `json:"note"`
+	Rank          float64 `json:"rank"`
+	HeaderSnippet string  `json:"header_snippet"`
+	BodySnippet   string  `json:"body_snippet"`
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+const sessionTokenSize = 32
+
+// Session is a login of the user on a device. The client keeps the token
+// of the session, only its hash is stored
+type Session struct {
+	ID         int       `json:"id"`
+	UserID     int       `json:"-"`
+	Token      string    `json:"-"`
+	TokenHash  string    `json:"-"`
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+// BeforeCreate generates the token of the session
+func (s *Session) BeforeCreate() error {
+	token, err := randomToken(sessionTokenSize)
+	if err != nil {
+		return err
+	}
+	s.Token = token
+	s.TokenHash = HashToken(token)
+	return nil
+}
+
+// HashToken returns the hash of the random token to store and look it up
+// by, unlike passwords tokens have enough entropy for a fast hash
+func HashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Roles of users for notes, each role grants everything the previous one does
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleOwner  = "owner"
+)
+
+var roleLevels = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// NoteShare gives a user access to a note of another user
+type NoteShare struct {
+	NoteID    int       `json:"note_id"`
+	UserID    int       `json:"user_id"`
+	Email     string    `json:"email,omitempty"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	Note      *Note     `json:"note,omitempty"`
+}
+
+// Validate ...
+func (s *NoteShare) Validate() error {
+	return validation.ValidateStruct(
+		s,
+		validation.Field(&s.Role, validation.Required, validation.In(RoleViewer, RoleEditor)),
+	)
+}
+
+// RoleAllows reports whether the role grants the required one
+func RoleAllows(role, required string) bool {
+	return role != "" && roleLevels[role] >= roleLevels[required]
+}
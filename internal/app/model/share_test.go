@@ -0,0 +1,67 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoteShare_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		s       func() *model.NoteShare
+		isValid bool
+	}{
+		{
+			name: "viewer",
+			s: func() *model.NoteShare {
+				return model.TestNoteShare(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "editor",
+			s: func() *model.NoteShare {
+				s := model.TestNoteShare(t)
+				s.Role = model.RoleEditor
+				return s
+			},
+			isValid: true,
+		},
+		{
+			name: "owner",
+			s: func() *model.NoteShare {
+				s := model.TestNoteShare(t)
+				s.Role = model.RoleOwner
+				return s
+			},
+			isValid: false,
+		},
+		{
+			name: "empty role",
+			s: func() *model.NoteShare {
+				s := model.TestNoteShare(t)
+				s.Role = ""
+				return s
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.isValid {
+			assert.NoError(t, tc.s().Validate())
+		} else {
+			assert.Error(t, tc.s().Validate())
+		}
+	}
+}
+
+func TestRoleAllows(t *testing.T) {
+	assert.True(t, model.RoleAllows(model.RoleOwner, model.RoleEditor))
+	assert.True(t, model.RoleAllows(model.RoleEditor, model.RoleEditor))
+	assert.True(t, model.RoleAllows(model.RoleEditor, model.RoleViewer))
+	assert.False(t, model.RoleAllows(model.RoleViewer, model.RoleEditor))
+	assert.False(t, model.RoleAllows("", model.RoleViewer))
+}
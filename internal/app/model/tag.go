@@ -0,0 +1,38 @@
+package model
+
+import (
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Tag ...
+type Tag struct {
+	ID        int    `json:"id"`
+	UserID    int    `json:"-"`
+	Name      string `json:"name"`
+	NoteCount int    `json:"note_count"`
+}
+
+// Validate ...
+func (t *Tag) Validate() error {
+	return validation.ValidateStruct(
+		t,
+		validation.Field(&t.Name, validation.Required, validation.Length(1, 50)),
+	)
+}
+
+// NormalizeTags trims tag names and drops empty and duplicate ones
+func NormalizeTags(names []string) []string {
+	result := []string{}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	return result
+}
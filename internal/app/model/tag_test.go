@@ -0,0 +1,59 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTag_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		t       func() *model.Tag
+		isValid bool
+	}{
+		{
+			name: "valid tag",
+			t: func() *model.Tag {
+				return model.TestTag(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "empty name",
+			t: func() *model.Tag {
+				tag := model.TestTag(t)
+				tag.Name = ""
+				return tag
+			},
+			isValid: false,
+		},
+		{
+			name: "large name",
+			t: func() *model.Tag {
+				tag := model.TestTag(t)
+				tag.Name = "tagtagtagtagtagtagtagtagtagtagtagtagtagtagtagtagtagtag"
+				return tag
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.isValid {
+			assert.NoError(t, tc.t().Validate())
+		} else {
+			assert.Error(t, tc.t().Validate())
+		}
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	assert.Equal(t, []string{}, model.NormalizeTags(nil))
+	assert.Equal(
+		t,
+		[]string{"work", "urgent"},
+		model.NormalizeTags([]string{" work", "urgent", "", "work "}),
+	)
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/notetemplate"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Template is a skeleton of notes, its header and body are expanded
+// with notetemplate when a note is created from it
+type Template struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"-"`
+	Name      string    `json:"name"`
+	Header    string    `json:"header"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate ...
+func (t *Template) Validate() error {
+	return validation.ValidateStruct(
+		t,
+		validation.Field(&t.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&t.Header, validation.Required, validation.Length(1, 1000), validation.By(checkTemplate)),
+		validation.Field(&t.Body, validation.Required, validation.Length(1, 10000), validation.By(checkTemplate)),
+	)
+}
+
+func checkTemplate(value interface{}) error {
+	return notetemplate.Check(value.(string))
+}
@@ -0,0 +1,80 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplate_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		tmpl    func() *model.Template
+		isValid bool
+	}{
+		{
+			name: "valid",
+			tmpl: func() *model.Template {
+				return model.TestTemplate(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "empty name",
+			tmpl: func() *model.Template {
+				tmpl := model.TestTemplate(t)
+				tmpl.Name = ""
+				return tmpl
+			},
+			isValid: false,
+		},
+		{
+			name: "long name",
+			tmpl: func() *model.Template {
+				tmpl := model.TestTemplate(t)
+				tmpl.Name = strings.Repeat("a", 101)
+				return tmpl
+			},
+			isValid: false,
+		},
+		{
+			name: "empty body",
+			tmpl: func() *model.Template {
+				tmpl := model.TestTemplate(t)
+				tmpl.Body = ""
+				return tmpl
+			},
+			isValid: false,
+		},
+		{
+			name: "invalid header",
+			tmpl: func() *model.Template {
+				tmpl := model.TestTemplate(t)
+				tmpl.Header = "{{date"
+				return tmpl
+			},
+			isValid: false,
+		},
+		{
+			name: "nested template",
+			tmpl: func() *model.Template {
+				tmpl := model.TestTemplate(t)
+				tmpl.Body = `{{define "x"}}{{end}}`
+				return tmpl
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.isValid {
+				assert.NoError(t, tc.tmpl().Validate())
+			} else {
+				assert.Error(t, tc.tmpl().Validate())
+			}
+		})
+	}
+}
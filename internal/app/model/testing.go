@@ -22,3 +22,80 @@ func TestNote(t *testing.T) *Note {
 		UpdatedAt: time.Now(),
 	}
 }
+
+// TestTag ...
+func TestTag(t *testing.T) *Tag {
+	return &Tag{
+		Name: "work",
+	}
+}
+
+// TestNoteShare ...
+func TestNoteShare(t *testing.T) *NoteShare {
+	return &NoteShare{
+		Role: RoleViewer,
+	}
+}
+
+// TestNoteLink ...
+func TestNoteLink(t *testing.T) *NoteLink {
+	return &NoteLink{}
+}
+
+// TestNotebook ...
+func TestNotebook(t *testing.T) *Notebook {
+	return &Notebook{
+		Name: "work",
+	}
+}
+
+// TestAttachment ...
+func TestAttachment(t *testing.T) *Attachment {
+	return &Attachment{
+		Filename:    "image.png",
+		ContentType: "image/png",
+		Size:        100,
+		StorageKey:  "key",
+	}
+}
+
+// TestTemplate ...
+func TestTemplate(t *testing.T) *Template {
+	return &Template{
+		Name:   "standup",
+		Header: "Standup {{date}}",
+		Body:   "Notes of {{user.email}}",
+	}
+}
+
+// TestReminder ...
+func TestReminder(t *testing.T) *Reminder {
+	remindAt := time.Now().Add(time.Hour)
+	return &Reminder{
+		RemindAt:   &remindAt,
+		Channel:    ChannelInApp,
+		Occurrence: 1,
+	}
+}
+
+// TestChecklistItem ...
+func TestChecklistItem(t *testing.T) *ChecklistItem {
+	return &ChecklistItem{
+		Text: "buy milk",
+	}
+}
+
+// TestComment ...
+func TestComment(t *testing.T) *Comment {
+	return &Comment{
+		Body: "looks good",
+	}
+}
+
+// TestAPIToken ...
+func TestAPIToken(t *testing.T) *APIToken {
+	return &APIToken{
+		Name:   "backup script",
+		Scopes: []string{ScopeNotesRead},
+	}
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
 	"golang.org/x/crypto/bcrypt"
@@ -8,10 +10,11 @@ import (
 
 // User ...
 type User struct {
-	ID                int    `json:"id"`
-	Email             string `json:"email"`
-	Password          string `json:"password,omitempty"`
-	EncryptedPassword string `json:"-"`
+	ID                int        `json:"id"`
+	Email             string     `json:"email"`
+	Password          string     `json:"password,omitempty"`
+	EncryptedPassword string     `json:"-"`
+	EmailVerifiedAt   *time.Time `json:"email_verified_at"`
 }
 
 // Validate ...
@@ -23,6 +26,14 @@ func (u *User) Validate() error {
 	)
 }
 
+// ValidatePassword validates the new password of the user
+func (u *User) ValidatePassword() error {
+	return validation.ValidateStruct(
+		u,
+		validation.Field(&u.Password, validation.Required, validation.Length(6, 100)),
+	)
+}
+
 // BeforeCreate ...
 func (u *User) BeforeCreate() error {
 	if len(u.Password) > 0 {
@@ -41,6 +52,11 @@ func (u *User) Sanitize() {
 	u.Password = ""
 }
 
+// Verified reports whether the user confirmed the email
+func (u *User) Verified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
 // ComparePassword ...
 func (u *User) ComparePassword(password string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(u.EncryptedPassword), []byte(password)) == nil
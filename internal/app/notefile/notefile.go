@@ -0,0 +1,214 @@
+// Package notefile converts notes to and from Markdown files with YAML front-matter
+package notefile
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+)
+
+const (
+	frontMatterDelimiter = "---"
+	maxSlugLength        = 50
+)
+
+// ErrUnclosedFrontMatter ...
+var ErrUnclosedFrontMatter = errors.New("front-matter is not closed with ---")
+
+// FileName returns the name of the note file made of its id and header,
+// ids keep names unique
+func FileName(n *model.Note) string {
+	return fmt.Sprintf("%d-%s.md", n.ID, slug(n.Header))
+}
+
+// Write writes the note body preceded by front-matter with its id, header,
+// timestamps and tags if any. Strings are written as JSON, which is valid YAML
+func Write(w io.Writer, n *model.Note) error {
+	bw := bufio.NewWriter(w)
+	header, err := json.Marshal(n.Header)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(bw, frontMatterDelimiter)
+	fmt.Fprintf(bw, "id: %d\n", n.ID)
+	fmt.Fprintf(bw, "header: %s\n", header)
+	fmt.Fprintf(bw, "created_at: %s\n", n.CreatedAt.Format(time.RFC3339Nano))
+	fmt.Fprintf(bw, "updated_at: %s\n", n.UpdatedAt.Format(time.RFC3339Nano))
+	if len(n.Tags) > 0 {
+		tags, err := json.Marshal(n.Tags)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(bw, "tags: %s\n", tags)
+	}
+	fmt.Fprintln(bw, frontMatterDelimiter)
+	fmt.Fprintln(bw)
+	bw.WriteString(n.Body)
+
+	return bw.Flush()
+}
+
+// Read reads the note from Markdown with optional front-matter. The header is
+// taken from the header or title key, the leading "# " heading or the file
+// name. Unknown keys and the id are ignored
+func Read(name string, r io.Reader) (*model.Note, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	src := strings.Replace(string(b), "\r\n", "\n", -1)
+
+	n := &model.Note{}
+	if strings.HasPrefix(src, frontMatterDelimiter+"\n") {
+		// keep the newline after the opening delimiter so empty
+		// front-matter is found by the same search
+		src = src[len(frontMatterDelimiter):]
+		end := strings.Index(src, "\n"+frontMatterDelimiter+"\n")
+		if end < 0 {
+			if !strings.HasSuffix(src, "\n"+frontMatterDelimiter) {
+				return nil, ErrUnclosedFrontMatter
+			}
+			end = len(src) - len(frontMatterDelimiter) - 1
+		}
+		if err := readFrontMatter(n, src[:end]); err != nil {
+			return nil, err
+		}
+		src = src[min(end+len(frontMatterDelimiter)+2, len(src)):]
+	}
+	src = strings.TrimLeft(src, "\n")
+
+	if n.Header == "" && strings.HasPrefix(src, "# ") {
+		line := src
+		if i := strings.Index(src, "\n"); i >= 0 {
+			line = src[:i]
+		}
+		n.Header = strings.TrimSpace(line[2:])
+		src = strings.TrimLeft(src[len(line):], "\n")
+	}
+	if n.Header == "" {
+		n.Header = strings.TrimSuffix(path.Base(name), path.Ext(name))
+	}
+	n.Body = src
+	return n, nil
+}
+
+func readFrontMatter(n *model.Note, fm string) error {
+	lines := strings.Split(fm, "\n")
+	for i := 0; i < len(lines); i++ {
+		kv := strings.SplitN(lines[i], ":", 2)
+		if len(kv) != 2 || strings.HasPrefix(lines[i], " ") {
+			continue
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+
+		var err error
+		switch key {
+		case "header", "title":
+			n.Header, err = scalar(value)
+		case "created_at", "created":
+			n.CreatedAt, err = timestamp(value)
+		case "updated_at", "updated":
+			n.UpdatedAt, err = timestamp(value)
+		case "tags":
+			if value != "" {
+				n.Tags, err = flowList(value)
+				break
+			}
+			for i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "- ") {
+				i++
+				tag, err := scalar(strings.TrimSpace(lines[i])[2:])
+				if err != nil {
+					return fmt.Errorf("tags: %v", err)
+				}
+				n.Tags = append(n.Tags, tag)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// scalar parses plain, single and double quoted YAML strings
+func scalar(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		var s string
+		err := json.Unmarshal([]byte(value), &s)
+		return s, err
+	case strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") && len(value) > 1:
+		return strings.Replace(value[1:len(value)-1], "''", "'", -1), nil
+	default:
+		return value, nil
+	}
+}
+
+// flowList parses YAML lists like [a, "b"]
+func flowList(value string) ([]string, error) {
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return nil, errors.New("list must be enclosed in brackets")
+	}
+
+	result := []string{}
+	var s []string
+	if err := json.Unmarshal([]byte(value), &s); err == nil {
+		return s, nil
+	}
+	for _, item := range strings.Split(value[1:len(value)-1], ",") {
+		item, err := scalar(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+func timestamp(value string) (time.Time, error) {
+	value, err := scalar(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// slug converts the header to lower case words joined by dashes
+func slug(header string) string {
+	words := strings.FieldsFunc(strings.ToLower(header), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	s := []rune(strings.Join(words, "-"))
+	if len(s) > maxSlugLength {
+		s = []rune(strings.TrimRight(string(s[:maxSlugLength]), "-"))
+	}
+	if len(s) == 0 {
+		return "note"
+	}
+	return string(s)
+}
@@ -0,0 +1,176 @@
+package notefile_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/notefile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{
+			name:     "words",
+			header:   "Weekly Plan: Q3!",
+			expected: "7-weekly-plan-q3.md",
+		},
+		{
+			name:     "unicode",
+			header:   "Список покупок",
+			expected: "7-список-покупок.md",
+		},
+		{
+			name:     "no words",
+			header:   "???",
+			expected: "7-note.md",
+		},
+		{
+			name:     "long",
+			header:   strings.Repeat("word ", 20),
+			expected: "7-" + strings.TrimSuffix(strings.Repeat("word-", 10), "-") + ".md",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, notefile.FileName(&model.Note{ID: 7, Header: tc.header}))
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	created := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	n := &model.Note{
+		ID:        7,
+		Header:    `Say "hi"`,
+		Body:      "# Title\n\ntext",
+		Tags:      []string{"work"},
+		CreatedAt: created,
+		UpdatedAt: created.Add(time.Hour),
+	}
+
+	b := &bytes.Buffer{}
+	assert.NoError(t, notefile.Write(b, n))
+	assert.Equal(t, `---
+id: 7
+header: "Say \"hi\""
+created_at: 2021-03-01T10:00:00Z
+updated_at: 2021-03-01T11:00:00Z
+tags: ["work"]
+---
+
+# Title
+
+text`, b.String())
+
+	n.Tags = nil
+	b.Reset()
+	assert.NoError(t, notefile.Write(b, n))
+	assert.NotContains(t, b.String(), "tags:")
+}
+
+func TestRead(t *testing.T) {
+	testCases := []struct {
+		name         string
+		file         string
+		src          string
+		expectedNote *model.Note
+		isValid      bool
+	}{
+		{
+			name: "front-matter",
+			file: "1-plan.md",
+			src: "---\nid: 1\nheader: \"Plan\"\ncreated_at: 2021-03-01T10:00:00Z\n" +
+				"updated_at: 2021-03-01T11:00:00Z\ntags: [\"work\", \"q3\"]\n---\n\nbody\n",
+			expectedNote: &model.Note{
+				Header:    "Plan",
+				Body:      "body\n",
+				Tags:      []string{"work", "q3"},
+				CreatedAt: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2021, 3, 1, 11, 0, 0, 0, time.UTC),
+			},
+			isValid: true,
+		},
+		{
+			name: "title and block tags",
+			file: "plan.md",
+			src:  "---\ntitle: 'It''s a plan'\ntags:\n  - work\n  - q3\ncreated: 2021-03-01\n---\nbody",
+			expectedNote: &model.Note{
+				Header:    "It's a plan",
+				Body:      "body",
+				Tags:      []string{"work", "q3"},
+				CreatedAt: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+			isValid: true,
+		},
+		{
+			name: "heading",
+			file: "plan.md",
+			src:  "# Weekly plan\n\nbody",
+			expectedNote: &model.Note{
+				Header: "Weekly plan",
+				Body:   "body",
+			},
+			isValid: true,
+		},
+		{
+			name: "file name",
+			file: "notes/weekly plan.md",
+			src:  "---\n---\nbody",
+			expectedNote: &model.Note{
+				Header: "weekly plan",
+				Body:   "body",
+			},
+			isValid: true,
+		},
+		{
+			name:    "unclosed front-matter",
+			file:    "plan.md",
+			src:     "---\nheader: plan\nbody",
+			isValid: false,
+		},
+		{
+			name:    "invalid time",
+			file:    "plan.md",
+			src:     "---\ncreated_at: yesterday\n---\nbody",
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n, err := notefile.Read(tc.file, strings.NewReader(tc.src))
+			if tc.isValid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedNote, n)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	created := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	n := &model.Note{
+		Header:    `Say "hi": now`,
+		Body:      "# Title\n\ntext",
+		Tags:      []string{"work", "q3"},
+		CreatedAt: created,
+		UpdatedAt: created.Add(time.Hour),
+	}
+
+	b := &bytes.Buffer{}
+	assert.NoError(t, notefile.Write(b, n))
+	rn, err := notefile.Read("note.md", b)
+	assert.NoError(t, err)
+	assert.Equal(t, n, rn)
+}
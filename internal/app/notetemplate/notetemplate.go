@@ -0,0 +1,175 @@
+// Package notetemplate expands placeholders of note templates
+package notetemplate
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+const (
+	// maxOutput limits the size of the expanded template
+	maxOutput = 64 << 10
+	// maxDuration limits the time of expanding the template
+	maxDuration = time.Second
+)
+
+var (
+	// ErrNestedTemplate ...
+	ErrNestedTemplate = errors.New("define, block and template actions are not allowed")
+	// ErrRange ...
+	ErrRange = errors.New("range actions are not allowed")
+	// ErrOutputTooLarge ...
+	ErrOutputTooLarge = errors.New("expanded template is too large")
+	// ErrTimeout ...
+	ErrTimeout = errors.New("expanding template takes too long")
+)
+
+// Context holds values available to templates: the user's email as
+// {{user.email}} and caller variables as {{.name}}
+type Context struct {
+	Now       time.Time
+	UserEmail string
+	Vars      map[string]string
+}
+
+// funcs returns the only functions available to templates besides builtins,
+// none of them have side effects or access anything but the context
+func funcs(ctx *Context) template.FuncMap {
+	return template.FuncMap{
+		"date": func(layout ...string) string {
+			return ctx.Now.Format(layoutOr(layout, "2006-01-02"))
+		},
+		"time": func(layout ...string) string {
+			return ctx.Now.Format(layoutOr(layout, "15:04"))
+		},
+		"weekday": func() string {
+			return ctx.Now.Weekday().String()
+		},
+		"user": func() map[string]string {
+			return map[string]string{"email": ctx.UserEmail}
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"trim":  strings.TrimSpace,
+		"default": func(def, value string) string {
+			if value == "" {
+				return def
+			}
+			return value
+		},
+	}
+}
+
+func layoutOr(layout []string, def string) string {
+	if len(layout) > 0 {
+		return layout[0]
+	}
+	return def
+}
+
+// Check reports whether the template source is valid
+func Check(src string) error {
+	_, err := parseTemplate(src, &Context{})
+	return err
+}
+
+// Execute expands the template source. Missing variables are errors,
+// so notes are never created with <no value> inside. Templates have
+// no loops, expanding them longer than maxDuration is abandoned anyway
+func Execute(src string, ctx *Context) (string, error) {
+	t, err := parseTemplate(src, ctx)
+	if err != nil {
+		return "", err
+	}
+
+	vars := ctx.Vars
+	if vars == nil {
+		vars = map[string]string{}
+	}
+	w := &limitedBuffer{limit: maxOutput}
+	done := make(chan error, 1)
+	go func() {
+		done <- t.Execute(w, vars)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			if w.exceeded {
+				return "", ErrOutputTooLarge
+			}
+			return "", err
+		}
+		return w.String(), nil
+	case <-time.After(maxDuration):
+		return "", ErrTimeout
+	}
+}
+
+func parseTemplate(src string, ctx *Context) (*template.Template, error) {
+	t, err := template.New("note").Funcs(funcs(ctx)).Option("missingkey=error").Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(t.Templates()) > 1 {
+		return nil, ErrNestedTemplate
+	}
+	if t.Tree != nil {
+		if err := checkNode(t.Tree.Root); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// checkNode rejects invoking other templates, which could recurse without
+// limit, and ranges, which could loop over a huge number without output.
+// Vars are flat strings, so there is nothing useful to range over
+func checkNode(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.TemplateNode:
+		return ErrNestedTemplate
+	case *parse.RangeNode:
+		return ErrRange
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, c := range n.Nodes {
+			if err := checkNode(c); err != nil {
+				return err
+			}
+		}
+	case *parse.IfNode:
+		return checkBranches(n.List, n.ElseList)
+	case *parse.WithNode:
+		return checkBranches(n.List, n.ElseList)
+	}
+	return nil
+}
+
+func checkBranches(list, elseList *parse.ListNode) error {
+	if err := checkNode(list); err != nil {
+		return err
+	}
+	return checkNode(elseList)
+}
+
+// limitedBuffer fails writes beyond the limit
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		b.exceeded = true
+		return 0, ErrOutputTooLarge
+	}
+	return b.Buffer.Write(p)
+}
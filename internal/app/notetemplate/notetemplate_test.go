@@ -0,0 +1,97 @@
+package notetemplate_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/notetemplate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecute(t *testing.T) {
+	ctx := &notetemplate.Context{
+		Now:       time.Date(2021, 3, 15, 9, 30, 0, 0, time.UTC),
+		UserEmail: "user@example.org",
+		Vars:      map[string]string{"project": "apollo"},
+	}
+	testCases := []struct {
+		name     string
+		src      string
+		expected string
+		isValid  bool
+	}{
+		{
+			name:     "placeholders",
+			src:      "Standup {{date}} {{time}} by {{user.email}}",
+			expected: "Standup 2021-03-15 09:30 by user@example.org",
+			isValid:  true,
+		},
+		{
+			name:     "vars and functions",
+			src:      `{{upper .project}} on {{weekday}}, {{date "Jan 2"}}, {{default "none" .project}}`,
+			expected: "APOLLO on Monday, Mar 15, apollo",
+			isValid:  true,
+		},
+		{
+			name:     "condition",
+			src:      `{{if eq .project "apollo"}}moon{{else}}earth{{end}}`,
+			expected: "moon",
+			isValid:  true,
+		},
+		{
+			name:    "missing var",
+			src:     "{{.agenda}}",
+			isValid: false,
+		},
+		{
+			name:    "unknown function",
+			src:     `{{exec "ls"}}`,
+			isValid: false,
+		},
+		{
+			name:    "define",
+			src:     `{{define "a"}}x{{end}}`,
+			isValid: false,
+		},
+		{
+			name:    "template",
+			src:     `{{if true}}{{template "note"}}{{end}}`,
+			isValid: false,
+		},
+		{
+			name:    "range",
+			src:     `{{if true}}{{range 2000000000}}{{range 2000000000}}{{end}}{{end}}{{end}}`,
+			isValid: false,
+		},
+		{
+			name:    "too large",
+			src:     strings.Repeat("{{.project}}", 20000),
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := notetemplate.Execute(tc.src, ctx)
+			if tc.isValid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, res)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	assert.NoError(t, notetemplate.Check("{{date}} {{.anything}}"))
+	assert.Error(t, notetemplate.Check("{{date"))
+	assert.Error(t, notetemplate.Check(`{{define "x"}}{{end}}`))
+	assert.EqualError(
+		t,
+		notetemplate.Check(`{{range 2000000000}}{{range 2000000000}}{{end}}{{end}}`),
+		notetemplate.ErrRange.Error(),
+	)
+	assert.EqualError(t, notetemplate.Check(`{{with .x}}{{range .}}{{end}}{{end}}`), notetemplate.ErrRange.Error())
+}
@@ -0,0 +1,180 @@
+// Package notifier delivers notifications about notes to users
+// through webhooks, email or in-app notifications
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/mail"
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// Message is a notification for the user about the note
+type Message struct {
+	Kind   string
+	UserID int
+	Email  string
+	NoteID int
+	Header string
+	Text   string
+	// Target is the address of the channel, like the webhook URL
+	Target string
+	At     time.Time
+}
+
+// Notifier ...
+type Notifier interface {
+	Notify(*Message) error
+}
+
+var (
+	// ErrAddressNotAllowed ...
+	ErrAddressNotAllowed = errors.New("webhook address is not allowed")
+	// ErrRedirect ...
+	ErrRedirect = errors.New("webhook redirects are not followed")
+)
+
+// privateNetworks are addresses of the server's own networks
+// besides loopback and link-local ones
+var privateNetworks = parseNetworks(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"fc00::/7",
+)
+
+// Webhook posts messages as JSON to their target URL
+type Webhook struct {
+	client *http.Client
+}
+
+// NewWebhook returns the webhook notifier. Unless allowPrivate is set, it
+// connects to public addresses only, the address is checked after the host
+// is resolved so DNS can't point a checked name to an internal one
+func NewWebhook(timeout time.Duration, allowPrivate bool) *Webhook {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = checkAddress
+	}
+	return &Webhook{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return ErrRedirect
+			},
+		},
+	}
+}
+
+// Notify ...
+func (n *Webhook) Notify(m *Message) error {
+	b, err := json.Marshal(map[string]interface{}{
+		"kind":    m.Kind,
+		"note_id": m.NoteID,
+		"header":  m.Header,
+		"message": m.Text,
+		"at":      m.At,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(m.Target, "application/json", bytes.NewReader(b))
+	if err != nil {
+		// the error would tell what the host resolves to
+		if errors.Is(err, ErrAddressNotAllowed) {
+			return ErrAddressNotAllowed
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+func checkAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() || ip.IsMulticast() {
+		return ErrAddressNotAllowed
+	}
+	for _, n := range privateNetworks {
+		if n.Contains(ip) {
+			return ErrAddressNotAllowed
+		}
+	}
+	return nil
+}
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	result := []*net.IPNet{}
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		result = append(result, n)
+	}
+	return result
+}
+
+// Email sends messages to the email of the user
+type Email struct {
+	sender mail.Sender
+}
+
+// NewEmail ...
+func NewEmail(sender mail.Sender) *Email {
+	return &Email{
+		sender: sender,
+	}
+}
+
+// Notify ...
+func (n *Email) Notify(m *Message) error {
+	return n.sender.Send(&mail.Message{
+		To:      m.Email,
+		Subject: m.Text,
+		Body:    m.Header,
+	})
+}
+
+// InApp saves messages as notifications of the user
+type InApp struct {
+	notifications store.NotificationRepository
+}
+
+// NewInApp ...
+func NewInApp(notifications store.NotificationRepository) *InApp {
+	return &InApp{
+		notifications: notifications,
+	}
+}
+
+// Notify ...
+func (n *InApp) Notify(m *Message) error {
+	noteID := m.NoteID
+	return n.notifications.Create(&model.Notification{
+		UserID:  m.UserID,
+		NoteID:  &noteID,
+		Kind:    m.Kind,
+		Message: m.Text,
+	})
+}
@@ -0,0 +1,99 @@
+package notifier_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/notifier"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook_Notify(t *testing.T) {
+	received := map[string]interface{}{}
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	m := &notifier.Message{
+		Kind:   model.NotificationReminder,
+		NoteID: 1,
+		Header: "header",
+		Text:   "Reminder: header",
+		Target: srv.URL,
+		At:     time.Now(),
+	}
+	n := notifier.NewWebhook(time.Second, true)
+	assert.NoError(t, n.Notify(m))
+	assert.Equal(t, "Reminder: header", received["message"])
+
+	status = http.StatusInternalServerError
+	assert.Error(t, n.Notify(m))
+
+	status = http.StatusFound
+	assert.Error(t, n.Notify(m))
+}
+
+func TestWebhook_NotifyRedirect(t *testing.T) {
+	hit := false
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer internal.Close()
+	srv := httptest.NewServer(http.RedirectHandler(internal.URL, http.StatusTemporaryRedirect))
+	defer srv.Close()
+
+	n := notifier.NewWebhook(time.Second, true)
+	err := n.Notify(&notifier.Message{Target: srv.URL})
+	assert.True(t, errors.Is(err, notifier.ErrRedirect))
+	assert.False(t, hit)
+}
+
+func TestWebhook_NotifyPrivateAddress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("private address is reached")
+	}))
+	defer srv.Close()
+
+	n := notifier.NewWebhook(time.Second, false)
+	for _, target := range []string{
+		srv.URL,
+		fmt.Sprintf("http://localhost:%d", srv.Listener.Addr().(*net.TCPAddr).Port),
+		"http://10.0.0.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		assert.EqualError(t, n.Notify(&notifier.Message{Target: target}), notifier.ErrAddressNotAllowed.Error(), target)
+	}
+}
+
+func TestInApp_Notify(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	n := notifier.NewInApp(s.Notifications())
+	assert.NoError(t, n.Notify(&notifier.Message{
+		Kind:   model.NotificationReminder,
+		UserID: u.ID,
+		NoteID: 1,
+		Text:   "Reminder: header",
+	}))
+
+	nl, err := s.Notifications().FindByUser(u, true)
+	assert.NoError(t, err)
+	if assert.Len(t, nl, 1) {
+		assert.Equal(t, "Reminder: header", nl[0].Message)
+	}
+}
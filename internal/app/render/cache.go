@@ -0,0 +1,67 @@
+package render
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache keeps rendered notes by id, a note is rendered again only when its
+// version changes. The least recently used notes are evicted when the cache
+// is full, caches of zero size keep nothing
+type Cache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[int]*list.Element
+}
+
+type entry struct {
+	id      int
+	version int
+	html    string
+}
+
+// NewCache ...
+func NewCache(size int) *Cache {
+	return &Cache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[int]*list.Element),
+	}
+}
+
+// Get returns the rendered note of the version
+func (c *Cache) Get(id, version int) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[id]
+	if !ok || el.Value.(*entry).version != version {
+		return "", false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*entry).html, true
+}
+
+// Add replaces the rendered note, entries of other versions are dropped
+func (c *Cache) Add(id, version int, html string) {
+	if c.size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[id]; ok {
+		el.Value = &entry{id, version, html}
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[id] = c.ll.PushFront(&entry{id, version, html})
+	if c.ll.Len() > c.size {
+		el := c.ll.Back()
+		c.ll.Remove(el)
+		delete(c.items, el.Value.(*entry).id)
+	}
+}
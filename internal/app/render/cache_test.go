@@ -0,0 +1,38 @@
+package render_test
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/render"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	c := render.NewCache(2)
+	c.Add(1, 1, "first")
+	c.Add(2, 1, "second")
+
+	html, ok := c.Get(1, 1)
+	assert.True(t, ok)
+	assert.Equal(t, "first", html)
+	_, ok = c.Get(1, 2)
+	assert.False(t, ok)
+
+	c.Add(1, 2, "first updated")
+	html, ok = c.Get(1, 2)
+	assert.True(t, ok)
+	assert.Equal(t, "first updated", html)
+
+	c.Add(3, 1, "third")
+	_, ok = c.Get(2, 1)
+	assert.False(t, ok)
+	_, ok = c.Get(1, 2)
+	assert.True(t, ok)
+}
+
+func TestCache_ZeroSize(t *testing.T) {
+	c := render.NewCache(0)
+	c.Add(1, 1, "first")
+	_, ok := c.Get(1, 1)
+	assert.False(t, ok)
+}
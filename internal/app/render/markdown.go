@@ -0,0 +1,37 @@
+// Package render converts Markdown note bodies to sanitized HTML
+package render
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+	)
+	policy = newPolicy()
+)
+
+// Markdown renders CommonMark with GFM tables, task lists, strikethrough
+// and autolinks. Raw HTML of the source is dropped and the result is
+// sanitized, so it's safe to embed into pages
+func Markdown(src string) (string, error) {
+	b := &bytes.Buffer{}
+	if err := markdown.Convert([]byte(src), b); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(b.String()), nil
+}
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w-]+$`)).OnElements("code")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").Matching(regexp.MustCompile(`^$|^checked$|^disabled$`)).OnElements("input")
+	return p
+}
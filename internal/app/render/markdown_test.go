@@ -0,0 +1,60 @@
+package render_test
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/render"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdown(t *testing.T) {
+	testCases := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			name:     "paragraph",
+			src:      "some *text*",
+			expected: "<p>some <em>text</em></p>\n",
+		},
+		{
+			name:     "table",
+			src:      "| a |\n|---|\n| 1 |",
+			expected: "<table>\n<thead>\n<tr>\n<th>a</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>1</td>\n</tr>\n</tbody>\n</table>\n",
+		},
+		{
+			name:     "task list",
+			src:      "- [x] done\n- [ ] todo",
+			expected: "<ul>\n<li><input checked=\"\" disabled=\"\" type=\"checkbox\"> done</li>\n<li><input disabled=\"\" type=\"checkbox\"> todo</li>\n</ul>\n",
+		},
+		{
+			name:     "code",
+			src:      "```go\ncode\n```",
+			expected: "<pre><code class=\"language-go\">code\n</code></pre>\n",
+		},
+		{
+			name:     "raw html",
+			src:      "<script>alert(1)</script>\n\n<img src=x onerror=alert(1)>",
+			expected: "\n\n",
+		},
+		{
+			name:     "javascript link",
+			src:      "[link](javascript:alert(1))",
+			expected: "<p>link</p>\n",
+		},
+		{
+			name:     "autolink",
+			src:      "see https://example.org",
+			expected: "<p>see <a href=\"https://example.org\" rel=\"nofollow\">https://example.org</a></p>\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			html, err := render.Markdown(tc.src)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, html)
+		})
+	}
+}
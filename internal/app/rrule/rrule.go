@@ -0,0 +1,194 @@
+// Package rrule implements a subset of iCalendar recurrence rules (RFC 5545):
+// FREQ, INTERVAL, COUNT, UNTIL and BYDAY. Occurrences are computed in the
+// location of the previous one
+package rrule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequencies of rules
+const (
+	Hourly  = "HOURLY"
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+	Yearly  = "YEARLY"
+)
+
+const maxInterval = 1000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule ...
+type Rule struct {
+	Freq     string
+	Interval int
+	Count    int
+	Until    time.Time
+	ByDay    []time.Weekday
+}
+
+// Parse parses the rule like "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR",
+// the "RRULE:" prefix is optional
+func Parse(s string) (*Rule, error) {
+	r := &Rule{Interval: 1}
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("rrule: invalid part %q", part)
+		}
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		var err error
+		switch key {
+		case "FREQ":
+			switch value {
+			case Hourly, Daily, Weekly, Monthly, Yearly:
+				r.Freq = value
+			default:
+				return nil, fmt.Errorf("rrule: unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = positive(key, value)
+			if err == nil && r.Interval > maxInterval {
+				err = fmt.Errorf("rrule: INTERVAL must be at most %d", maxInterval)
+			}
+		case "COUNT":
+			r.Count, err = positive(key, value)
+		case "UNTIL":
+			r.Until, err = time.Parse("20060102T150405Z", value)
+			if err != nil {
+				r.Until, err = time.Parse("20060102", value)
+			}
+			if err != nil {
+				err = fmt.Errorf("rrule: invalid UNTIL %q", value)
+			}
+		case "BYDAY":
+			for _, d := range strings.Split(value, ",") {
+				wd, ok := weekdays[d]
+				if !ok {
+					return nil, fmt.Errorf("rrule: invalid BYDAY %q", d)
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		default:
+			return nil, fmt.Errorf("rrule: unsupported part %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("rrule: FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, fmt.Errorf("rrule: COUNT and UNTIL can't be used together")
+	}
+	if len(r.ByDay) > 0 && r.Freq != Daily && r.Freq != Weekly {
+		return nil, fmt.Errorf("rrule: BYDAY is supported with DAILY and WEEKLY only")
+	}
+	return r, nil
+}
+
+func positive(key, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("rrule: %s must be a positive number", key)
+	}
+	return n, nil
+}
+
+// Next returns the occurrence after prev, which is the n-th occurrence
+// counting from 1. It returns false when the rule has no more occurrences
+func (r *Rule) Next(prev time.Time, n int) (time.Time, bool) {
+	if r.Count > 0 && n >= r.Count {
+		return time.Time{}, false
+	}
+
+	t, ok := r.next(prev)
+	if !ok || !r.Until.IsZero() && t.After(r.Until) {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func (r *Rule) next(prev time.Time) (time.Time, bool) {
+	switch r.Freq {
+	case Hourly:
+		return prev.Add(time.Duration(r.Interval) * time.Hour), true
+	case Daily:
+		// with intervals multiple of a week the weekday never changes,
+		// so a week of candidates is enough
+		for i := 1; i <= 7; i++ {
+			t := prev.AddDate(0, 0, i*r.Interval)
+			if r.onDay(t) {
+				return t, true
+			}
+		}
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			return prev.AddDate(0, 0, 7*r.Interval), true
+		}
+		start := weekStart(prev)
+		for i := 1; i <= 7*r.Interval+7; i++ {
+			t := prev.AddDate(0, 0, i)
+			weeks := int(weekStart(t).Sub(start).Hours()+12) / (24 * 7)
+			if weeks%r.Interval == 0 && r.onDay(t) {
+				return t, true
+			}
+		}
+	case Monthly:
+		// months without the day of the occurrence are skipped
+		for i := 1; i <= 12; i++ {
+			t := time.Date(prev.Year(), prev.Month()+time.Month(i*r.Interval), prev.Day(),
+				prev.Hour(), prev.Minute(), prev.Second(), prev.Nanosecond(), prev.Location())
+			if t.Day() == prev.Day() {
+				return t, true
+			}
+		}
+	case Yearly:
+		// February 29 occurs in leap years only
+		for i := 1; i <= 8; i++ {
+			t := prev.AddDate(i*r.Interval, 0, 0)
+			if t.Day() == prev.Day() {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r *Rule) onDay(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, d := range r.ByDay {
+		if t.Weekday() == d {
+			return true
+		}
+	}
+	return false
+}
+
+// weekStart returns midnight of Monday of the week of t
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
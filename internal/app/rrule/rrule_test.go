@@ -0,0 +1,158 @@
+package rrule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/rrule"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name    string
+		rule    string
+		isValid bool
+	}{
+		{
+			name:    "valid",
+			rule:    "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;UNTIL=20211231T000000Z",
+			isValid: true,
+		},
+		{
+			name:    "lower case",
+			rule:    "freq=daily;count=3",
+			isValid: true,
+		},
+		{
+			name:    "no freq",
+			rule:    "INTERVAL=2",
+			isValid: false,
+		},
+		{
+			name:    "unsupported freq",
+			rule:    "FREQ=SECONDLY",
+			isValid: false,
+		},
+		{
+			name:    "invalid interval",
+			rule:    "FREQ=DAILY;INTERVAL=0",
+			isValid: false,
+		},
+		{
+			name:    "count and until",
+			rule:    "FREQ=DAILY;COUNT=2;UNTIL=20210101",
+			isValid: false,
+		},
+		{
+			name:    "invalid day",
+			rule:    "FREQ=WEEKLY;BYDAY=XX",
+			isValid: false,
+		},
+		{
+			name:    "byday with monthly",
+			rule:    "FREQ=MONTHLY;BYDAY=MO",
+			isValid: false,
+		},
+		{
+			name:    "unsupported part",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=1",
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := rrule.Parse(tc.rule)
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestRule_Next(t *testing.T) {
+	// Monday
+	start := time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		rule     string
+		start    time.Time
+		expected []time.Time
+	}{
+		{
+			name:  "hourly",
+			rule:  "FREQ=HOURLY;INTERVAL=6;COUNT=3",
+			start: start,
+			expected: []time.Time{
+				start,
+				start.Add(6 * time.Hour),
+				start.Add(12 * time.Hour),
+			},
+		},
+		{
+			name:  "daily on weekdays",
+			rule:  "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;COUNT=6",
+			start: start.AddDate(0, 0, 3),
+			expected: []time.Time{
+				start.AddDate(0, 0, 3),
+				start.AddDate(0, 0, 4),
+				start.AddDate(0, 0, 7),
+				start.AddDate(0, 0, 8),
+				start.AddDate(0, 0, 9),
+				start.AddDate(0, 0, 10),
+			},
+		},
+		{
+			name:  "every other week",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;UNTIL=20210401",
+			start: start,
+			expected: []time.Time{
+				start,
+				start.AddDate(0, 0, 4),
+				start.AddDate(0, 0, 14),
+				start.AddDate(0, 0, 18),
+				start.AddDate(0, 0, 28),
+			},
+		},
+		{
+			name:  "monthly skips short months",
+			rule:  "FREQ=MONTHLY;COUNT=3",
+			start: time.Date(2021, 1, 31, 9, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2021, 1, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2021, 3, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2021, 5, 31, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "yearly leap day",
+			rule:  "FREQ=YEARLY;COUNT=2",
+			start: time.Date(2020, 2, 29, 9, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2020, 2, 29, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := rrule.Parse(tc.rule)
+			assert.NoError(t, err)
+
+			got := []time.Time{tc.start}
+			for n, prev := 1, tc.start; n < 10; n++ {
+				next, ok := r.Next(prev, n)
+				if !ok {
+					break
+				}
+				got = append(got, next)
+				prev = next
+			}
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package store
+
+import "io"
+
+// Blob is the content of a stored file
+type Blob interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+// BlobStore keeps contents of files by keys, keys consist of letters,
+// digits and dashes
+type BlobStore interface {
+	Put(string, io.Reader) (int64, error)
+	Open(string) (Blob, error)
+	Delete(string) error
+}
+
+// ValidBlobKey ...
+func ValidBlobKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package store
+
+import "github.com/KapitanD/http-api-server/internal/app/model"
+
+// Bulk operations on notes
+const (
+	BulkDelete = "delete"
+	BulkUpdate = "update"
+	BulkTag    = "tag"
+	BulkMove   = "move"
+)
+
+// BulkItem is an operation on a single note applied by NoteRepository.Bulk.
+// Delete moves the note to trash, update changes its header and body, tag
+// adds and removes tags and move moves it to the notebook. The user must
+// have at least Role for the note when the item is applied, notes the user
+// can't access fail with ErrRecordNotFound and lower roles with ErrForbidden.
+// Err is set when the operation fails
+type BulkItem struct {
+	Op         string
+	NoteID     int
+	UserID     int
+	Role       string
+	Header     string
+	Body       string
+	AddTags    []string
+	RemoveTags []string
+	NotebookID *int
+	Err        error
+}
+
+// Validate checks the item without looking at the note
+func (it *BulkItem) Validate() error {
+	switch it.Op {
+	case BulkDelete, BulkMove:
+		return nil
+	case BulkUpdate:
+		return (&model.Note{Header: it.Header, Body: it.Body}).ValidateUpdate()
+	case BulkTag:
+		for _, name := range model.NormalizeTags(append(it.AddTags, it.RemoveTags...)) {
+			if err := (&model.Tag{Name: name}).Validate(); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return ErrUnknownBulkOp
+	}
+}
+
+// MergeTags returns tags with the added tags and without the removed ones
+func (it *BulkItem) MergeTags(tags []string) []string {
+	removed := make(map[string]bool)
+	for _, name := range model.NormalizeTags(it.RemoveTags) {
+		removed[name] = true
+	}
+
+	result := []string{}
+	for _, name := range model.NormalizeTags(append(append([]string{}, tags...), it.AddTags...)) {
+		if !removed[name] {
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package store_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkItem_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		item    *store.BulkItem
+		isValid bool
+	}{
+		{
+			name:    "delete",
+			item:    &store.BulkItem{Op: store.BulkDelete, NoteID: 1},
+			isValid: true,
+		},
+		{
+			name:    "update",
+			item:    &store.BulkItem{Op: store.BulkUpdate, NoteID: 1, Header: "header"},
+			isValid: true,
+		},
+		{
+			name:    "tag",
+			item:    &store.BulkItem{Op: store.BulkTag, NoteID: 1, AddTags: []string{"work"}},
+			isValid: true,
+		},
+		{
+			name:    "invalid tag",
+			item:    &store.BulkItem{Op: store.BulkTag, NoteID: 1, AddTags: []string{strings.Repeat("a", 51)}},
+			isValid: false,
+		},
+		{
+			name:    "unknown op",
+			item:    &store.BulkItem{Op: "copy", NoteID: 1},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.isValid {
+				assert.NoError(t, tc.item.Validate())
+			} else {
+				assert.Error(t, tc.item.Validate())
+			}
+		})
+	}
+}
+
+func TestBulkItem_MergeTags(t *testing.T) {
+	it := &store.BulkItem{
+		AddTags:    []string{"urgent", "work"},
+		RemoveTags: []string{"draft"},
+	}
+	assert.Equal(t, []string{"work", "urgent"}, it.MergeTags([]string{"draft", "work"}))
+	assert.Equal(t, []string{}, (&store.BulkItem{RemoveTags: []string{"work"}}).MergeTags([]string{"work"}))
+}
@@ -5,4 +5,24 @@ import "errors"
 var (
 	// ErrRecordNotFound ...
 	ErrRecordNotFound = errors.New("record not found")
+	// ErrInvalidCursor ...
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrVersionConflict ...
+	ErrVersionConflict = errors.New("note was changed by another request")
+	// ErrInvalidSearchQuery ...
+	ErrInvalidSearchQuery = errors.New("search query must contain at least one word")
+	// ErrInvalidBlobKey ...
+	ErrInvalidBlobKey = errors.New("invalid blob key")
+	// ErrUnknownFlag ...
+	ErrUnknownFlag = errors.New("unknown note flag")
+	// ErrNotebookCycle ...
+	ErrNotebookCycle = errors.New("notebook can't be moved into itself or its descendant")
+	// ErrInvalidDeleteMode ...
+	ErrInvalidDeleteMode = errors.New("delete mode must be cascade or move")
+	// ErrUnknownBulkOp ...
+	ErrUnknownBulkOp = errors.New("operation must be delete, update, tag or move")
+	// ErrForbidden ...
+	ErrForbidden = errors.New("not enough permissions")
+	// ErrBulkFailed ...
+	ErrBulkFailed = errors.New("bulk operation failed, no changes were applied")
 )
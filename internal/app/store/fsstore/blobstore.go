@@ -0,0 +1,96 @@
+package fsstore
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// BlobStore keeps blobs as files in the directory, files are spread
+// over subdirectories named by the first two characters of keys
+type BlobStore struct {
+	dir string
+}
+
+// New creates the directory when it doesn't exist
+func New(dir string) (*BlobStore, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+
+	return &BlobStore{
+		dir: dir,
+	}, nil
+}
+
+// Put writes the blob to a temporary file first, so readers never see
+// partially written blobs
+func (s *BlobStore) Put(key string, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return 0, err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(f.Name())
+
+	n, err := io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+
+	return n, os.Rename(f.Name(), path)
+}
+
+// Open ...
+func (s *BlobStore) Open(key string) (store.Blob, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// Delete ...
+func (s *BlobStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *BlobStore) path(key string) (string, error) {
+	if !store.ValidBlobKey(key) || len(key) < 2 {
+		return "", store.ErrInvalidBlobKey
+	}
+	return filepath.Join(s.dir, key[:2], key), nil
+}
@@ -0,0 +1,72 @@
+package memstore
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// BlobStore keeps blobs in memory
+type BlobStore struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+// New ...
+func New() *BlobStore {
+	return &BlobStore{
+		blobs: make(map[string][]byte),
+	}
+}
+
+// Put ...
+func (s *BlobStore) Put(key string, r io.Reader) (int64, error) {
+	if !store.ValidBlobKey(key) {
+		return 0, store.ErrInvalidBlobKey
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[key] = b
+	return int64(len(b)), nil
+}
+
+// Open ...
+func (s *BlobStore) Open(key string) (store.Blob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, ok := s.blobs[key]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+	return &blob{bytes.NewReader(b)}, nil
+}
+
+// Delete ...
+func (s *BlobStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.blobs[key]; !ok {
+		return store.ErrRecordNotFound
+	}
+	delete(s.blobs, key)
+	return nil
+}
+
+type blob struct {
+	*bytes.Reader
+}
+
+func (b *blob) Close() error {
+	return nil
+}
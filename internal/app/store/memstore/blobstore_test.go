@@ -0,0 +1,34 @@
+package memstore_test
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/memstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlobStore(t *testing.T) {
+	s := memstore.New()
+
+	n, err := s.Put("blob-1", strings.NewReader("content"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), n)
+
+	_, err = s.Put("../blob", strings.NewReader("content"))
+	assert.EqualError(t, err, store.ErrInvalidBlobKey.Error())
+
+	b, err := s.Open("blob-1")
+	assert.NoError(t, err)
+	content, err := ioutil.ReadAll(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+	assert.NoError(t, b.Close())
+
+	assert.NoError(t, s.Delete("blob-1"))
+	_, err = s.Open("blob-1")
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+	assert.EqualError(t, s.Delete("blob-1"), store.ErrRecordNotFound.Error())
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	// SortCreatedAt ...
+	SortCreatedAt = "created_at"
+	// SortUpdatedAt ...
+	SortUpdatedAt = "updated_at"
+	// SortHeader ...
+	SortHeader = "header"
+
+	// ArchivedExclude ...
+	ArchivedExclude = "exclude"
+	// ArchivedInclude ...
+	ArchivedInclude = "include"
+	// ArchivedOnly ...
+	ArchivedOnly = "only"
+
+	// DefaultNoteLimit ...
+	DefaultNoteLimit = 20
+	// MaxNoteLimit ...
+	MaxNoteLimit = 100
+)
+
+// NoteQuery describes one page of notes listing
+type NoteQuery struct {
+	NotebookID    int
+	Archived      string
+	StarredOnly   bool
+	PinnedFirst   bool
+	Tags          []string
+	MatchAllTags  bool
+	Sort          string
+	Desc          bool
+	Limit         int
+	Cursor        string
+	CreatedAfter  time.Time
+	UpdatedBefore time.Time
+}
+
+// NewNoteQuery returns a query for the first page of most recently updated
+// not archived notes, pinned notes go first
+func NewNoteQuery() *NoteQuery {
+	return &NoteQuery{
+		Archived:    ArchivedExclude,
+		PinnedFirst: true,
+		Sort:        SortUpdatedAt,
+		Desc:        true,
+		Limit:       DefaultNoteLimit,
+	}
+}
+
+// Validate ...
+func (q *NoteQuery) Validate() error {
+	return validation.ValidateStruct(
+		q,
+		validation.Field(&q.Archived, validation.Required, validation.In(ArchivedExclude, ArchivedInclude, ArchivedOnly)),
+		validation.Field(&q.Sort, validation.Required, validation.In(SortCreatedAt, SortUpdatedAt, SortHeader)),
+		validation.Field(&q.Limit, validation.Required, validation.Min(1), validation.Max(MaxNoteLimit)),
+	)
+}
+
+// Cursor points at the last note of a page, notes of the next page
+// go strictly after it in the query order
+type Cursor struct {
+	Sort        string    `json:"s"`
+	Desc        bool      `json:"d,omitempty"`
+	PinnedFirst bool      `json:"pf,omitempty"`
+	Pinned      bool      `json:"p,omitempty"`
+	ID          int       `json:"id"`
+	Time        time.Time `json:"t,omitempty"`
+	Header      string    `json:"h,omitempty"`
+}
+
+// NextCursor returns cursor pointing at the note in the query order
+func (q *NoteQuery) NextCursor(n *model.Note) string {
+	c := &Cursor{
+		Sort:        q.Sort,
+		Desc:        q.Desc,
+		PinnedFirst: q.PinnedFirst,
+		ID:          n.ID,
+	}
+	if q.PinnedFirst {
+		c.Pinned = n.Pinned
+	}
+	switch q.Sort {
+	case SortCreatedAt:
+		c.Time = n.CreatedAt
+	case SortUpdatedAt:
+		c.Time = n.UpdatedAt
+	case SortHeader:
+		c.Header = n.Header
+	}
+	return c.Encode()
+}
+
+// Encode ...
+func (c *Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses cursor of the query, the cursor must be produced
+// with the same sort field, direction and pinned notes order
+func (q *NoteQuery) DecodeCursor() (*Cursor, error) {
+	if q.Cursor == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	c := &Cursor{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != q.Sort || c.Desc != q.Desc || c.PinnedFirst != q.PinnedFirst || c.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,84 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoteQuery_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		q       func() *store.NoteQuery
+		isValid bool
+	}{
+		{
+			name: "default",
+			q: func() *store.NoteQuery {
+				return store.NewNoteQuery()
+			},
+			isValid: true,
+		},
+		{
+			name: "unknown sort",
+			q: func() *store.NoteQuery {
+				q := store.NewNoteQuery()
+				q.Sort = "body"
+				return q
+			},
+			isValid: false,
+		},
+		{
+			name: "unknown archived mode",
+			q: func() *store.NoteQuery {
+				q := store.NewNoteQuery()
+				q.Archived = "all"
+				return q
+			},
+			isValid: false,
+		},
+		{
+			name: "large limit",
+			q: func() *store.NoteQuery {
+				q := store.NewNoteQuery()
+				q.Limit = store.MaxNoteLimit + 1
+				return q
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.isValid {
+			assert.NoError(t, tc.q().Validate())
+		} else {
+			assert.Error(t, tc.q().Validate())
+		}
+	}
+}
+
+func TestNoteQuery_DecodeCursor(t *testing.T) {
+	q := store.NewNoteQuery()
+	c, err := q.DecodeCursor()
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+
+	n := model.TestNote(t)
+	n.ID = 10
+	q.Cursor = q.NextCursor(n)
+	c, err = q.DecodeCursor()
+	assert.NoError(t, err)
+	assert.Equal(t, n.ID, c.ID)
+	assert.True(t, n.UpdatedAt.Equal(c.Time))
+
+	q.PinnedFirst = false
+	_, err = q.DecodeCursor()
+	assert.EqualError(t, err, store.ErrInvalidCursor.Error())
+
+	q.PinnedFirst = true
+	q.Sort = store.SortHeader
+	_, err = q.DecodeCursor()
+	assert.EqualError(t, err, store.ErrInvalidCursor.Error())
+}
@@ -1,19 +1,213 @@
 package store
 
-import "github.com/KapitanD/http-api-server/internal/app/model"
+import (
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+)
 
 // UserRepository ...
 type UserRepository interface {
 	Create(*model.User) error
 	FindByEmail(string) (*model.User, error)
 	Find(int) (*model.User, error)
+	UpdatePassword(*model.User) error
+	MarkVerified(int, time.Time) error
 }
 
+// Flags of notes
+const (
+	FlagPinned   = "pinned"
+	FlagArchived = "archived"
+	FlagStarred  = "starred"
+)
+
 // NoteRepository ...
 type NoteRepository interface {
 	Create(*model.Note, *model.User) error
 	Update(int, *model.Note) error
 	Delete(int) error
-	FindByUser(*model.User) ([]*model.Note, error)
+	Trash(int) error
+	Restore(int) error
+	Move(int, *int) error
+	SetFlag(int, string, bool) error
+	PurgeTrash(time.Time) (int, error)
+	Bulk([]*BulkItem, bool) error
+	EachByUser(*model.User, func(*model.Note) error) error
+	FindPage(*model.User, *NoteQuery) ([]*model.Note, string, error)
 	FindByID(int) (*model.Note, error)
+	CountByUser(*model.User) (int, error)
+	FindTrash(*model.User) ([]*model.Note, error)
+	FindTrashedByID(int) (*model.Note, error)
+	Search(*model.User, *SearchQuery) ([]*model.SearchResult, error)
+}
+
+// TagRepository ...
+type TagRepository interface {
+	SetNoteTags(*model.Note, []string) error
+	FindByUser(*model.User) ([]*model.Tag, error)
+}
+
+// RevisionRepository ...
+type RevisionRepository interface {
+	FindByNote(int) ([]*model.NoteRevision, error)
+	Find(int, int) (*model.NoteRevision, error)
+	Prune(int, int) error
+}
+
+// ShareRepository ...
+type ShareRepository interface {
+	Save(*model.NoteShare) error
+	Delete(int, int) error
+	Find(int, int) (*model.NoteShare, error)
+	FindByNote(int) ([]*model.NoteShare, error)
+	FindByUser(*model.User) ([]*model.NoteShare, error)
+}
+
+// LinkRepository ...
+type LinkRepository interface {
+	Create(*model.NoteLink) error
+	Delete(int, string) error
+	Find(string) (*model.NoteLink, error)
+	FindByNote(int) ([]*model.NoteLink, error)
+	RecordView(*model.NoteLink) error
+	RecordFailedAttempt(*model.NoteLink, int, time.Duration) error
+}
+
+// Modes of notebook deletion
+const (
+	// NotebookDeleteCascade deletes nested notebooks and moves notes to trash
+	NotebookDeleteCascade = "cascade"
+	// NotebookDeleteMoveToParent moves nested notebooks and notes to the parent
+	NotebookDeleteMoveToParent = "move"
+)
+
+// NotebookRepository ...
+type NotebookRepository interface {
+	Create(*model.Notebook) error
+	Rename(int, string) error
+	Move(int, *int) error
+	Delete(int, string) error
+	Find(int) (*model.Notebook, error)
+	FindByUser(*model.User) ([]*model.Notebook, error)
+}
+
+// AttachmentRepository ...
+type AttachmentRepository interface {
+	Create(*model.Attachment) error
+	Delete(int) error
+	Find(int) (*model.Attachment, error)
+	FindByNote(int) ([]*model.Attachment, error)
+	FindTrashedBefore(time.Time) ([]*model.Attachment, error)
+}
+
+// TemplateRepository ...
+type TemplateRepository interface {
+	Create(*model.Template) error
+	Update(*model.Template) error
+	Delete(int) error
+	Find(int) (*model.Template, error)
+	FindByUser(*model.User) ([]*model.Template, error)
+}
+
+// ReferenceRepository ...
+type ReferenceRepository interface {
+	FindBySource(int) ([]*model.NoteReference, error)
+	FindByTarget(int) ([]*model.NoteReference, error)
+}
+
+// ReminderRepository ...
+type ReminderRepository interface {
+	Create(*model.Reminder) error
+	Update(*model.Reminder) error
+	Delete(int) error
+	Find(int) (*model.Reminder, error)
+	FindByNote(int, *model.User) ([]*model.Reminder, error)
+	FindByUser(*model.User) ([]*model.Reminder, error)
+	FireDue(time.Time, int, func(*model.Reminder) error) (int, error)
+}
+
+// NotificationRepository ...
+type NotificationRepository interface {
+	Create(*model.Notification) error
+	MarkRead(int, *model.User) error
+	FindByUser(*model.User, bool) ([]*model.Notification, error)
+}
+
+// ChecklistRepository ...
+type ChecklistRepository interface {
+	Create(*model.ChecklistItem) error
+	Update(*model.ChecklistItem) error
+	Move(int, int) error
+	Delete(int) error
+	Find(int) (*model.ChecklistItem, error)
+	FindByNote(int) ([]*model.ChecklistItem, error)
+}
+
+// CommentRepository ...
+type CommentRepository interface {
+	Create(*model.Comment) error
+	Update(*model.Comment) error
+	Delete(int) error
+	Find(int) (*model.Comment, error)
+	FindByNote(int) ([]*model.Comment, error)
+}
+
+// SessionRepository ...
+type SessionRepository interface {
+	Create(*model.Session) error
+	Touch(int, string, time.Time) error
+	Delete(int) error
+	DeleteByUser(int, int) (int, error)
+	Find(int) (*model.Session, error)
+	FindByToken(string) (*model.Session, error)
+	FindByUser(*model.User) ([]*model.Session, error)
+}
+
+// RefreshTokenRepository ...
+type RefreshTokenRepository interface {
+	Create(*model.RefreshToken) error
+	FindByToken(string) (*model.RefreshToken, error)
+	Use(int, time.Time) error
+	RevokeFamily(string, time.Time) error
+	RevokeByUser(int, time.Time) error
+}
+
+// APITokenRepository ...
+type APITokenRepository interface {
+	Create(*model.APIToken) error
+	Touch(int, time.Time) error
+	Delete(int) error
+	DeleteByUser(int) error
+	Find(int) (*model.APIToken, error)
+	FindByToken(string) (*model.APIToken, error)
+	FindByUser(*model.User) ([]*model.APIToken, error)
+}
+
+// PasswordResetRepository ...
+type PasswordResetRepository interface {
+	Create(*model.PasswordReset) error
+	FindByToken(string) (*model.PasswordReset, error)
+	FindLatest(int) (*model.PasswordReset, error)
+	Use(int, *model.User, time.Time) error
+	DeleteByUser(int) error
+}
+
+// EmailVerificationRepository ...
+type EmailVerificationRepository interface {
+	Create(*model.EmailVerification) error
+	FindByToken(string) (*model.EmailVerification, error)
+	FindLatest(int) (*model.EmailVerification, error)
+	DeleteByUser(int) error
+}
+
+// ImportJobRepository ...
+type ImportJobRepository interface {
+	Create(*model.ImportJob) error
+	Find(string, int) (*model.ImportJob, error)
+	AddFile(string, *model.ImportFileResult) error
+	Touch(string, time.Time) error
+	Finish(*model.ImportJob) error
+	FailStale(time.Time, string) (int, error)
+	DeleteFinishedBefore(time.Time) (int, error)
 }
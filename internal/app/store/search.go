@@ -0,0 +1,167 @@
+package store
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	// HighlightStart ...
+	HighlightStart = "<mark>"
+	// HighlightStop ...
+	HighlightStop = "</mark>"
+
+	// HighlightStartSentinel and HighlightStopSentinel delimit matches
+	// before the snippet is escaped, they never survive html escaping
+	// as markup so note text can't forge or break the highlight tags
+	HighlightStartSentinel = "\x02"
+	// HighlightStopSentinel ...
+	HighlightStopSentinel = "\x03"
+)
+
+var snippetReplacer = strings.NewReplacer(
+	HighlightStartSentinel, HighlightStart,
+	HighlightStopSentinel, HighlightStop,
+)
+
+// EscapeSnippet html-escapes the text of a snippet and turns
+// sentinel delimited matches into HighlightStart and HighlightStop
+func EscapeSnippet(s string) string {
+	return snippetReplacer.Replace(html.EscapeString(s))
+}
+
+// SearchTerm is a word, a "quoted phrase" or a word* prefix of a search query,
+// negated terms start with minus and exclude notes containing them
+type SearchTerm struct {
+	Words  []string
+	Prefix bool
+	Negate bool
+}
+
+// SearchQuery ...
+type SearchQuery struct {
+	Terms []*SearchTerm
+	Limit int
+}
+
+// ParseSearchQuery ...
+func ParseSearchQuery(q string) (*SearchQuery, error) {
+	sq := &SearchQuery{
+		Limit: DefaultNoteLimit,
+	}
+
+	positive := false
+	for _, raw := range splitSearchQuery(q) {
+		t := &SearchTerm{}
+		if strings.HasPrefix(raw, "-") {
+			t.Negate = true
+			raw = raw[1:]
+		}
+		if strings.HasPrefix(raw, `"`) {
+			raw = strings.Trim(raw, `"`)
+		} else if strings.HasSuffix(raw, "*") {
+			t.Prefix = true
+		}
+
+		t.Words = Tokenize(raw)
+		if len(t.Words) == 0 {
+			continue
+		}
+		if !t.Negate {
+			positive = true
+		}
+		sq.Terms = append(sq.Terms, t)
+	}
+
+	if !positive {
+		return nil, ErrInvalidSearchQuery
+	}
+	return sq, nil
+}
+
+// Tokenize splits text to lower case words of letters and digits
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), isSeparator)
+}
+
+// Matches reports whether the token is the i-th word of the term
+func (t *SearchTerm) Matches(i int, token string) bool {
+	if t.Prefix && i == len(t.Words)-1 {
+		return strings.HasPrefix(token, t.Words[i])
+	}
+	return token == t.Words[i]
+}
+
+// Highlight html-escapes the text and wraps words matching positive terms
+// of the query with HighlightStart and HighlightStop
+func (sq *SearchQuery) Highlight(text string) string {
+	b := &strings.Builder{}
+	word := &strings.Builder{}
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+		w := word.String()
+		if sq.matchesAny(strings.ToLower(w)) {
+			b.WriteString(HighlightStartSentinel + w + HighlightStopSentinel)
+		} else {
+			b.WriteString(w)
+		}
+		word.Reset()
+	}
+
+	for _, r := range text {
+		if isSeparator(r) {
+			flush()
+			b.WriteRune(r)
+			continue
+		}
+		word.WriteRune(r)
+	}
+	flush()
+
+	return EscapeSnippet(b.String())
+}
+
+func (sq *SearchQuery) matchesAny(token string) bool {
+	for _, t := range sq.Terms {
+		if t.Negate {
+			continue
+		}
+		for i := range t.Words {
+			if t.Matches(i, token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func splitSearchQuery(q string) []string {
+	result := []string{}
+	b := &strings.Builder{}
+	quoted := false
+	for _, r := range q {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			b.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if b.Len() > 0 {
+				result = append(result, b.String())
+				b.Reset()
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() > 0 {
+		result = append(result, b.String())
+	}
+	return result
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -0,0 +1,57 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	sq, err := store.ParseSearchQuery(`Meeting "next week" proj* -draft`)
+	assert.NoError(t, err)
+	assert.Equal(t, []*store.SearchTerm{
+		{Words: []string{"meeting"}},
+		{Words: []string{"next", "week"}},
+		{Words: []string{"proj"}, Prefix: true},
+		{Words: []string{"draft"}, Negate: true},
+	}, sq.Terms)
+
+	_, err = store.ParseSearchQuery("-draft")
+	assert.EqualError(t, err, store.ErrInvalidSearchQuery.Error())
+
+	_, err = store.ParseSearchQuery(` "" * `)
+	assert.EqualError(t, err, store.ErrInvalidSearchQuery.Error())
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"hello", "world", "2021"}, store.Tokenize("Hello, world! (2021)"))
+}
+
+func TestSearchQuery_Highlight(t *testing.T) {
+	sq, err := store.ParseSearchQuery(`proj* -draft`)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"<mark>Project</mark> draft, <mark>projects</mark>.",
+		sq.Highlight("Project draft, projects."),
+	)
+}
+
+func TestSearchQuery_HighlightEscapes(t *testing.T) {
+	sq, err := store.ParseSearchQuery(`img`)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"&lt;<mark>img</mark> src=x onerror=&#34;alert(1)&#34;&gt; &lt;/mark&gt;",
+		sq.Highlight(`<img src=x onerror="alert(1)"> </mark>`),
+	)
+}
+
+func TestEscapeSnippet(t *testing.T) {
+	assert.Equal(
+		t,
+		"&lt;script&gt;<mark>alert</mark>&lt;/script&gt;",
+		store.EscapeSnippet("<script>"+store.HighlightStartSentinel+"alert"+store.HighlightStopSentinel+"</script>"),
+	)
+}
@@ -0,0 +1,177 @@
+package sessionstore
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+)
+
+const (
+	// touchInterval limits how often the last-seen time of a session is written
+	touchInterval = time.Minute
+	maxDeviceLen  = 255
+)
+
+// Store keeps sessions in the repository, the cookie holds only the signed
+// token of the session. The only value kept is "user_id", sessions without
+// it aren't saved
+type Store struct {
+	repo    store.SessionRepository
+	codecs  []securecookie.Codec
+	maxAge  time.Duration
+	Options *sessions.Options
+}
+
+// New creates the store of sessions expiring in maxAge after the login,
+// key pairs are used to sign cookies as in sessions.NewCookieStore
+func New(repo store.SessionRepository, maxAge time.Duration, keyPairs ...[]byte) *Store {
+	codecs := securecookie.CodecsFromPairs(keyPairs...)
+	for _, c := range codecs {
+		if sc, ok := c.(*securecookie.SecureCookie); ok {
+			sc.MaxAge(int(maxAge.Seconds()))
+		}
+	}
+
+	return &Store{
+		repo:   repo,
+		codecs: codecs,
+		maxAge: maxAge,
+		Options: &sessions.Options{
+			Path:     "/",
+			MaxAge:   int(maxAge.Seconds()),
+			HttpOnly: true,
+		},
+	}
+}
+
+// Get returns the session cached for the request
+func (s *Store) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+// New loads the session of the cookie, a new session is returned when
+// the cookie is missing, invalid or the session is revoked
+func (s *Store) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	opts := *s.Options
+	session.Options = &opts
+	session.IsNew = true
+
+	c, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+
+	var token string
+	if err := securecookie.DecodeMulti(name, c.Value, &token, s.codecs...); err != nil {
+		return session, nil
+	}
+
+	rec, err := s.repo.FindByToken(model.HashToken(token))
+	if err == store.ErrRecordNotFound {
+		return session, nil
+	}
+	if err != nil {
+		return session, err
+	}
+
+	now := time.Now()
+	ip := remoteIP(r)
+	if now.Sub(rec.LastSeenAt) >= touchInterval || ip != rec.IP {
+		if err := s.repo.Touch(rec.ID, ip, now); err != nil && err != store.ErrRecordNotFound {
+			return session, err
+		}
+	}
+
+	session.ID = strconv.Itoa(rec.ID)
+	session.Values["user_id"] = rec.UserID
+	session.IsNew = false
+	return session, nil
+}
+
+// Save deletes the session when MaxAge is negative or the user is
+// unset, a session of another user replaces the current one
+func (s *Store) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	userID, ok := session.Values["user_id"].(int)
+	if session.Options.MaxAge < 0 || !ok {
+		if err := s.delete(session); err != nil {
+			return err
+		}
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	if session.ID != "" {
+		id, _ := strconv.Atoi(session.ID)
+		rec, err := s.repo.Find(id)
+		if err == nil && rec.UserID == userID {
+			return nil
+		}
+		if err := s.delete(session); err != nil {
+			return err
+		}
+	}
+
+	rec := &model.Session{
+		UserID:    userID,
+		Device:    device(r),
+		IP:        remoteIP(r),
+		ExpiresAt: time.Now().Add(s.maxAge),
+	}
+	if err := s.repo.Create(rec); err != nil {
+		return err
+	}
+
+	encoded, err := securecookie.EncodeMulti(session.Name(), rec.Token, s.codecs...)
+	if err != nil {
+		return err
+	}
+
+	session.ID = strconv.Itoa(rec.ID)
+	session.IsNew = false
+	http.SetCookie(w, sessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
+
+func (s *Store) delete(session *sessions.Session) error {
+	if session.ID == "" {
+		return nil
+	}
+
+	id, _ := strconv.Atoi(session.ID)
+	if err := s.repo.Delete(id); err != nil && err != store.ErrRecordNotFound {
+		return err
+	}
+	session.ID = ""
+	return nil
+}
+
+// device is the User-Agent of the request as valid UTF-8, cut at a rune
+// boundary to fit the column
+func device(r *http.Request) string {
+	d := strings.ToValidUTF8(r.UserAgent(), "")
+	if len(d) <= maxDeviceLen {
+		return d
+	}
+	i := maxDeviceLen
+	for i > 0 && !utf8.RuneStart(d[i]) {
+		i--
+	}
+	return d[:i]
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,148 @@
+package sessionstore_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/sessionstore"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/stretchr/testify/assert"
+)
+
+const sessionName = "session"
+
+func login(t *testing.T, s *sessionstore.Store, userID int) *http.Cookie {
+	t.Helper()
+	return loginAs(t, s, userID, "curl/7.68.0")
+}
+
+func loginAs(t *testing.T, s *sessionstore.Store, userID int, userAgent string) *http.Cookie {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/sessions", nil)
+	req.Header.Set("User-Agent", userAgent)
+	rec := httptest.NewRecorder()
+	session, err := s.Get(req, sessionName)
+	assert.NoError(t, err)
+	assert.True(t, session.IsNew)
+
+	session.Values["user_id"] = userID
+	assert.NoError(t, s.Save(req, rec, session))
+	cookies := rec.Result().Cookies()
+	if !assert.Len(t, cookies, 1) {
+		t.FailNow()
+	}
+	return cookies[0]
+}
+
+func TestStore_Login(t *testing.T) {
+	st := teststore.New()
+	u := model.TestUser(t)
+	st.User().Create(u)
+	s := sessionstore.New(st.Sessions(), time.Hour, []byte("secret"))
+
+	cookie := login(t, s, u.ID)
+	assert.True(t, cookie.HttpOnly)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookie)
+	session, err := s.Get(req, sessionName)
+	assert.NoError(t, err)
+	assert.False(t, session.IsNew)
+	assert.Equal(t, u.ID, session.Values["user_id"])
+
+	sl, err := st.Sessions().FindByUser(u)
+	assert.NoError(t, err)
+	if assert.Len(t, sl, 1) {
+		assert.Equal(t, "curl/7.68.0", sl[0].Device)
+		assert.Equal(t, "192.0.2.1", sl[0].IP)
+	}
+}
+
+func TestStore_LoginLongUserAgent(t *testing.T) {
+	st := teststore.New()
+	u := model.TestUser(t)
+	st.User().Create(u)
+	s := sessionstore.New(st.Sessions(), time.Hour, []byte("secret"))
+
+	// "я" is two bytes, the 255th byte falls inside a character
+	loginAs(t, s, u.ID, strings.Repeat("я", 200))
+
+	sl, err := st.Sessions().FindByUser(u)
+	assert.NoError(t, err)
+	if assert.Len(t, sl, 1) {
+		assert.True(t, utf8.ValidString(sl[0].Device))
+		assert.Equal(t, strings.Repeat("я", 127), sl[0].Device)
+	}
+}
+
+func TestStore_Invalid(t *testing.T) {
+	st := teststore.New()
+	u := model.TestUser(t)
+	st.User().Create(u)
+	s := sessionstore.New(st.Sessions(), time.Hour, []byte("secret"))
+	cookie := login(t, s, u.ID)
+
+	testCases := []struct {
+		name   string
+		cookie *http.Cookie
+		before func()
+	}{
+		{
+			name:   "tampered",
+			cookie: &http.Cookie{Name: sessionName, Value: cookie.Value + "x"},
+		},
+		{
+			name:   "another key",
+			cookie: login(t, sessionstore.New(st.Sessions(), time.Hour, []byte("other")), u.ID),
+		},
+		{
+			name:   "revoked",
+			cookie: cookie,
+			before: func() {
+				st.Sessions().DeleteByUser(u.ID, 0)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.before != nil {
+				tc.before()
+			}
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(tc.cookie)
+			session, err := s.Get(req, sessionName)
+			assert.NoError(t, err)
+			assert.True(t, session.IsNew)
+			assert.Nil(t, session.Values["user_id"])
+		})
+	}
+}
+
+func TestStore_Logout(t *testing.T) {
+	st := teststore.New()
+	u := model.TestUser(t)
+	st.User().Create(u)
+	s := sessionstore.New(st.Sessions(), time.Hour, []byte("secret"))
+	cookie := login(t, s, u.ID)
+
+	req := httptest.NewRequest(http.MethodDelete, "/sessions/current", nil)
+	req.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	session, _ := s.Get(req, sessionName)
+	session.Options.MaxAge = -1
+	assert.NoError(t, s.Save(req, rec, session))
+	if assert.Len(t, rec.Result().Cookies(), 1) {
+		assert.Equal(t, -1, rec.Result().Cookies()[0].MaxAge)
+	}
+
+	sl, err := st.Sessions().FindByUser(u)
+	assert.NoError(t, err)
+	assert.Len(t, sl, 0)
+}
@@ -0,0 +1,132 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/lib/pq"
+)
+
+const apiTokenColumns = "id, user_id, name, token_hash, scopes, expires_at, last_used_at, created_at"
+
+// APITokenRepository ...
+type APITokenRepository struct {
+	store *Store
+}
+
+// Create ...
+func (r *APITokenRepository) Create(t *model.APIToken) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	if err := t.BeforeCreate(); err != nil {
+		return err
+	}
+
+	return r.store.db.QueryRow(
+		"INSERT INTO api_tokens (user_id, name, token_hash, scopes, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at;",
+		t.UserID,
+		t.Name,
+		t.TokenHash,
+		pq.Array(t.Scopes),
+		t.ExpiresAt,
+	).Scan(&t.ID, &t.CreatedAt)
+}
+
+// Touch records the use of the token
+func (r *APITokenRepository) Touch(id int, at time.Time) error {
+	return execOne(
+		r.store.db,
+		"UPDATE api_tokens SET last_used_at = $2 WHERE id = $1;",
+		id,
+		at,
+	)
+}
+
+// Delete ...
+func (r *APITokenRepository) Delete(id int) error {
+	return execOne(
+		r.store.db,
+		"DELETE FROM api_tokens WHERE id = $1;",
+		id,
+	)
+}
+
+// DeleteByUser ...
+func (r *APITokenRepository) DeleteByUser(userID int) error {
+	_, err := r.store.db.Exec(
+		"DELETE FROM api_tokens WHERE user_id = $1;",
+		userID,
+	)
+	return err
+}
+
+// Find returns the token even if it's expired
+func (r *APITokenRepository) Find(id int) (*model.APIToken, error) {
+	return r.find(
+		"SELECT "+apiTokenColumns+" FROM api_tokens WHERE id = $1",
+		id,
+	)
+}
+
+// FindByToken returns the token with the hash unless it's expired
+func (r *APITokenRepository) FindByToken(hash string) (*model.APIToken, error) {
+	return r.find(
+		"SELECT "+apiTokenColumns+" FROM api_tokens WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > $2)",
+		hash,
+		time.Now(),
+	)
+}
+
+// FindByUser returns all tokens of the user, the latest first
+func (r *APITokenRepository) FindByUser(u *model.User) ([]*model.APIToken, error) {
+	rows, err := r.store.db.Query(
+		"SELECT "+apiTokenColumns+" FROM api_tokens WHERE user_id = $1 ORDER BY created_at DESC, id DESC",
+		u.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.APIToken{}
+	for rows.Next() {
+		t, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, rows.Err()
+}
+
+func (r *APITokenRepository) find(query string, args ...interface{}) (*model.APIToken, error) {
+	t, err := scanAPIToken(r.store.db.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func scanAPIToken(row scanner) (*model.APIToken, error) {
+	t := &model.APIToken{}
+	if err := row.Scan(
+		&t.ID,
+		&t.UserID,
+		&t.Name,
+		&t.TokenHash,
+		pq.Array(&t.Scopes),
+		&t.ExpiresAt,
+		&t.LastUsedAt,
+		&t.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
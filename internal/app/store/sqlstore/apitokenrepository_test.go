@@ -0,0 +1,141 @@
+package sqlstore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/sqlstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPITokenRepository_Create(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("api_tokens", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	tok := model.TestAPIToken(t)
+	tok.UserID = u.ID
+	tok.Scopes = []string{model.ScopeNotesWrite, model.ScopeNotesRead}
+	assert.NoError(t, s.APITokens().Create(tok))
+	assert.NotZero(t, tok.ID)
+	assert.NotEmpty(t, tok.Token)
+
+	ft, err := s.APITokens().FindByToken(model.HashToken(tok.Token))
+	assert.NoError(t, err)
+	assert.Equal(t, tok.ID, ft.ID)
+	assert.Empty(t, ft.Token)
+	assert.Equal(t, []string{model.ScopeNotesRead, model.ScopeNotesWrite}, ft.Scopes)
+
+	assert.Error(t, s.APITokens().Create(&model.APIToken{UserID: u.ID, Name: "no scopes"}))
+}
+
+func TestAPITokenRepository_FindByToken(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("api_tokens", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	tok := model.TestAPIToken(t)
+	tok.UserID = u.ID
+	expiresAt := time.Now().Add(time.Hour)
+	tok.ExpiresAt = &expiresAt
+	s.APITokens().Create(tok)
+
+	_, err := s.APITokens().FindByToken(model.HashToken(tok.Token))
+	assert.NoError(t, err)
+	_, err = s.APITokens().FindByToken(model.HashToken("pat_unknown"))
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	deleted := model.TestAPIToken(t)
+	deleted.UserID = u.ID
+	s.APITokens().Create(deleted)
+	s.APITokens().Delete(deleted.ID)
+	_, err = s.APITokens().FindByToken(model.HashToken(deleted.Token))
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestAPITokenRepository_Touch(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("api_tokens", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+	tok := model.TestAPIToken(t)
+	tok.UserID = u.ID
+	s.APITokens().Create(tok)
+
+	at := time.Now()
+	assert.NoError(t, s.APITokens().Touch(tok.ID, at))
+	assert.EqualError(t, s.APITokens().Touch(tok.ID+1, at), store.ErrRecordNotFound.Error())
+
+	ft, err := s.APITokens().Find(tok.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, ft.LastUsedAt) {
+		assert.WithinDuration(t, at, *ft.LastUsedAt, time.Millisecond)
+	}
+}
+
+func TestAPITokenRepository_FindByUser(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("api_tokens", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	s.User().Create(other)
+
+	var last *model.APIToken
+	for _, user := range []*model.User{u, u, other} {
+		tok := model.TestAPIToken(t)
+		tok.UserID = user.ID
+		s.APITokens().Create(tok)
+		if user == u {
+			last = tok
+		}
+	}
+
+	tl, err := s.APITokens().FindByUser(u)
+	assert.NoError(t, err)
+	if assert.Len(t, tl, 2) {
+		assert.Equal(t, last.ID, tl[0].ID)
+	}
+
+	assert.NoError(t, s.APITokens().Delete(tl[0].ID))
+	assert.EqualError(t, s.APITokens().Delete(tl[0].ID), store.ErrRecordNotFound.Error())
+}
+
+func TestAPITokenRepository_DeleteByUser(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("api_tokens", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	s.User().Create(other)
+
+	for _, user := range []*model.User{u, u, other} {
+		tok := model.TestAPIToken(t)
+		tok.UserID = user.ID
+		s.APITokens().Create(tok)
+	}
+
+	assert.NoError(t, s.APITokens().DeleteByUser(u.ID))
+	tl, err := s.APITokens().FindByUser(u)
+	assert.NoError(t, err)
+	assert.Empty(t, tl)
+	tl, err = s.APITokens().FindByUser(other)
+	assert.NoError(t, err)
+	assert.Len(t, tl, 1)
+}
@@ -0,0 +1,107 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+const attachmentColumns = "a.id, a.note_id, a.filename, a.content_type, a.size, a.storage_key, a.created_at"
+
+// AttachmentRepository ...
+type AttachmentRepository struct {
+	store *Store
+}
+
+// Create ...
+func (r *AttachmentRepository) Create(a *model.Attachment) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+
+	return r.store.db.QueryRow(
+		"INSERT INTO note_attachments (note_id, filename, content_type, size, storage_key) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at;",
+		a.NoteID,
+		a.Filename,
+		a.ContentType,
+		a.Size,
+		a.StorageKey,
+	).Scan(&a.ID, &a.CreatedAt)
+}
+
+// Delete ...
+func (r *AttachmentRepository) Delete(id int) error {
+	return execOne(
+		r.store.db,
+		"DELETE FROM note_attachments WHERE id = $1;",
+		id,
+	)
+}
+
+// Find ...
+func (r *AttachmentRepository) Find(id int) (*model.Attachment, error) {
+	a, err := scanAttachment(r.store.db.QueryRow(
+		"SELECT "+attachmentColumns+" FROM note_attachments a WHERE a.id = $1",
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+// FindByNote ...
+func (r *AttachmentRepository) FindByNote(noteID int) ([]*model.Attachment, error) {
+	return r.query(
+		"SELECT "+attachmentColumns+" FROM note_attachments a WHERE a.note_id = $1 ORDER BY a.id",
+		noteID,
+	)
+}
+
+// FindTrashedBefore returns attachments of notes moved to trash before the time
+func (r *AttachmentRepository) FindTrashedBefore(before time.Time) ([]*model.Attachment, error) {
+	return r.query(
+		"SELECT "+attachmentColumns+" FROM note_attachments a JOIN notes n ON n.id = a.note_id "+
+			"WHERE n.deleted_at < $1 ORDER BY a.id",
+		before,
+	)
+}
+
+func (r *AttachmentRepository) query(query string, args ...interface{}) ([]*model.Attachment, error) {
+	rows, err := r.store.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.Attachment{}
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, a)
+	}
+	return result, rows.Err()
+}
+
+func scanAttachment(row scanner) (*model.Attachment, error) {
+	a := &model.Attachment{}
+	if err := row.Scan(
+		&a.ID,
+		&a.NoteID,
+		&a.Filename,
+		&a.ContentType,
+		&a.Size,
+		&a.StorageKey,
+		&a.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
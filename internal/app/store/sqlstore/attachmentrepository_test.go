@@ -0,0 +1,113 @@
+package sqlstore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/sqlstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachmentRepository_Create(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_attachments", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.Notes().Create(n, u)
+
+	a := model.TestAttachment(t)
+	a.NoteID = n.ID
+	assert.NoError(t, s.Attachments().Create(a))
+	assert.NotZero(t, a.ID)
+
+	ra, err := s.Attachments().Find(a.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, a.StorageKey, ra.StorageKey)
+
+	assert.Error(t, s.Attachments().Create(&model.Attachment{NoteID: n.ID}))
+}
+
+func TestAttachmentRepository_Delete(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_attachments", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.Notes().Create(n, u)
+
+	a := model.TestAttachment(t)
+	a.NoteID = n.ID
+	s.Attachments().Create(a)
+
+	assert.NoError(t, s.Attachments().Delete(a.ID))
+	_, err := s.Attachments().Find(a.ID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+	assert.EqualError(t, s.Attachments().Delete(a.ID), store.ErrRecordNotFound.Error())
+}
+
+func TestAttachmentRepository_FindByNote(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_attachments", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	n1 := model.TestNote(t)
+	n2 := model.TestNote(t)
+	s.User().Create(u)
+	s.Notes().Create(n1, u)
+	s.Notes().Create(n2, u)
+
+	for i, n := range []*model.Note{n1, n1, n2} {
+		a := model.TestAttachment(t)
+		a.NoteID = n.ID
+		a.StorageKey = a.StorageKey + string(rune('a'+i))
+		s.Attachments().Create(a)
+	}
+
+	al, err := s.Attachments().FindByNote(n1.ID)
+	assert.NoError(t, err)
+	assert.Len(t, al, 2)
+
+	assert.NoError(t, s.Notes().Delete(n1.ID))
+	al, err = s.Attachments().FindByNote(n1.ID)
+	assert.NoError(t, err)
+	assert.Len(t, al, 0)
+}
+
+func TestAttachmentRepository_FindTrashedBefore(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_attachments", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	n1 := model.TestNote(t)
+	n2 := model.TestNote(t)
+	s.User().Create(u)
+	s.Notes().Create(n1, u)
+	s.Notes().Create(n2, u)
+
+	for i, n := range []*model.Note{n1, n2} {
+		a := model.TestAttachment(t)
+		a.NoteID = n.ID
+		a.StorageKey = a.StorageKey + string(rune('a'+i))
+		s.Attachments().Create(a)
+	}
+	s.Notes().Trash(n1.ID)
+
+	al, err := s.Attachments().FindTrashedBefore(time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	if assert.Len(t, al, 1) {
+		assert.Equal(t, n1.ID, al[0].NoteID)
+	}
+
+	al, err = s.Attachments().FindTrashedBefore(time.Now().Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, al, 0)
+}
@@ -0,0 +1,235 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+const checklistColumns = "id, note_id, text, done, position, created_at, updated_at"
+
+// ChecklistRepository ...
+type ChecklistRepository struct {
+	store *Store
+}
+
+// Create adds the item to the end of the checklist of its note
+func (r *ChecklistRepository) Create(i *model.ChecklistItem) error {
+	if err := i.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// the note lock serializes positions of its items
+	if _, err := lockNote(tx, i.NoteID); err != nil {
+		return err
+	}
+
+	if err := tx.QueryRow(
+		"INSERT INTO checklist_items (note_id, text, done, position) "+
+			"SELECT $1, $2, $3, COALESCE(MAX(position), 0) + 1 FROM checklist_items WHERE note_id = $1 "+
+			"RETURNING id, position, created_at, updated_at;",
+		i.NoteID,
+		i.Text,
+		i.Done,
+	).Scan(&i.ID, &i.Position, &i.CreatedAt, &i.UpdatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update saves text and done of the item
+func (r *ChecklistRepository) Update(i *model.ChecklistItem) error {
+	if err := i.Validate(); err != nil {
+		return err
+	}
+
+	i.UpdatedAt = time.Now()
+	return execOne(
+		r.store.db,
+		"UPDATE checklist_items SET text = $2, done = $3, updated_at = $4 WHERE id = $1;",
+		i.ID,
+		i.Text,
+		i.Done,
+		i.UpdatedAt,
+	)
+}
+
+// Move puts the item at the position shifting the items between,
+// positions out of the checklist move it to the start or the end
+func (r *ChecklistRepository) Move(id int, position int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	i, err := lockChecklistItem(tx, id)
+	if err != nil {
+		return err
+	}
+
+	var count int
+	if err := tx.QueryRow(
+		"SELECT count(*) FROM checklist_items WHERE note_id = $1",
+		i.NoteID,
+	).Scan(&count); err != nil {
+		return err
+	}
+	if position < 1 {
+		position = 1
+	}
+	if position > count {
+		position = count
+	}
+
+	// positions are unique when the transaction commits only
+	if position < i.Position {
+		_, err = tx.Exec(
+			"UPDATE checklist_items SET position = position + 1 WHERE note_id = $1 AND position >= $2 AND position < $3;",
+			i.NoteID,
+			position,
+			i.Position,
+		)
+	} else if position > i.Position {
+		_, err = tx.Exec(
+			"UPDATE checklist_items SET position = position - 1 WHERE note_id = $1 AND position > $2 AND position <= $3;",
+			i.NoteID,
+			i.Position,
+			position,
+		)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(
+		"UPDATE checklist_items SET position = $2, updated_at = $3 WHERE id = $1;",
+		id,
+		position,
+		time.Now(),
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes the item and closes the gap in positions
+func (r *ChecklistRepository) Delete(id int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	i, err := lockChecklistItem(tx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM checklist_items WHERE id = $1;", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(
+		"UPDATE checklist_items SET position = position - 1 WHERE note_id = $1 AND position > $2;",
+		i.NoteID,
+		i.Position,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Find ...
+func (r *ChecklistRepository) Find(id int) (*model.ChecklistItem, error) {
+	i, err := scanChecklistItem(r.store.db.QueryRow(
+		"SELECT "+checklistColumns+" FROM checklist_items WHERE id = $1",
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return i, nil
+}
+
+// FindByNote returns items of the note ordered by position
+func (r *ChecklistRepository) FindByNote(noteID int) ([]*model.ChecklistItem, error) {
+	rows, err := r.store.db.Query(
+		"SELECT "+checklistColumns+" FROM checklist_items WHERE note_id = $1 ORDER BY position",
+		noteID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.ChecklistItem{}
+	for rows.Next() {
+		i, err := scanChecklistItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, i)
+	}
+	return result, rows.Err()
+}
+
+// lockChecklistItem locks the note of the item and returns the item
+func lockChecklistItem(tx *sql.Tx, id int) (*model.ChecklistItem, error) {
+	var noteID int
+	if err := tx.QueryRow(
+		"SELECT note_id FROM checklist_items WHERE id = $1",
+		id,
+	).Scan(&noteID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	if _, err := lockNote(tx, noteID); err != nil {
+		return nil, err
+	}
+
+	// the item could be moved or deleted before the note was locked
+	i, err := scanChecklistItem(tx.QueryRow(
+		"SELECT "+checklistColumns+" FROM checklist_items WHERE id = $1",
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return i, nil
+}
+
+func scanChecklistItem(row scanner) (*model.ChecklistItem, error) {
+	i := &model.ChecklistItem{}
+	if err := row.Scan(
+		&i.ID,
+		&i.NoteID,
+		&i.Text,
+		&i.Done,
+		&i.Position,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
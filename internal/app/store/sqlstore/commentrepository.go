@@ -0,0 +1,110 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+const commentColumns = "c.id, c.note_id, c.author_id, u.email, c.parent_id, c.body, c.created_at, c.updated_at"
+
+// CommentRepository ...
+type CommentRepository struct {
+	store *Store
+}
+
+// Create ...
+func (r *CommentRepository) Create(c *model.Comment) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	return r.store.db.QueryRow(
+		"INSERT INTO comments (note_id, author_id, parent_id, body) VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at;",
+		c.NoteID,
+		c.AuthorID,
+		c.ParentID,
+		c.Body,
+	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+}
+
+// Update saves the body of the comment
+func (r *CommentRepository) Update(c *model.Comment) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	c.UpdatedAt = time.Now()
+	return execOne(
+		r.store.db,
+		"UPDATE comments SET body = $2, updated_at = $3 WHERE id = $1;",
+		c.ID,
+		c.Body,
+		c.UpdatedAt,
+	)
+}
+
+// Delete deletes the comment with replies to it
+func (r *CommentRepository) Delete(id int) error {
+	return execOne(
+		r.store.db,
+		"DELETE FROM comments WHERE id = $1;",
+		id,
+	)
+}
+
+// Find ...
+func (r *CommentRepository) Find(id int) (*model.Comment, error) {
+	c, err := scanComment(r.store.db.QueryRow(
+		"SELECT "+commentColumns+" FROM comments c JOIN users u ON u.id = c.author_id WHERE c.id = $1",
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// FindByNote returns comments of the note, the oldest first
+func (r *CommentRepository) FindByNote(noteID int) ([]*model.Comment, error) {
+	rows, err := r.store.db.Query(
+		"SELECT "+commentColumns+" FROM comments c JOIN users u ON u.id = c.author_id WHERE c.note_id = $1 ORDER BY c.id",
+		noteID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.Comment{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
+
+func scanComment(row scanner) (*model.Comment, error) {
+	c := &model.Comment{}
+	if err := row.Scan(
+		&c.ID,
+		&c.NoteID,
+		&c.AuthorID,
+		&c.AuthorEmail,
+		&c.ParentID,
+		&c.Body,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
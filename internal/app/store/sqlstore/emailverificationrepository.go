@@ -0,0 +1,82 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+const emailVerificationColumns = "id, user_id, token_hash, created_at, expires_at"
+
+// EmailVerificationRepository ...
+type EmailVerificationRepository struct {
+	store *Store
+}
+
+// Create generates the token and saves the verification, expired
+// verifications of the user are deleted on the way
+func (r *EmailVerificationRepository) Create(v *model.EmailVerification) error {
+	if err := v.BeforeCreate(); err != nil {
+		return err
+	}
+
+	if _, err := r.store.db.Exec(
+		"DELETE FROM email_verifications WHERE user_id = $1 AND expires_at <= $2;",
+		v.UserID,
+		time.Now(),
+	); err != nil {
+		return err
+	}
+
+	return r.store.db.QueryRow(
+		"INSERT INTO email_verifications (user_id, token_hash, expires_at) VALUES ($1, $2, $3) RETURNING id, created_at;",
+		v.UserID,
+		v.TokenHash,
+		v.ExpiresAt,
+	).Scan(&v.ID, &v.CreatedAt)
+}
+
+// FindByToken returns the verification unless it's expired
+func (r *EmailVerificationRepository) FindByToken(hash string) (*model.EmailVerification, error) {
+	return r.find(
+		"SELECT "+emailVerificationColumns+" FROM email_verifications WHERE token_hash = $1 AND expires_at > $2",
+		hash,
+		time.Now(),
+	)
+}
+
+// FindLatest returns the last verification sent to the user
+func (r *EmailVerificationRepository) FindLatest(userID int) (*model.EmailVerification, error) {
+	return r.find(
+		"SELECT "+emailVerificationColumns+" FROM email_verifications WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT 1",
+		userID,
+	)
+}
+
+// DeleteByUser ...
+func (r *EmailVerificationRepository) DeleteByUser(userID int) error {
+	_, err := r.store.db.Exec(
+		"DELETE FROM email_verifications WHERE user_id = $1;",
+		userID,
+	)
+	return err
+}
+
+func (r *EmailVerificationRepository) find(query string, args ...interface{}) (*model.EmailVerification, error) {
+	v := &model.EmailVerification{}
+	if err := r.store.db.QueryRow(query, args...).Scan(
+		&v.ID,
+		&v.UserID,
+		&v.TokenHash,
+		&v.CreatedAt,
+		&v.ExpiresAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,164 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// ImportJobRepository keeps import jobs in the database,
+// so any instance of the server reports their status
+type ImportJobRepository struct {
+	store *Store
+}
+
+// Create ...
+func (r *ImportJobRepository) Create(j *model.ImportJob) error {
+	j.Files = []*model.ImportFileResult{}
+	return r.store.db.QueryRow(
+		"INSERT INTO import_jobs (id, user_id, format, status) VALUES ($1, $2, $3, $4) RETURNING created_at, heartbeat_at;",
+		j.ID,
+		j.UserID,
+		j.Format,
+		j.Status,
+	).Scan(&j.CreatedAt, &j.HeartbeatAt)
+}
+
+// Find returns the job of the user with its files in order they're added
+func (r *ImportJobRepository) Find(id string, userID int) (*model.ImportJob, error) {
+	j := &model.ImportJob{}
+	if err := r.store.db.QueryRow(
+		"SELECT id, user_id, format, status, imported, failed, error, created_at, finished_at, heartbeat_at FROM import_jobs WHERE id = $1 AND user_id = $2",
+		id,
+		userID,
+	).Scan(
+		&j.ID,
+		&j.UserID,
+		&j.Format,
+		&j.Status,
+		&j.Imported,
+		&j.Failed,
+		&j.Error,
+		&j.CreatedAt,
+		&j.FinishedAt,
+		&j.HeartbeatAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := r.store.db.Query(
+		"SELECT name, status, COALESCE(note_id, 0), error FROM import_job_files WHERE job_id = $1 ORDER BY id",
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	j.Files = []*model.ImportFileResult{}
+	for rows.Next() {
+		f := &model.ImportFileResult{}
+		if err := rows.Scan(&f.Name, &f.Status, &f.NoteID, &f.Error); err != nil {
+			return nil, err
+		}
+		j.Files = append(j.Files, f)
+	}
+	return j, rows.Err()
+}
+
+// AddFile saves the result of the file and counts it in the job,
+// which also updates the heartbeat of the job
+func (r *ImportJobRepository) AddFile(id string, f *model.ImportFileResult) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		"INSERT INTO import_job_files (job_id, name, status, note_id, error) VALUES ($1, $2, $3, NULLIF($4, 0), $5);",
+		id,
+		f.Name,
+		f.Status,
+		f.NoteID,
+		f.Error,
+	); err != nil {
+		return err
+	}
+
+	imported, failed := 0, 1
+	if f.Status == model.ImportFileImported {
+		imported, failed = 1, 0
+	}
+	if err := execOne(
+		tx,
+		"UPDATE import_jobs SET imported = imported + $2, failed = failed + $3, heartbeat_at = $4 WHERE id = $1;",
+		id,
+		imported,
+		failed,
+		time.Now(),
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Touch updates the heartbeat of the job
+func (r *ImportJobRepository) Touch(id string, at time.Time) error {
+	return execOne(
+		r.store.db,
+		"UPDATE import_jobs SET heartbeat_at = $2 WHERE id = $1;",
+		id,
+		at,
+	)
+}
+
+// Finish saves the status, error and finish time of the job
+func (r *ImportJobRepository) Finish(j *model.ImportJob) error {
+	return execOne(
+		r.store.db,
+		"UPDATE import_jobs SET status = $2, error = $3, finished_at = $4 WHERE id = $1;",
+		j.ID,
+		j.Status,
+		j.Error,
+		j.FinishedAt,
+	)
+}
+
+// FailStale finishes running jobs with the heartbeat before t as failed
+// with the error
+func (r *ImportJobRepository) FailStale(t time.Time, reason string) (int, error) {
+	res, err := r.store.db.Exec(
+		"UPDATE import_jobs SET status = $2, error = $3, finished_at = $4 WHERE status = $5 AND heartbeat_at < $1;",
+		t,
+		model.ImportJobFailed,
+		reason,
+		time.Now(),
+		model.ImportJobRunning,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := res.RowsAffected()
+	return int(count), err
+}
+
+// DeleteFinishedBefore deletes jobs finished before t with their files
+func (r *ImportJobRepository) DeleteFinishedBefore(t time.Time) (int, error) {
+	res, err := r.store.db.Exec(
+		"DELETE FROM import_jobs WHERE finished_at < $1;",
+		t,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := res.RowsAffected()
+	return int(count), err
+}
@@ -0,0 +1,145 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+const linkColumns = "token, note_id, encrypted_password, expires_at, views, failed_attempts, locked_until, created_at"
+
+// LinkRepository ...
+type LinkRepository struct {
+	store *Store
+}
+
+// Create ...
+func (r *LinkRepository) Create(l *model.NoteLink) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+
+	if err := l.BeforeCreate(); err != nil {
+		return err
+	}
+
+	return r.store.db.QueryRow(
+		"INSERT INTO note_links (token, note_id, encrypted_password, expires_at) VALUES ($1, $2, $3, $4) RETURNING created_at;",
+		l.Token,
+		l.NoteID,
+		l.EncryptedPassword,
+		l.ExpiresAt,
+	).Scan(&l.CreatedAt)
+}
+
+// Delete ...
+func (r *LinkRepository) Delete(noteID int, token string) error {
+	res, err := r.store.db.Exec(
+		"DELETE FROM note_links WHERE note_id = $1 AND token = $2;",
+		noteID,
+		token,
+	)
+	if err != nil {
+		return err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return store.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Find ...
+func (r *LinkRepository) Find(token string) (*model.NoteLink, error) {
+	l, err := scanLink(r.store.db.QueryRow(
+		"SELECT "+linkColumns+" FROM note_links WHERE token = $1",
+		token,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return l, nil
+}
+
+// FindByNote returns links of the note, the latest created first
+func (r *LinkRepository) FindByNote(noteID int) ([]*model.NoteLink, error) {
+	rows, err := r.store.db.Query(
+		"SELECT "+linkColumns+" FROM note_links WHERE note_id = $1 ORDER BY created_at DESC, token",
+		noteID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.NoteLink{}
+	for rows.Next() {
+		l, err := scanLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, l)
+	}
+	return result, rows.Err()
+}
+
+// RecordView increments the view count of the link
+// and resets its failed password attempts
+func (r *LinkRepository) RecordView(l *model.NoteLink) error {
+	if err := r.store.db.QueryRow(
+		"UPDATE note_links SET views = views + 1, failed_attempts = 0 WHERE token = $1 RETURNING views, failed_attempts;",
+		l.Token,
+	).Scan(&l.Views, &l.FailedAttempts); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// RecordFailedAttempt counts an incorrect password of the link,
+// the link is locked for lockout when the count reaches maxAttempts
+func (r *LinkRepository) RecordFailedAttempt(l *model.NoteLink, maxAttempts int, lockout time.Duration) error {
+	if err := r.store.db.QueryRow(
+		"UPDATE note_links SET "+
+			"locked_until = CASE WHEN failed_attempts + 1 >= $2 THEN $3 ELSE locked_until END, "+
+			"failed_attempts = CASE WHEN failed_attempts + 1 >= $2 THEN 0 ELSE failed_attempts + 1 END "+
+			"WHERE token = $1 RETURNING failed_attempts, locked_until;",
+		l.Token,
+		maxAttempts,
+		time.Now().Add(lockout),
+	).Scan(&l.FailedAttempts, &l.LockedUntil); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func scanLink(row scanner) (*model.NoteLink, error) {
+	l := &model.NoteLink{}
+	if err := row.Scan(
+		&l.Token,
+		&l.NoteID,
+		&l.EncryptedPassword,
+		&l.ExpiresAt,
+		&l.Views,
+		&l.FailedAttempts,
+		&l.LockedUntil,
+		&l.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
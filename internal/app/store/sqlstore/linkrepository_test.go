@@ -0,0 +1,135 @@
+package sqlstore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/sqlstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkRepository_Create(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_links", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.Notes().Create(n, u)
+
+	l := model.TestNoteLink(t)
+	l.NoteID = n.ID
+	l.Password = "password"
+	assert.NoError(t, s.Links().Create(l))
+	assert.NotEmpty(t, l.Token)
+
+	rl, err := s.Links().Find(l.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, n.ID, rl.NoteID)
+	assert.True(t, rl.ComparePassword("password"))
+	assert.Empty(t, rl.Password)
+}
+
+func TestLinkRepository_Delete(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_links", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.Notes().Create(n, u)
+
+	l := model.TestNoteLink(t)
+	l.NoteID = n.ID
+	s.Links().Create(l)
+
+	assert.EqualError(t, s.Links().Delete(n.ID+1, l.Token), store.ErrRecordNotFound.Error())
+	assert.NoError(t, s.Links().Delete(n.ID, l.Token))
+
+	_, err := s.Links().Find(l.Token)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestLinkRepository_FindByNote(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_links", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.Notes().Create(n, u)
+
+	ll, err := s.Links().FindByNote(n.ID)
+	assert.NoError(t, err)
+	assert.Len(t, ll, 0)
+
+	for i := 0; i < 2; i++ {
+		l := model.TestNoteLink(t)
+		l.NoteID = n.ID
+		s.Links().Create(l)
+	}
+
+	ll, err = s.Links().FindByNote(n.ID)
+	assert.NoError(t, err)
+	assert.Len(t, ll, 2)
+}
+
+func TestLinkRepository_RecordView(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_links", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.Notes().Create(n, u)
+
+	l := model.TestNoteLink(t)
+	l.NoteID = n.ID
+	s.Links().Create(l)
+
+	assert.NoError(t, s.Links().RecordView(l))
+	assert.NoError(t, s.Links().RecordView(l))
+	assert.Equal(t, 2, l.Views)
+
+	rl, err := s.Links().Find(l.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rl.Views)
+}
+
+func TestLinkRepository_RecordFailedAttempt(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_links", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.Notes().Create(n, u)
+
+	l := model.TestNoteLink(t)
+	l.NoteID = n.ID
+	l.Password = "password"
+	s.Links().Create(l)
+
+	assert.NoError(t, s.Links().RecordFailedAttempt(l, 2, time.Minute))
+	assert.Equal(t, 1, l.FailedAttempts)
+	assert.False(t, l.Locked(time.Now()))
+
+	assert.NoError(t, s.Links().RecordView(l))
+	assert.Equal(t, 0, l.FailedAttempts)
+
+	assert.NoError(t, s.Links().RecordFailedAttempt(l, 2, time.Minute))
+	assert.NoError(t, s.Links().RecordFailedAttempt(l, 2, time.Minute))
+	assert.True(t, l.Locked(time.Now()))
+
+	rl, err := s.Links().Find(l.Token)
+	assert.NoError(t, err)
+	assert.True(t, rl.Locked(time.Now()))
+	assert.False(t, rl.Locked(time.Now().Add(2*time.Minute)))
+}
@@ -0,0 +1,235 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/lib/pq"
+)
+
+const notebookColumns = "id, user_id, parent_id, name, created_at, updated_at"
+
+// NotebookRepository ...
+type NotebookRepository struct {
+	store *Store
+}
+
+// Create ...
+func (r *NotebookRepository) Create(nb *model.Notebook) error {
+	if err := nb.Validate(); err != nil {
+		return err
+	}
+
+	return r.store.db.QueryRow(
+		"INSERT INTO notebooks (user_id, parent_id, name) VALUES ($1, $2, $3) RETURNING id, created_at, updated_at;",
+		nb.UserID,
+		nb.ParentID,
+		nb.Name,
+	).Scan(&nb.ID, &nb.CreatedAt, &nb.UpdatedAt)
+}
+
+// Rename ...
+func (r *NotebookRepository) Rename(id int, name string) error {
+	nb := &model.Notebook{Name: name}
+	if err := nb.Validate(); err != nil {
+		return err
+	}
+
+	return execOne(
+		r.store.db,
+		"UPDATE notebooks SET name = $2, updated_at = $3 WHERE id = $1;",
+		id,
+		name,
+		time.Now(),
+	)
+}
+
+// Move changes the parent of the notebook, nil parent id makes it top-level.
+// Notebooks of the user are locked while checking the new parent isn't
+// the notebook itself or its descendant, so concurrent moves can't make a cycle
+func (r *NotebookRepository) Move(id int, parentID *int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		"SELECT id FROM notebooks WHERE user_id = (SELECT user_id FROM notebooks WHERE id = $1) FOR UPDATE;",
+		id,
+	); err != nil {
+		return err
+	}
+
+	if parentID != nil {
+		var cycle bool
+		if err := tx.QueryRow(
+			"WITH RECURSIVE ancestors AS ("+
+				"SELECT id, parent_id FROM notebooks WHERE id = $2 "+
+				"UNION ALL SELECT nb.id, nb.parent_id FROM notebooks nb JOIN ancestors a ON nb.id = a.parent_id"+
+				") SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $1)",
+			id,
+			*parentID,
+		).Scan(&cycle); err != nil {
+			return err
+		}
+		if cycle {
+			return store.ErrNotebookCycle
+		}
+	}
+
+	if err := execOne(
+		tx,
+		"UPDATE notebooks SET parent_id = $2, updated_at = $3 WHERE id = $1;",
+		id,
+		parentID,
+		time.Now(),
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes the notebook. In cascade mode nested notebooks are deleted
+// and their notes are moved to trash, otherwise nested notebooks and notes
+// are moved to the parent of the notebook
+func (r *NotebookRepository) Delete(id int, mode string) error {
+	if mode != store.NotebookDeleteCascade && mode != store.NotebookDeleteMoveToParent {
+		return store.ErrInvalidDeleteMode
+	}
+
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var parentID *int
+	if err := tx.QueryRow(
+		"SELECT parent_id FROM notebooks WHERE id = $1 FOR UPDATE",
+		id,
+	).Scan(&parentID); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+
+	if mode == store.NotebookDeleteCascade {
+		ids, err := subtree(tx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(
+			"UPDATE notes SET deleted_at = $2 WHERE notebook_id = ANY($1) AND deleted_at IS NULL;",
+			pq.Array(ids),
+			time.Now(),
+		); err != nil {
+			return err
+		}
+	} else {
+		if _, err := tx.Exec(
+			"UPDATE notebooks SET parent_id = $2 WHERE parent_id = $1;",
+			id,
+			parentID,
+		); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(
+			"UPDATE notes SET notebook_id = $2 WHERE notebook_id = $1;",
+			id,
+			parentID,
+		); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(
+		"DELETE FROM notebooks WHERE id = $1;",
+		id,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Find ...
+func (r *NotebookRepository) Find(id int) (*model.Notebook, error) {
+	nb, err := scanNotebook(r.store.db.QueryRow(
+		"SELECT "+notebookColumns+" FROM notebooks WHERE id = $1",
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return nb, nil
+}
+
+// FindByUser returns all notebooks of the user ordered by name
+func (r *NotebookRepository) FindByUser(u *model.User) ([]*model.Notebook, error) {
+	rows, err := r.store.db.Query(
+		"SELECT "+notebookColumns+" FROM notebooks WHERE user_id = $1 ORDER BY name, id",
+		u.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.Notebook{}
+	for rows.Next() {
+		nb, err := scanNotebook(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, nb)
+	}
+	return result, rows.Err()
+}
+
+// subtree returns ids of the notebook and all its descendants
+func subtree(tx *sql.Tx, id int) ([]int64, error) {
+	rows, err := tx.Query(
+		"WITH RECURSIVE descendants AS ("+
+			"SELECT id FROM notebooks WHERE id = $1 "+
+			"UNION ALL SELECT nb.id FROM notebooks nb JOIN descendants d ON nb.parent_id = d.id"+
+			") SELECT id FROM descendants",
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func scanNotebook(row scanner) (*model.Notebook, error) {
+	nb := &model.Notebook{}
+	if err := row.Scan(
+		&nb.ID,
+		&nb.UserID,
+		&nb.ParentID,
+		&nb.Name,
+		&nb.CreatedAt,
+		&nb.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return nb, nil
+}
@@ -2,10 +2,26 @@ package sqlstore
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/KapitanD/http-api-server/internal/app/model"
 	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/lib/pq"
+)
+
+const noteColumns = `notes.id, notes.author_id, notes.notebook_id, notes.header, notes.body, notes.version,
+	notes.pinned, notes.archived, notes.starred,
+	notes.created_at, notes.updated_at, notes.deleted_at,
+	ARRAY(SELECT t.name FROM tags t JOIN note_tags nt ON nt.tag_id = t.id WHERE nt.note_id = notes.id ORDER BY t.name),
+	(SELECT (100 * count(*) FILTER (WHERE ci.done) / NULLIF(count(*), 0))::int FROM checklist_items ci WHERE ci.note_id = notes.id)`
+
+// ts_headline marks matches with sentinels, snippets are escaped
+// with store.EscapeSnippet after scanning
+const (
+	headerHeadlineOptions = "StartSel=" + store.HighlightStartSentinel + ", StopSel=" + store.HighlightStopSentinel + ", HighlightAll=true"
+	bodyHeadlineOptions   = "StartSel=" + store.HighlightStartSentinel + ", StopSel=" + store.HighlightStopSentinel + ", MaxWords=35, MinWords=15, MaxFragments=2"
 )
 
 // NoteRepository ...
@@ -13,6 +29,18 @@ type NoteRepository struct {
 	store *Store
 }
 
+type scanner interface {
+	Scan(...interface{}) error
+}
+
+type execer interface {
+	Exec(string, ...interface{}) (sql.Result, error)
+}
+
+type querier interface {
+	Query(string, ...interface{}) (*sql.Rows, error)
+}
+
 // Create ...
 func (r *NoteRepository) Create(n *model.Note, u *model.User) error {
 	if err := n.Validate(); err != nil {
@@ -21,40 +49,112 @@ func (r *NoteRepository) Create(n *model.Note, u *model.User) error {
 
 	n.AuthorID = u.ID
 
-	return r.store.db.QueryRow(
-		"INSERT INTO notes (author_id, header, body, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING id;",
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRow(
+		"INSERT INTO notes (author_id, notebook_id, header, body, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, version;",
 		u.ID,
+		n.NotebookID,
 		n.Header,
 		n.Body,
 		n.CreatedAt,
 		n.UpdatedAt,
-	).Scan(&n.ID)
+	).Scan(&n.ID, &n.Version); err != nil {
+		return err
+	}
+
+	if err := insertRevision(tx, n); err != nil {
+		return err
+	}
+
+	if err := saveReferences(tx, n); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// Update ...
+// Update changes the note and records its new content as a revision
+// when the header or body differ from the current ones. Non-zero version
+// of the update must match the current note version
 func (r *NoteRepository) Update(id int, un *model.Note) error {
 	if err := un.ValidateUpdate(); err != nil {
 		return err
 	}
 
-	n, err := r.FindByID(id)
+	tx, err := r.store.db.Begin()
 	if err != nil {
 		return err
 	}
-	if un.Body != "" {
+	defer tx.Rollback()
+
+	if err := updateNote(tx, id, un); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// updateNote changes the note within the transaction
+func updateNote(tx *sql.Tx, id int, un *model.Note) error {
+	n, err := lockNote(tx, id)
+	if err != nil {
+		return err
+	}
+	changed := false
+	if un.Body != "" && un.Body != n.Body {
 		n.Body = un.Body
+		changed = true
 	}
-	if un.Header != "" {
+	if un.Header != "" && un.Header != n.Header {
 		n.Header = un.Header
+		changed = true
 	}
 	n.UpdatedAt = time.Now()
-	return r.store.db.QueryRow(
-		"UPDATE notes SET header=$1, body=$2, updated_at=$3 WHERE id=$4 RETURNING id;",
+
+	if err := tx.QueryRow(
+		"UPDATE notes SET header=$1, body=$2, updated_at=$3, version=version+1 WHERE id=$4 AND ($5 = 0 OR version = $5) RETURNING version;",
 		n.Header,
 		n.Body,
 		n.UpdatedAt,
 		id,
-	).Scan(&id)
+		un.Version,
+	).Scan(&n.Version); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrVersionConflict
+		}
+		return err
+	}
+
+	if changed {
+		if err := insertRevision(tx, n); err != nil {
+			return err
+		}
+		if err := saveReferences(tx, n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lockNote selects the note for update within the transaction
+func lockNote(tx *sql.Tx, id int) (*model.Note, error) {
+	n, err := scanNote(tx.QueryRow(
+		"SELECT "+noteColumns+" FROM notes WHERE id = $1 AND deleted_at IS NULL FOR UPDATE",
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return n, nil
 }
 
 // Delete ...
@@ -66,54 +166,449 @@ func (r *NoteRepository) Delete(id int) error {
 	return err
 }
 
-// FindByUser ...
-func (r *NoteRepository) FindByUser(u *model.User) ([]*model.Note, error) {
+// Trash moves the note to trash
+func (r *NoteRepository) Trash(id int) error {
+	return execOne(
+		r.store.db,
+		"UPDATE notes SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL;",
+		id,
+		time.Now(),
+	)
+}
+
+// Restore moves the note back from trash
+func (r *NoteRepository) Restore(id int) error {
+	return execOne(
+		r.store.db,
+		"UPDATE notes SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL;",
+		id,
+	)
+}
+
+// Move moves the note to the notebook, nil notebook id moves it to the top level
+func (r *NoteRepository) Move(id int, notebookID *int) error {
+	return execOne(
+		r.store.db,
+		"UPDATE notes SET notebook_id = $2 WHERE id = $1 AND deleted_at IS NULL;",
+		id,
+		notebookID,
+	)
+}
+
+// SetFlag sets or clears the flag of the note
+func (r *NoteRepository) SetFlag(id int, flag string, value bool) error {
+	switch flag {
+	case store.FlagPinned, store.FlagArchived, store.FlagStarred:
+	default:
+		return store.ErrUnknownFlag
+	}
+
+	return execOne(
+		r.store.db,
+		"UPDATE notes SET "+flag+" = $2 WHERE id = $1 AND deleted_at IS NULL;",
+		id,
+		value,
+	)
+}
+
+// PurgeTrash deletes notes moved to trash before the time
+// and returns the number of deleted notes
+func (r *NoteRepository) PurgeTrash(before time.Time) (int, error) {
+	res, err := r.store.db.Exec(
+		"DELETE FROM notes WHERE deleted_at < $1;",
+		before,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := res.RowsAffected()
+	return int(count), err
+}
+
+// Bulk applies the items in a single transaction. In atomic mode the first
+// failed item rolls back all changes and Bulk returns ErrBulkFailed, otherwise
+// changes of failed items are rolled back to a savepoint and the rest are
+// committed. Errors of the items are stored in their Err field
+func (r *NoteRepository) Bulk(items []*store.BulkItem, atomic bool) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, it := range items {
+		if !atomic {
+			if _, err := tx.Exec("SAVEPOINT bulk_item;"); err != nil {
+				return err
+			}
+		}
+
+		it.Err = applyBulkItem(tx, it)
+		switch {
+		case it.Err == nil && !atomic:
+			if _, err := tx.Exec("RELEASE SAVEPOINT bulk_item;"); err != nil {
+				return err
+			}
+		case it.Err != nil && atomic:
+			return store.ErrBulkFailed
+		case it.Err != nil:
+			if _, err := tx.Exec("ROLLBACK TO SAVEPOINT bulk_item;"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func applyBulkItem(tx *sql.Tx, it *store.BulkItem) error {
+	if err := it.Validate(); err != nil {
+		return err
+	}
+	if err := checkBulkRole(tx, it); err != nil {
+		return err
+	}
+
+	switch it.Op {
+	case store.BulkDelete:
+		return execOne(
+			tx,
+			"UPDATE notes SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL;",
+			it.NoteID,
+			time.Now(),
+		)
+	case store.BulkUpdate:
+		return updateNote(tx, it.NoteID, &model.Note{
+			Header:    it.Header,
+			Body:      it.Body,
+			UpdatedAt: time.Now(),
+		})
+	case store.BulkTag:
+		n, err := lockNote(tx, it.NoteID)
+		if err != nil {
+			return err
+		}
+		return setNoteTags(tx, n, it.MergeTags(n.Tags))
+	default:
+		return execOne(
+			tx,
+			"UPDATE notes SET notebook_id = $2 WHERE id = $1 AND deleted_at IS NULL;",
+			it.NoteID,
+			it.NotebookID,
+		)
+	}
+}
+
+// checkBulkRole locks the note and the share of the user for it,
+// so the role can't be revoked until the transaction ends
+func checkBulkRole(tx *sql.Tx, it *store.BulkItem) error {
+	n, err := lockNote(tx, it.NoteID)
+	if err != nil {
+		return err
+	}
+
+	role := model.RoleOwner
+	if n.AuthorID != it.UserID {
+		if err := tx.QueryRow(
+			"SELECT role FROM note_shares WHERE note_id = $1 AND user_id = $2 FOR SHARE",
+			it.NoteID,
+			it.UserID,
+		).Scan(&role); err != nil {
+			if err == sql.ErrNoRows {
+				return store.ErrRecordNotFound
+			}
+			return err
+		}
+	}
+	if !model.RoleAllows(role, it.Role) {
+		return store.ErrForbidden
+	}
+	return nil
+}
+
+// EachByUser calls fn for every note of the user in order of ids while
+// reading them from the database, so notes are never held in memory at once.
+// Iteration stops at the first error returned by fn
+func (r *NoteRepository) EachByUser(u *model.User, fn func(*model.Note) error) error {
 	rows, err := r.store.db.Query(
-		"SELECT id, author_id, header, body, created_at, updated_at FROM notes WHERE author_id=$1",
+		"SELECT "+noteColumns+" FROM notes WHERE author_id = $1 AND deleted_at IS NULL ORDER BY id",
 		u.ID,
 	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		n, err := scanNote(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(n); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// FindPage returns notes of the user matching the query and the cursor
+// of the next page, which is empty for the last page
+func (r *NoteRepository) FindPage(u *model.User, q *store.NoteQuery) ([]*model.Note, string, error) {
+	if err := q.Validate(); err != nil {
+		return nil, "", err
+	}
+	c, err := q.DecodeCursor()
+	if err != nil {
+		return nil, "", err
+	}
+
+	where := []string{"author_id = $1", "deleted_at IS NULL"}
+	args := []interface{}{u.ID}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	switch q.Archived {
+	case store.ArchivedExclude:
+		where = append(where, "NOT archived")
+	case store.ArchivedOnly:
+		where = append(where, "archived")
+	}
+	if q.StarredOnly {
+		where = append(where, "starred")
+	}
+	if q.NotebookID != 0 {
+		where = append(where, "notebook_id = "+arg(q.NotebookID))
+	}
+	if len(q.Tags) > 0 {
+		if q.MatchAllTags {
+			where = append(where, fmt.Sprintf(
+				"(SELECT count(DISTINCT t.id) FROM tags t JOIN note_tags nt ON nt.tag_id = t.id "+
+					"WHERE nt.note_id = notes.id AND t.name = ANY(%s)) = %s",
+				arg(pq.Array(q.Tags)),
+				arg(len(q.Tags)),
+			))
+		} else {
+			where = append(where, fmt.Sprintf(
+				"id IN (SELECT nt.note_id FROM note_tags nt JOIN tags t ON nt.tag_id = t.id WHERE t.name = ANY(%s))",
+				arg(pq.Array(q.Tags)),
+			))
+		}
+	}
+	if !q.CreatedAfter.IsZero() {
+		where = append(where, "created_at > "+arg(q.CreatedAfter))
+	}
+	if !q.UpdatedBefore.IsZero() {
+		where = append(where, "updated_at < "+arg(q.UpdatedBefore))
+	}
+
+	op, dir := ">", "ASC"
+	if q.Desc {
+		op, dir = "<", "DESC"
+	}
+	if c != nil {
+		var v interface{} = c.Time
+		if q.Sort == store.SortHeader {
+			v = c.Header
+		}
+		after := fmt.Sprintf("(%s, id) %s (%s, %s)", q.Sort, op, arg(v), arg(c.ID))
+		if q.PinnedFirst {
+			p := arg(c.Pinned)
+			after = fmt.Sprintf("(pinned < %s OR (pinned = %s AND %s))", p, p, after)
+		}
+		where = append(where, after)
+	}
+
+	order := fmt.Sprintf("%s %s, id %s", q.Sort, dir, dir)
+	if q.PinnedFirst {
+		order = "pinned DESC, " + order
+	}
+
+	nl, err := r.query(
+		fmt.Sprintf(
+			"SELECT %s FROM notes WHERE %s ORDER BY %s LIMIT %s",
+			noteColumns,
+			strings.Join(where, " AND "),
+			order,
+			arg(q.Limit+1),
+		),
+		args...,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(nl) > q.Limit {
+		nl = nl[:q.Limit]
+		return nl, q.NextCursor(nl[len(nl)-1]), nil
+	}
+	return nl, "", nil
+}
+
+// FindByID ...
+func (r *NoteRepository) FindByID(id int) (*model.Note, error) {
+	n, err := scanNote(r.store.db.QueryRow(
+		"SELECT "+noteColumns+" FROM notes WHERE id = $1 AND deleted_at IS NULL",
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return n, nil
+}
+
+// FindTrash returns notes of the user in trash, the latest deleted first
+func (r *NoteRepository) FindTrash(u *model.User) ([]*model.Note, error) {
+	return r.query(
+		"SELECT "+noteColumns+" FROM notes WHERE author_id=$1 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC, id DESC",
+		u.ID,
+	)
+}
+
+// CountByUser counts notes of the author including notes in trash
+func (r *NoteRepository) CountByUser(u *model.User) (int, error) {
+	var count int
+	err := r.store.db.QueryRow(
+		"SELECT count(*) FROM notes WHERE author_id = $1",
+		u.ID,
+	).Scan(&count)
+	return count, err
+}
+
+// FindTrashedByID ...
+func (r *NoteRepository) FindTrashedByID(id int) (*model.Note, error) {
+	n, err := scanNote(r.store.db.QueryRow(
+		"SELECT "+noteColumns+" FROM notes WHERE id = $1 AND deleted_at IS NOT NULL",
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return n, nil
+}
+
+// Search ranks notes of the user by relevance to the query
+func (r *NoteRepository) Search(u *model.User, sq *store.SearchQuery) ([]*model.SearchResult, error) {
+	rows, err := r.store.db.Query(
+		"SELECT "+noteColumns+", ts_rank(search, q), ts_headline('simple', header, q, $3), ts_headline('simple', body, q, $4) "+
+			"FROM notes, to_tsquery('simple', $2) q WHERE author_id = $1 AND deleted_at IS NULL AND search @@ q "+
+			"ORDER BY ts_rank(search, q) DESC, id DESC LIMIT $5",
+		u.ID,
+		tsQuery(sq),
+		headerHeadlineOptions,
+		bodyHeadlineOptions,
+		sq.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.SearchResult{}
+	for rows.Next() {
+		sr := &model.SearchResult{}
+		sr.Note, err = scanNote(rows, &sr.Rank, &sr.HeaderSnippet, &sr.BodySnippet)
+		if err != nil {
+			return nil, err
+		}
+		sr.HeaderSnippet = store.EscapeSnippet(sr.HeaderSnippet)
+		sr.BodySnippet = store.EscapeSnippet(sr.BodySnippet)
+		result = append(result, sr)
+	}
+	return result, rows.Err()
+}
+
+// execOne executes the query and reports ErrRecordNotFound
+// when it affects no rows
+func execOne(db execer, query string, args ...interface{}) error {
+	res, err := db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return store.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *NoteRepository) query(query string, args ...interface{}) ([]*model.Note, error) {
+	rows, err := r.store.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+
 	result := []*model.Note{}
 	for rows.Next() {
-		n := &model.Note{}
-		if err := rows.Scan(
-			&n.ID,
-			&n.AuthorID,
-			&n.Header,
-			&n.Body,
-			&n.CreatedAt,
-			&n.UpdatedAt,
-		); err != nil {
-			if err == sql.ErrNoRows {
-				return nil, store.ErrRecordNotFound
-			}
+		n, err := scanNote(rows)
+		if err != nil {
 			return nil, err
 		}
 		result = append(result, n)
 	}
-	return result, nil
+	return result, rows.Err()
 }
 
-// FindByID ...
-func (r *NoteRepository) FindByID(id int) (*model.Note, error) {
+func scanNote(row scanner, extra ...interface{}) (*model.Note, error) {
 	n := &model.Note{}
-	if err := r.store.db.QueryRow(
-		"SELECT id, author_id, header, body, created_at, updated_at FROM notes WHERE id = $1",
-		id,
-	).Scan(
+	if err := row.Scan(append([]interface{}{
 		&n.ID,
 		&n.AuthorID,
+		&n.NotebookID,
 		&n.Header,
 		&n.Body,
+		&n.Version,
+		&n.Pinned,
+		&n.Archived,
+		&n.Starred,
 		&n.CreatedAt,
 		&n.UpdatedAt,
-	); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, store.ErrRecordNotFound
-		}
+		&n.DeletedAt,
+		pq.Array(&n.Tags),
+		&n.Completion,
+	}, extra...)...); err != nil {
 		return nil, err
 	}
+	if len(n.Tags) == 0 {
+		n.Tags = nil
+	}
 	return n, nil
 }
+
+// tsQuery converts the search query to to_tsquery syntax, words consist
+// of letters and digits only so they need no escaping
+func tsQuery(sq *store.SearchQuery) string {
+	terms := []string{}
+	for _, t := range sq.Terms {
+		words := make([]string, len(t.Words))
+		for i, w := range t.Words {
+			words[i] = "'" + w + "'"
+		}
+		if t.Prefix {
+			words[len(words)-1] += ":*"
+		}
+
+		term := strings.Join(words, " <-> ")
+		if len(words) > 1 {
+			term = "(" + term + ")"
+		}
+		if t.Negate {
+			term = "!" + term
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " & ")
+}
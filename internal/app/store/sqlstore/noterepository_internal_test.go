@@ -0,0 +1,18 @@
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTSQuery(t *testing.T) {
+	sq, err := store.ParseSearchQuery(`meeting "next week" proj* -draft -"old plan"`)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		`'meeting' & ('next' <-> 'week') & 'proj':* & !'draft' & !('old' <-> 'plan')`,
+		tsQuery(sq),
+	)
+}
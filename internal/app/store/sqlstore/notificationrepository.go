@@ -0,0 +1,62 @@
+package sqlstore
+
+import (
+	"github.com/KapitanD/http-api-server/internal/app/model"
+)
+
+// NotificationRepository ...
+type NotificationRepository struct {
+	store *Store
+}
+
+// Create ...
+func (r *NotificationRepository) Create(n *model.Notification) error {
+	return r.store.db.QueryRow(
+		"INSERT INTO notifications (user_id, note_id, kind, message) VALUES ($1, $2, $3, $4) RETURNING id, created_at;",
+		n.UserID,
+		n.NoteID,
+		n.Kind,
+		n.Message,
+	).Scan(&n.ID, &n.CreatedAt)
+}
+
+// MarkRead ...
+func (r *NotificationRepository) MarkRead(id int, u *model.User) error {
+	return execOne(
+		r.store.db,
+		"UPDATE notifications SET read_at = COALESCE(read_at, now()) WHERE id = $1 AND user_id = $2;",
+		id,
+		u.ID,
+	)
+}
+
+// FindByUser returns notifications of the user, the latest first
+func (r *NotificationRepository) FindByUser(u *model.User, unread bool) ([]*model.Notification, error) {
+	rows, err := r.store.db.Query(
+		"SELECT id, user_id, note_id, kind, message, created_at, read_at FROM notifications WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL) ORDER BY id DESC",
+		u.ID,
+		unread,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.Notification{}
+	for rows.Next() {
+		n := &model.Notification{}
+		if err := rows.Scan(
+			&n.ID,
+			&n.UserID,
+			&n.NoteID,
+			&n.Kind,
+			&n.Message,
+			&n.CreatedAt,
+			&n.ReadAt,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, n)
+	}
+	return result, rows.Err()
+}
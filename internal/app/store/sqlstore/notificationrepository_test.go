@@ -0,0 +1,47 @@
+package sqlstore_test
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/sqlstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationRepository_MarkRead(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("notifications", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	s.User().Create(other)
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+
+	first := &model.Notification{UserID: u.ID, NoteID: &n.ID, Kind: model.NotificationReminder, Message: "first"}
+	assert.NoError(t, s.Notifications().Create(first))
+	second := &model.Notification{UserID: u.ID, Kind: model.NotificationReminder, Message: "second"}
+	assert.NoError(t, s.Notifications().Create(second))
+
+	nl, err := s.Notifications().FindByUser(u, false)
+	assert.NoError(t, err)
+	if assert.Len(t, nl, 2) {
+		assert.Equal(t, "second", nl[0].Message)
+	}
+
+	assert.EqualError(t, s.Notifications().MarkRead(first.ID, other), store.ErrRecordNotFound.Error())
+	assert.NoError(t, s.Notifications().MarkRead(first.ID, u))
+	nl, err = s.Notifications().FindByUser(u, true)
+	assert.NoError(t, err)
+	assert.Len(t, nl, 1)
+
+	// notifications about the note are deleted with it
+	s.Notes().Delete(n.ID)
+	nl, err = s.Notifications().FindByUser(u, false)
+	assert.NoError(t, err)
+	assert.Len(t, nl, 1)
+}
@@ -0,0 +1,121 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+const passwordResetColumns = "id, user_id, token_hash, created_at, expires_at, used_at"
+
+// PasswordResetRepository ...
+type PasswordResetRepository struct {
+	store *Store
+}
+
+// Create generates the token and saves the reset, expired resets
+// of the user are deleted on the way
+func (r *PasswordResetRepository) Create(p *model.PasswordReset) error {
+	if err := p.BeforeCreate(); err != nil {
+		return err
+	}
+
+	if _, err := r.store.db.Exec(
+		"DELETE FROM password_resets WHERE user_id = $1 AND expires_at <= $2;",
+		p.UserID,
+		time.Now(),
+	); err != nil {
+		return err
+	}
+
+	return r.store.db.QueryRow(
+		"INSERT INTO password_resets (user_id, token_hash, expires_at) VALUES ($1, $2, $3) RETURNING id, created_at;",
+		p.UserID,
+		p.TokenHash,
+		p.ExpiresAt,
+	).Scan(&p.ID, &p.CreatedAt)
+}
+
+// FindByToken returns the reset unless it's used or expired
+func (r *PasswordResetRepository) FindByToken(hash string) (*model.PasswordReset, error) {
+	return r.find(
+		"SELECT "+passwordResetColumns+" FROM password_resets WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2",
+		hash,
+		time.Now(),
+	)
+}
+
+// FindLatest returns the last reset requested for the user
+func (r *PasswordResetRepository) FindLatest(userID int) (*model.PasswordReset, error) {
+	return r.find(
+		"SELECT "+passwordResetColumns+" FROM password_resets WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT 1",
+		userID,
+	)
+}
+
+// Use marks the reset used and sets the new password of the user in one
+// transaction, ErrRecordNotFound is returned when the reset is already
+// used, so the token works once even for concurrent requests
+func (r *PasswordResetRepository) Use(id int, u *model.User, at time.Time) error {
+	if err := u.ValidatePassword(); err != nil {
+		return err
+	}
+
+	if err := u.BeforeCreate(); err != nil {
+		return err
+	}
+
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := execOne(
+		tx,
+		"UPDATE password_resets SET used_at = $3 WHERE id = $1 AND user_id = $2 AND used_at IS NULL;",
+		id,
+		u.ID,
+		at,
+	); err != nil {
+		return err
+	}
+	if err := execOne(
+		tx,
+		"UPDATE users SET encrypted_password = $2 WHERE id = $1;",
+		u.ID,
+		u.EncryptedPassword,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteByUser ...
+func (r *PasswordResetRepository) DeleteByUser(userID int) error {
+	_, err := r.store.db.Exec(
+		"DELETE FROM password_resets WHERE user_id = $1;",
+		userID,
+	)
+	return err
+}
+
+func (r *PasswordResetRepository) find(query string, args ...interface{}) (*model.PasswordReset, error) {
+	p := &model.PasswordReset{}
+	if err := r.store.db.QueryRow(query, args...).Scan(
+		&p.ID,
+		&p.UserID,
+		&p.TokenHash,
+		&p.CreatedAt,
+		&p.ExpiresAt,
+		&p.UsedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return p, nil
+}
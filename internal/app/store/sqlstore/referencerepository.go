@@ -0,0 +1,108 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+)
+
+const referenceColumns = `r.source_id, s.header, r.link, r.target_id, t.header, t.deleted_at`
+
+// ReferenceRepository ...
+type ReferenceRepository struct {
+	store *Store
+}
+
+// FindBySource returns links of the note in order of appearance
+func (r *ReferenceRepository) FindBySource(noteID int) ([]*model.NoteReference, error) {
+	return r.query(
+		"SELECT "+referenceColumns+" FROM note_references r JOIN notes s ON s.id = r.source_id "+
+			"LEFT JOIN notes t ON t.id = r.target_id WHERE r.source_id = $1 ORDER BY r.position",
+		noteID,
+	)
+}
+
+// FindByTarget returns links to the note from notes not in trash
+func (r *ReferenceRepository) FindByTarget(noteID int) ([]*model.NoteReference, error) {
+	return r.query(
+		"SELECT "+referenceColumns+" FROM note_references r JOIN notes s ON s.id = r.source_id "+
+			"LEFT JOIN notes t ON t.id = r.target_id WHERE r.target_id = $1 AND s.deleted_at IS NULL "+
+			"ORDER BY r.source_id, r.position",
+		noteID,
+	)
+}
+
+func (r *ReferenceRepository) query(query string, args ...interface{}) ([]*model.NoteReference, error) {
+	rows, err := r.store.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.NoteReference{}
+	for rows.Next() {
+		ref := &model.NoteReference{}
+		var targetHeader sql.NullString
+		var targetDeletedAt *time.Time
+		if err := rows.Scan(
+			&ref.SourceID,
+			&ref.SourceHeader,
+			&ref.Link,
+			&ref.TargetID,
+			&targetHeader,
+			&targetDeletedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		var target *model.Note
+		if ref.TargetID != nil {
+			target = &model.Note{ID: *ref.TargetID, Header: targetHeader.String, DeletedAt: targetDeletedAt}
+		}
+		ref.ResolveStatus(target)
+		result = append(result, ref)
+	}
+	return result, rows.Err()
+}
+
+// saveReferences replaces links of the note with links parsed from its body
+// and points unresolved title links of the author's notes to the note
+// when they match its header
+func saveReferences(tx *sql.Tx, n *model.Note) error {
+	if _, err := tx.Exec(
+		"DELETE FROM note_references WHERE source_id = $1;",
+		n.ID,
+	); err != nil {
+		return err
+	}
+
+	for i, l := range model.ParseWikiLinks(n.Body) {
+		target := "(SELECT id FROM notes WHERE author_id = $4 AND deleted_at IS NULL AND lower(trim(header)) = lower($5) ORDER BY id LIMIT 1)"
+		var key interface{} = l.Title
+		if l.NoteID != 0 {
+			target = "(SELECT id FROM notes WHERE author_id = $4 AND id = $5)"
+			key = l.NoteID
+		}
+		if _, err := tx.Exec(
+			"INSERT INTO note_references (source_id, link, position, target_id) VALUES ($1, $2, $3, "+target+");",
+			n.ID,
+			l.Link,
+			i,
+			n.AuthorID,
+			key,
+		); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Exec(
+		"UPDATE note_references r SET target_id = $1 FROM notes s "+
+			"WHERE s.id = r.source_id AND s.author_id = $2 AND r.target_id IS NULL "+
+			"AND r.link NOT LIKE '#%' AND lower(r.link) = lower(trim($3));",
+		n.ID,
+		n.AuthorID,
+		n.Header,
+	)
+	return err
+}
@@ -0,0 +1,94 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// RefreshTokenRepository ...
+type RefreshTokenRepository struct {
+	store *Store
+}
+
+// Create generates the token and saves it, expired tokens of the user
+// are deleted on the way. Used tokens are kept until they expire
+// to detect their reuse
+func (r *RefreshTokenRepository) Create(t *model.RefreshToken) error {
+	if err := t.BeforeCreate(); err != nil {
+		return err
+	}
+
+	if _, err := r.store.db.Exec(
+		"DELETE FROM refresh_tokens WHERE user_id = $1 AND expires_at <= $2;",
+		t.UserID,
+		time.Now(),
+	); err != nil {
+		return err
+	}
+
+	return r.store.db.QueryRow(
+		"INSERT INTO refresh_tokens (user_id, family, token_hash, expires_at) VALUES ($1, $2, $3, $4) RETURNING id, created_at;",
+		t.UserID,
+		t.Family,
+		t.TokenHash,
+		t.ExpiresAt,
+	).Scan(&t.ID, &t.CreatedAt)
+}
+
+// FindByToken returns the token even if it's used or revoked
+func (r *RefreshTokenRepository) FindByToken(hash string) (*model.RefreshToken, error) {
+	t := &model.RefreshToken{}
+	if err := r.store.db.QueryRow(
+		"SELECT id, user_id, family, token_hash, created_at, expires_at, used_at, revoked_at FROM refresh_tokens WHERE token_hash = $1",
+		hash,
+	).Scan(
+		&t.ID,
+		&t.UserID,
+		&t.Family,
+		&t.TokenHash,
+		&t.CreatedAt,
+		&t.ExpiresAt,
+		&t.UsedAt,
+		&t.RevokedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// Use marks the token used, ErrRecordNotFound is returned when the token
+// is already used or revoked, so only one of concurrent requests succeeds
+func (r *RefreshTokenRepository) Use(id int, at time.Time) error {
+	return execOne(
+		r.store.db,
+		"UPDATE refresh_tokens SET used_at = $2 WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL;",
+		id,
+		at,
+	)
+}
+
+// RevokeFamily ...
+func (r *RefreshTokenRepository) RevokeFamily(family string, at time.Time) error {
+	_, err := r.store.db.Exec(
+		"UPDATE refresh_tokens SET revoked_at = $2 WHERE family = $1 AND revoked_at IS NULL;",
+		family,
+		at,
+	)
+	return err
+}
+
+// RevokeByUser ...
+func (r *RefreshTokenRepository) RevokeByUser(userID int, at time.Time) error {
+	_, err := r.store.db.Exec(
+		"UPDATE refresh_tokens SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL;",
+		userID,
+		at,
+	)
+	return err
+}
@@ -0,0 +1,94 @@
+package sqlstore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/sqlstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTokenRepository_Create(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("refresh_tokens", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	rt := &model.RefreshToken{UserID: u.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	assert.NoError(t, s.RefreshTokens().Create(rt))
+	assert.NotZero(t, rt.ID)
+	assert.NotEmpty(t, rt.Token)
+	assert.NotEmpty(t, rt.Family)
+
+	next := &model.RefreshToken{UserID: u.ID, Family: rt.Family, ExpiresAt: time.Now().Add(time.Hour)}
+	assert.NoError(t, s.RefreshTokens().Create(next))
+	assert.Equal(t, rt.Family, next.Family)
+	assert.NotEqual(t, rt.Token, next.Token)
+
+	ft, err := s.RefreshTokens().FindByToken(model.HashToken(rt.Token))
+	assert.NoError(t, err)
+	assert.Equal(t, rt.ID, ft.ID)
+	assert.Nil(t, ft.UsedAt)
+
+	_, err = s.RefreshTokens().FindByToken(model.HashToken("unknown"))
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestRefreshTokenRepository_Use(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("refresh_tokens", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+	rt := &model.RefreshToken{UserID: u.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	s.RefreshTokens().Create(rt)
+
+	assert.NoError(t, s.RefreshTokens().Use(rt.ID, time.Now()))
+	assert.EqualError(t, s.RefreshTokens().Use(rt.ID, time.Now()), store.ErrRecordNotFound.Error())
+
+	ft, err := s.RefreshTokens().FindByToken(model.HashToken(rt.Token))
+	assert.NoError(t, err)
+	assert.NotNil(t, ft.UsedAt)
+}
+
+func TestRefreshTokenRepository_Revoke(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("refresh_tokens", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	s.User().Create(other)
+
+	first := &model.RefreshToken{UserID: u.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	s.RefreshTokens().Create(first)
+	sibling := &model.RefreshToken{UserID: u.ID, Family: first.Family, ExpiresAt: time.Now().Add(time.Hour)}
+	s.RefreshTokens().Create(sibling)
+	second := &model.RefreshToken{UserID: u.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	s.RefreshTokens().Create(second)
+	otherToken := &model.RefreshToken{UserID: other.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	s.RefreshTokens().Create(otherToken)
+
+	revoked := func(rt *model.RefreshToken) bool {
+		ft, err := s.RefreshTokens().FindByToken(model.HashToken(rt.Token))
+		assert.NoError(t, err)
+		return ft.RevokedAt != nil
+	}
+
+	assert.NoError(t, s.RefreshTokens().RevokeFamily(first.Family, time.Now()))
+	assert.True(t, revoked(first))
+	assert.True(t, revoked(sibling))
+	assert.False(t, revoked(second))
+	assert.EqualError(t, s.RefreshTokens().Use(sibling.ID, time.Now()), store.ErrRecordNotFound.Error())
+
+	assert.NoError(t, s.RefreshTokens().RevokeByUser(u.ID, time.Now()))
+	assert.True(t, revoked(second))
+	assert.False(t, revoked(otherToken))
+}
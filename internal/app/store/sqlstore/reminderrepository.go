@@ -0,0 +1,202 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+const reminderColumns = "id, note_id, user_id, remind_at, recurrence, channel, target, occurrence, fired, last_fired_at, last_error, created_at"
+
+// ReminderRepository ...
+type ReminderRepository struct {
+	store *Store
+}
+
+// Create ...
+func (r *ReminderRepository) Create(rm *model.Reminder) error {
+	if err := rm.Validate(); err != nil {
+		return err
+	}
+
+	return r.store.db.QueryRow(
+		"INSERT INTO reminders (note_id, user_id, remind_at, recurrence, channel, target, occurrence) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at;",
+		rm.NoteID,
+		rm.UserID,
+		rm.RemindAt,
+		rm.Recurrence,
+		rm.Channel,
+		rm.Target,
+		rm.Occurrence,
+	).Scan(&rm.ID, &rm.CreatedAt)
+}
+
+// Update ...
+func (r *ReminderRepository) Update(rm *model.Reminder) error {
+	if err := rm.Validate(); err != nil {
+		return err
+	}
+
+	return execOne(
+		r.store.db,
+		"UPDATE reminders SET remind_at = $2, recurrence = $3, channel = $4, target = $5, occurrence = $6, last_error = $7 WHERE id = $1;",
+		rm.ID,
+		rm.RemindAt,
+		rm.Recurrence,
+		rm.Channel,
+		rm.Target,
+		rm.Occurrence,
+		rm.LastError,
+	)
+}
+
+// Delete ...
+func (r *ReminderRepository) Delete(id int) error {
+	return execOne(
+		r.store.db,
+		"DELETE FROM reminders WHERE id = $1;",
+		id,
+	)
+}
+
+// Find ...
+func (r *ReminderRepository) Find(id int) (*model.Reminder, error) {
+	rm, err := scanReminder(r.store.db.QueryRow(
+		"SELECT "+reminderColumns+" FROM reminders WHERE id = $1",
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return rm, nil
+}
+
+// FindByNote returns reminders of the user for the note, upcoming first
+func (r *ReminderRepository) FindByNote(noteID int, u *model.User) ([]*model.Reminder, error) {
+	return r.query(
+		"SELECT "+reminderColumns+" FROM reminders WHERE note_id = $1 AND user_id = $2 ORDER BY remind_at NULLS LAST, id",
+		noteID,
+		u.ID,
+	)
+}
+
+// FindByUser returns reminders of the user which have occurrences left
+func (r *ReminderRepository) FindByUser(u *model.User) ([]*model.Reminder, error) {
+	return r.query(
+		"SELECT "+reminderColumns+" FROM reminders WHERE user_id = $1 AND remind_at IS NOT NULL ORDER BY remind_at, id",
+		u.ID,
+	)
+}
+
+// FireDue calls fn for up to limit reminders due at the time and advances
+// them to the next occurrence. Reminders are claimed by advancing them in
+// a short transaction with SKIP LOCKED, so concurrent callers, like other
+// replicas of the server, get different reminders, and fn runs without
+// holding locks. An error of fn is saved in the reminder and doesn't stop
+// the batch. Reminders claimed by a server that stops before calling fn
+// are not delivered
+func (r *ReminderRepository) FireDue(now time.Time, limit int, fn func(*model.Reminder) error) (int, error) {
+	due, err := r.claimDue(now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, rm := range due {
+		if err := fn(rm); err != nil {
+			if _, err := r.store.db.Exec(
+				"UPDATE reminders SET last_error = $2 WHERE id = $1;",
+				rm.ID,
+				err.Error(),
+			); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(due), nil
+}
+
+// claimDue advances reminders due at the time and returns them
+// as they were before, with the occurrence to deliver
+func (r *ReminderRepository) claimDue(now time.Time, limit int) ([]*model.Reminder, error) {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	due, err := queryReminders(
+		tx,
+		"SELECT "+reminderColumns+" FROM reminders WHERE remind_at <= $1 ORDER BY remind_at, id LIMIT $2 FOR UPDATE SKIP LOCKED",
+		now,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rm := range due {
+		next := *rm
+		next.Advance(now)
+
+		if _, err := tx.Exec(
+			"UPDATE reminders SET remind_at = $2, occurrence = $3, fired = $4, last_fired_at = $5, last_error = '' WHERE id = $1;",
+			next.ID,
+			next.RemindAt,
+			next.Occurrence,
+			next.Fired,
+			next.LastFiredAt,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	return due, tx.Commit()
+}
+
+func (r *ReminderRepository) query(query string, args ...interface{}) ([]*model.Reminder, error) {
+	return queryReminders(r.store.db, query, args...)
+}
+
+func queryReminders(db querier, query string, args ...interface{}) ([]*model.Reminder, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.Reminder{}
+	for rows.Next() {
+		rm, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, rm)
+	}
+	return result, rows.Err()
+}
+
+func scanReminder(row scanner) (*model.Reminder, error) {
+	rm := &model.Reminder{}
+	if err := row.Scan(
+		&rm.ID,
+		&rm.NoteID,
+		&rm.UserID,
+		&rm.RemindAt,
+		&rm.Recurrence,
+		&rm.Channel,
+		&rm.Target,
+		&rm.Occurrence,
+		&rm.Fired,
+		&rm.LastFiredAt,
+		&rm.LastError,
+		&rm.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return rm, nil
+}
@@ -0,0 +1,184 @@
+package sqlstore_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/sqlstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReminderRepository_Create(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("reminders", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+
+	rm := model.TestReminder(t)
+	rm.NoteID = n.ID
+	rm.UserID = u.ID
+	assert.NoError(t, s.Reminders().Create(rm))
+	assert.NotZero(t, rm.ID)
+
+	rr, err := s.Reminders().Find(rm.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, rm.Channel, rr.Channel)
+	assert.WithinDuration(t, *rm.RemindAt, *rr.RemindAt, time.Millisecond)
+
+	assert.Error(t, s.Reminders().Create(&model.Reminder{NoteID: n.ID, UserID: u.ID}))
+}
+
+func TestReminderRepository_Update(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("reminders", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+	rm := model.TestReminder(t)
+	rm.NoteID = n.ID
+	rm.UserID = u.ID
+	s.Reminders().Create(rm)
+
+	rm.Recurrence = "FREQ=DAILY"
+	assert.NoError(t, s.Reminders().Update(rm))
+	rm.Recurrence = "FREQ=NEVER"
+	assert.Error(t, s.Reminders().Update(rm))
+	rm.Recurrence = ""
+	rm.ID++
+	assert.EqualError(t, s.Reminders().Update(rm), store.ErrRecordNotFound.Error())
+
+	rr, err := s.Reminders().Find(rm.ID - 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "FREQ=DAILY", rr.Recurrence)
+}
+
+func TestReminderRepository_Delete(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("reminders", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+	rm := model.TestReminder(t)
+	rm.NoteID = n.ID
+	rm.UserID = u.ID
+	s.Reminders().Create(rm)
+
+	assert.NoError(t, s.Reminders().Delete(rm.ID))
+	assert.EqualError(t, s.Reminders().Delete(rm.ID), store.ErrRecordNotFound.Error())
+
+	// reminders are deleted with the note
+	s.Reminders().Create(rm)
+	s.Notes().Delete(n.ID)
+	_, err := s.Reminders().Find(rm.ID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestReminderRepository_FindByUser(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("reminders", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	s.User().Create(other)
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+
+	for i, user := range []*model.User{u, u, other} {
+		remindAt := time.Now().Add(time.Duration(3-i) * time.Hour)
+		rm := model.TestReminder(t)
+		rm.NoteID = n.ID
+		rm.UserID = user.ID
+		rm.RemindAt = &remindAt
+		s.Reminders().Create(rm)
+	}
+
+	rl, err := s.Reminders().FindByUser(u)
+	assert.NoError(t, err)
+	if assert.Len(t, rl, 2) {
+		assert.True(t, rl[0].RemindAt.Before(*rl[1].RemindAt))
+	}
+
+	rl, err = s.Reminders().FindByNote(n.ID, other)
+	assert.NoError(t, err)
+	assert.Len(t, rl, 1)
+}
+
+func TestReminderRepository_FireDue(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("reminders", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+	once := model.TestReminder(t)
+	once.RemindAt = &past
+	recurring := model.TestReminder(t)
+	recurring.RemindAt = &past
+	recurring.Recurrence = "FREQ=DAILY"
+	later := model.TestReminder(t)
+	later.RemindAt = &future
+	for _, rm := range []*model.Reminder{once, recurring, later} {
+		rm.NoteID = n.ID
+		rm.UserID = u.ID
+		s.Reminders().Create(rm)
+	}
+
+	fired := []int{}
+	count, err := s.Reminders().FireDue(now, 10, func(rm *model.Reminder) error {
+		fired = append(fired, rm.ID)
+		assert.WithinDuration(t, past, *rm.RemindAt, time.Millisecond)
+
+		// reminders aren't locked while they're delivered
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+		defer tx.Rollback()
+		_, err = tx.Exec("SET LOCAL lock_timeout = '1s';")
+		assert.NoError(t, err)
+		_, err = tx.Exec("UPDATE reminders SET target = target WHERE id = $1;", rm.ID)
+		assert.NoError(t, err)
+
+		if rm.ID == once.ID {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.ElementsMatch(t, []int{once.ID, recurring.ID}, fired)
+
+	rm, err := s.Reminders().Find(once.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, rm.RemindAt)
+	assert.Equal(t, 1, rm.Fired)
+	assert.Equal(t, "unavailable", rm.LastError)
+
+	rm, err = s.Reminders().Find(recurring.ID)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, past.AddDate(0, 0, 1), *rm.RemindAt, time.Millisecond)
+
+	count, err = s.Reminders().FireDue(now, 10, func(*model.Reminder) error { return nil })
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,89 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// RevisionRepository ...
+type RevisionRepository struct {
+	store *Store
+}
+
+// FindByNote returns revisions of the note, the latest first
+func (r *RevisionRepository) FindByNote(noteID int) ([]*model.NoteRevision, error) {
+	rows, err := r.store.db.Query(
+		"SELECT id, note_id, revision, header, body, created_at FROM note_revisions WHERE note_id = $1 ORDER BY revision DESC",
+		noteID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.NoteRevision{}
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, rev)
+	}
+	return result, rows.Err()
+}
+
+// Find ...
+func (r *RevisionRepository) Find(noteID int, revision int) (*model.NoteRevision, error) {
+	rev, err := scanRevision(r.store.db.QueryRow(
+		"SELECT id, note_id, revision, header, body, created_at FROM note_revisions WHERE note_id = $1 AND revision = $2",
+		noteID,
+		revision,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return rev, nil
+}
+
+// Prune deletes all but keep latest revisions of the note
+func (r *RevisionRepository) Prune(noteID int, keep int) error {
+	_, err := r.store.db.Exec(
+		"DELETE FROM note_revisions WHERE note_id = $1 AND revision <= "+
+			"(SELECT max(revision) FROM note_revisions WHERE note_id = $1) - $2;",
+		noteID,
+		keep,
+	)
+	return err
+}
+
+func insertRevision(tx *sql.Tx, n *model.Note) error {
+	_, err := tx.Exec(
+		"INSERT INTO note_revisions (note_id, revision, header, body, created_at) "+
+			"SELECT $1, COALESCE(max(revision), 0) + 1, $2, $3, $4 FROM note_revisions WHERE note_id = $1;",
+		n.ID,
+		n.Header,
+		n.Body,
+		n.UpdatedAt,
+	)
+	return err
+}
+
+func scanRevision(row scanner) (*model.NoteRevision, error) {
+	rev := &model.NoteRevision{}
+	if err := row.Scan(
+		&rev.ID,
+		&rev.NoteID,
+		&rev.Revision,
+		&rev.Header,
+		&rev.Body,
+		&rev.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
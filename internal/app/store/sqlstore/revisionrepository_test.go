@@ -0,0 +1,71 @@
+package sqlstore_test
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/sqlstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevisionRepository_FindByNote(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_revisions", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.Notes().Create(n, u)
+	s.Notes().Update(n.ID, &model.Note{Body: "changed"})
+	s.Notes().Update(n.ID, &model.Note{Body: "changed"})
+
+	rl, err := s.Revisions().FindByNote(n.ID)
+	assert.NoError(t, err)
+	assert.Len(t, rl, 2)
+	assert.Equal(t, 2, rl[0].Revision)
+	assert.Equal(t, "changed", rl[0].Body)
+	assert.Equal(t, 1, rl[1].Revision)
+	assert.Equal(t, "body", rl[1].Body)
+}
+
+func TestRevisionRepository_Find(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_revisions", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.Notes().Create(n, u)
+
+	_, err := s.Revisions().Find(n.ID, 2)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	rev, err := s.Revisions().Find(n.ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, n.Header, rev.Header)
+	assert.Equal(t, n.Body, rev.Body)
+}
+
+func TestRevisionRepository_Prune(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_revisions", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.Notes().Create(n, u)
+	for _, body := range []string{"one", "two", "three"} {
+		s.Notes().Update(n.ID, &model.Note{Body: body})
+	}
+
+	assert.NoError(t, s.Revisions().Prune(n.ID, 2))
+	rl, err := s.Revisions().FindByNote(n.ID)
+	assert.NoError(t, err)
+	assert.Len(t, rl, 2)
+	assert.Equal(t, 4, rl[0].Revision)
+	assert.Equal(t, 3, rl[1].Revision)
+}
@@ -0,0 +1,142 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+const sessionColumns = "id, user_id, token_hash, device, ip, created_at, last_seen_at, expires_at"
+
+// SessionRepository ...
+type SessionRepository struct {
+	store *Store
+}
+
+// Create generates the token of the session and saves it,
+// expired sessions of the user are deleted on the way
+func (r *SessionRepository) Create(s *model.Session) error {
+	if err := s.BeforeCreate(); err != nil {
+		return err
+	}
+
+	if _, err := r.store.db.Exec(
+		"DELETE FROM sessions WHERE user_id = $1 AND expires_at <= $2;",
+		s.UserID,
+		time.Now(),
+	); err != nil {
+		return err
+	}
+
+	return r.store.db.QueryRow(
+		"INSERT INTO sessions (user_id, token_hash, device, ip, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, last_seen_at;",
+		s.UserID,
+		s.TokenHash,
+		s.Device,
+		s.IP,
+		s.ExpiresAt,
+	).Scan(&s.ID, &s.CreatedAt, &s.LastSeenAt)
+}
+
+// Touch records the activity of the session
+func (r *SessionRepository) Touch(id int, ip string, at time.Time) error {
+	return execOne(
+		r.store.db,
+		"UPDATE sessions SET ip = $2, last_seen_at = $3 WHERE id = $1;",
+		id,
+		ip,
+		at,
+	)
+}
+
+// Delete ...
+func (r *SessionRepository) Delete(id int) error {
+	return execOne(
+		r.store.db,
+		"DELETE FROM sessions WHERE id = $1;",
+		id,
+	)
+}
+
+// DeleteByUser deletes sessions of the user except the session with
+// the id, zero id deletes all of them
+func (r *SessionRepository) DeleteByUser(userID int, except int) (int, error) {
+	res, err := r.store.db.Exec(
+		"DELETE FROM sessions WHERE user_id = $1 AND id <> $2;",
+		userID,
+		except,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := res.RowsAffected()
+	return int(count), err
+}
+
+// Find returns the session unless it's expired
+func (r *SessionRepository) Find(id int) (*model.Session, error) {
+	return r.find("id = $1", id)
+}
+
+// FindByToken returns the session with the token hash unless it's expired
+func (r *SessionRepository) FindByToken(hash string) (*model.Session, error) {
+	return r.find("token_hash = $1", hash)
+}
+
+// FindByUser returns active sessions of the user, the last seen first
+func (r *SessionRepository) FindByUser(u *model.User) ([]*model.Session, error) {
+	rows, err := r.store.db.Query(
+		"SELECT "+sessionColumns+" FROM sessions WHERE user_id = $1 AND expires_at > $2 ORDER BY last_seen_at DESC, id DESC",
+		u.ID,
+		time.Now(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.Session{}
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+func (r *SessionRepository) find(cond string, arg interface{}) (*model.Session, error) {
+	s, err := scanSession(r.store.db.QueryRow(
+		"SELECT "+sessionColumns+" FROM sessions WHERE "+cond+" AND expires_at > $2",
+		arg,
+		time.Now(),
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func scanSession(row scanner) (*model.Session, error) {
+	s := &model.Session{}
+	if err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.TokenHash,
+		&s.Device,
+		&s.IP,
+		&s.CreatedAt,
+		&s.LastSeenAt,
+		&s.ExpiresAt,
+	); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,117 @@
+package sqlstore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/sqlstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionRepository_Create(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("sessions", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	ss := &model.Session{UserID: u.ID, Device: "curl", IP: "127.0.0.1", ExpiresAt: time.Now().Add(time.Hour)}
+	assert.NoError(t, s.Sessions().Create(ss))
+	assert.NotZero(t, ss.ID)
+	assert.NotEmpty(t, ss.Token)
+
+	rs, err := s.Sessions().FindByToken(model.HashToken(ss.Token))
+	assert.NoError(t, err)
+	assert.Equal(t, ss.ID, rs.ID)
+	assert.Equal(t, "curl", rs.Device)
+
+	_, err = s.Sessions().FindByToken(model.HashToken("unknown"))
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestSessionRepository_Touch(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("sessions", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+	ss := &model.Session{UserID: u.ID, Device: "curl", IP: "127.0.0.1", ExpiresAt: time.Now().Add(time.Hour)}
+	s.Sessions().Create(ss)
+
+	at := time.Now().Add(time.Minute)
+	assert.NoError(t, s.Sessions().Touch(ss.ID, "10.0.0.1", at))
+	assert.EqualError(t, s.Sessions().Touch(ss.ID+1, "10.0.0.1", at), store.ErrRecordNotFound.Error())
+
+	rs, err := s.Sessions().Find(ss.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", rs.IP)
+	assert.WithinDuration(t, at, rs.LastSeenAt, time.Millisecond)
+}
+
+func TestSessionRepository_FindByUser(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("sessions", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	s.User().Create(other)
+
+	var last *model.Session
+	for i, user := range []*model.User{u, u, other} {
+		ss := &model.Session{UserID: user.ID, Device: "curl", IP: "127.0.0.1", ExpiresAt: time.Now().Add(time.Hour)}
+		s.Sessions().Create(ss)
+		s.Sessions().Touch(ss.ID, "127.0.0.1", time.Now().Add(time.Duration(i)*time.Minute))
+		if user == u {
+			last = ss
+		}
+	}
+	expired := &model.Session{UserID: u.ID, Device: "curl", IP: "127.0.0.1", ExpiresAt: time.Now().Add(-time.Hour)}
+	s.Sessions().Create(expired)
+
+	sl, err := s.Sessions().FindByUser(u)
+	assert.NoError(t, err)
+	if assert.Len(t, sl, 2) {
+		assert.Equal(t, last.ID, sl[0].ID)
+	}
+	_, err = s.Sessions().Find(expired.ID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestSessionRepository_Delete(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("sessions", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	s.User().Create(other)
+
+	sessions := []*model.Session{}
+	for _, user := range []*model.User{u, u, u, other} {
+		ss := &model.Session{UserID: user.ID, Device: "curl", IP: "127.0.0.1", ExpiresAt: time.Now().Add(time.Hour)}
+		s.Sessions().Create(ss)
+		sessions = append(sessions, ss)
+	}
+
+	assert.NoError(t, s.Sessions().Delete(sessions[0].ID))
+	assert.EqualError(t, s.Sessions().Delete(sessions[0].ID), store.ErrRecordNotFound.Error())
+
+	count, err := s.Sessions().DeleteByUser(u.ID, sessions[1].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	count, err = s.Sessions().DeleteByUser(u.ID, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	_, err = s.Sessions().Find(sessions[3].ID)
+	assert.NoError(t, err)
+}
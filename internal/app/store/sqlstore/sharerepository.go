@@ -0,0 +1,129 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// ShareRepository ...
+type ShareRepository struct {
+	store *Store
+}
+
+// Save shares the note with the user or changes the role of existing share
+func (r *ShareRepository) Save(s *model.NoteShare) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	return r.store.db.QueryRow(
+		"INSERT INTO note_shares (note_id, user_id, role) VALUES ($1, $2, $3) "+
+			"ON CONFLICT (note_id, user_id) DO UPDATE SET role = EXCLUDED.role RETURNING created_at;",
+		s.NoteID,
+		s.UserID,
+		s.Role,
+	).Scan(&s.CreatedAt)
+}
+
+// Delete ...
+func (r *ShareRepository) Delete(noteID int, userID int) error {
+	res, err := r.store.db.Exec(
+		"DELETE FROM note_shares WHERE note_id = $1 AND user_id = $2;",
+		noteID,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return store.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Find ...
+func (r *ShareRepository) Find(noteID int, userID int) (*model.NoteShare, error) {
+	s := &model.NoteShare{}
+	if err := r.store.db.QueryRow(
+		"SELECT s.note_id, s.user_id, u.email, s.role, s.created_at FROM note_shares s JOIN users u ON u.id = s.user_id "+
+			"WHERE s.note_id = $1 AND s.user_id = $2",
+		noteID,
+		userID,
+	).Scan(
+		&s.NoteID,
+		&s.UserID,
+		&s.Email,
+		&s.Role,
+		&s.CreatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// FindByNote returns users the note is shared with
+func (r *ShareRepository) FindByNote(noteID int) ([]*model.NoteShare, error) {
+	rows, err := r.store.db.Query(
+		"SELECT s.note_id, s.user_id, u.email, s.role, s.created_at FROM note_shares s JOIN users u ON u.id = s.user_id "+
+			"WHERE s.note_id = $1 ORDER BY u.email",
+		noteID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.NoteShare{}
+	for rows.Next() {
+		s := &model.NoteShare{}
+		if err := rows.Scan(
+			&s.NoteID,
+			&s.UserID,
+			&s.Email,
+			&s.Role,
+			&s.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+// FindByUser returns notes shared with the user, the latest shared first
+func (r *ShareRepository) FindByUser(u *model.User) ([]*model.NoteShare, error) {
+	rows, err := r.store.db.Query(
+		"SELECT "+noteColumns+", s.role, s.created_at FROM note_shares s JOIN notes ON notes.id = s.note_id "+
+			"WHERE s.user_id = $1 AND notes.deleted_at IS NULL ORDER BY s.created_at DESC, notes.id DESC",
+		u.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.NoteShare{}
+	for rows.Next() {
+		s := &model.NoteShare{
+			UserID: u.ID,
+			Email:  u.Email,
+		}
+		s.Note, err = scanNote(rows, &s.Role, &s.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		s.NoteID = s.Note.ID
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,126 @@
+package sqlstore_test
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/sqlstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareRepository_Save(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_shares", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.User().Create(other)
+	s.Notes().Create(n, u)
+
+	sh := model.TestNoteShare(t)
+	sh.NoteID = n.ID
+	sh.UserID = other.ID
+	assert.NoError(t, s.Shares().Save(sh))
+
+	sh.Role = model.RoleEditor
+	assert.NoError(t, s.Shares().Save(sh))
+	rs, err := s.Shares().Find(n.ID, other.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.RoleEditor, rs.Role)
+	assert.Equal(t, other.Email, rs.Email)
+
+	sh.Role = model.RoleOwner
+	assert.Error(t, s.Shares().Save(sh))
+}
+
+func TestShareRepository_Delete(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_shares", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.User().Create(other)
+	s.Notes().Create(n, u)
+
+	assert.EqualError(t, s.Shares().Delete(n.ID, other.ID), store.ErrRecordNotFound.Error())
+
+	sh := model.TestNoteShare(t)
+	sh.NoteID = n.ID
+	sh.UserID = other.ID
+	s.Shares().Save(sh)
+	assert.NoError(t, s.Shares().Delete(n.ID, other.ID))
+
+	_, err := s.Shares().Find(n.ID, other.ID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestShareRepository_FindByNote(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_shares", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.User().Create(other)
+	s.Notes().Create(n, u)
+
+	sl, err := s.Shares().FindByNote(n.ID)
+	assert.NoError(t, err)
+	assert.Len(t, sl, 0)
+
+	sh := model.TestNoteShare(t)
+	sh.NoteID = n.ID
+	sh.UserID = other.ID
+	s.Shares().Save(sh)
+
+	sl, err = s.Shares().FindByNote(n.ID)
+	assert.NoError(t, err)
+	assert.Len(t, sl, 1)
+	assert.Equal(t, other.Email, sl[0].Email)
+}
+
+func TestShareRepository_FindByUser(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_shares", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	n1 := model.TestNote(t)
+	n2 := model.TestNote(t)
+	s.User().Create(u)
+	s.User().Create(other)
+	s.Notes().Create(n1, u)
+	s.Notes().Create(n2, u)
+
+	for _, n := range []*model.Note{n1, n2} {
+		sh := model.TestNoteShare(t)
+		sh.NoteID = n.ID
+		sh.UserID = other.ID
+		s.Shares().Save(sh)
+	}
+	s.Notes().Trash(n2.ID)
+
+	sl, err := s.Shares().FindByUser(other)
+	assert.NoError(t, err)
+	assert.Len(t, sl, 1)
+	assert.Equal(t, n1.ID, sl[0].Note.ID)
+	assert.Equal(t, model.RoleViewer, sl[0].Role)
+
+	sl, err = s.Shares().FindByUser(u)
+	assert.NoError(t, err)
+	assert.Len(t, sl, 0)
+}
@@ -9,9 +9,27 @@ import (
 
 // Store ...
 type Store struct {
-	db             *sql.DB
-	userRepository *UserRepository
-	noteRepository *NoteRepository
+	db                          *sql.DB
+	userRepository              *UserRepository
+	noteRepository              *NoteRepository
+	tagRepository               *TagRepository
+	revisionRepository          *RevisionRepository
+	shareRepository             *ShareRepository
+	linkRepository              *LinkRepository
+	notebookRepository          *NotebookRepository
+	attachmentRepository        *AttachmentRepository
+	templateRepository          *TemplateRepository
+	referenceRepository         *ReferenceRepository
+	reminderRepository          *ReminderRepository
+	notificationRepository      *NotificationRepository
+	checklistRepository         *ChecklistRepository
+	commentRepository           *CommentRepository
+	sessionRepository           *SessionRepository
+	refreshTokenRepository      *RefreshTokenRepository
+	apiTokenRepository          *APITokenRepository
+	passwordResetRepository     *PasswordResetRepository
+	emailVerificationRepository *EmailVerificationRepository
+	importJobRepository         *ImportJobRepository
 }
 
 // New ...
@@ -46,3 +64,237 @@ func (s *Store) Notes() store.NoteRepository {
 
 	return s.noteRepository
 }
+
+// Tags ...
+func (s *Store) Tags() store.TagRepository {
+	if s.tagRepository != nil {
+		return s.tagRepository
+	}
+
+	s.tagRepository = &TagRepository{
+		store: s,
+	}
+
+	return s.tagRepository
+}
+
+// Revisions ...
+func (s *Store) Revisions() store.RevisionRepository {
+	if s.revisionRepository != nil {
+		return s.revisionRepository
+	}
+
+	s.revisionRepository = &RevisionRepository{
+		store: s,
+	}
+
+	return s.revisionRepository
+}
+
+// Shares ...
+func (s *Store) Shares() store.ShareRepository {
+	if s.shareRepository != nil {
+		return s.shareRepository
+	}
+
+	s.shareRepository = &ShareRepository{
+		store: s,
+	}
+
+	return s.shareRepository
+}
+
+// Links ...
+func (s *Store) Links() store.LinkRepository {
+	if s.linkRepository != nil {
+		return s.linkRepository
+	}
+
+	s.linkRepository = &LinkRepository{
+		store: s,
+	}
+
+	return s.linkRepository
+}
+
+// Notebooks ...
+func (s *Store) Notebooks() store.NotebookRepository {
+	if s.notebookRepository != nil {
+		return s.notebookRepository
+	}
+
+	s.notebookRepository = &NotebookRepository{
+		store: s,
+	}
+
+	return s.notebookRepository
+}
+
+// Attachments ...
+func (s *Store) Attachments() store.AttachmentRepository {
+	if s.attachmentRepository != nil {
+		return s.attachmentRepository
+	}
+
+	s.attachmentRepository = &AttachmentRepository{
+		store: s,
+	}
+
+	return s.attachmentRepository
+}
+
+// Templates ...
+func (s *Store) Templates() store.TemplateRepository {
+	if s.templateRepository != nil {
+		return s.templateRepository
+	}
+
+	s.templateRepository = &TemplateRepository{
+		store: s,
+	}
+
+	return s.templateRepository
+}
+
+// References ...
+func (s *Store) References() store.ReferenceRepository {
+	if s.referenceRepository != nil {
+		return s.referenceRepository
+	}
+
+	s.referenceRepository = &ReferenceRepository{
+		store: s,
+	}
+
+	return s.referenceRepository
+}
+
+// Reminders ...
+func (s *Store) Reminders() store.ReminderRepository {
+	if s.reminderRepository != nil {
+		return s.reminderRepository
+	}
+
+	s.reminderRepository = &ReminderRepository{
+		store: s,
+	}
+
+	return s.reminderRepository
+}
+
+// Notifications ...
+func (s *Store) Notifications() store.NotificationRepository {
+	if s.notificationRepository != nil {
+		return s.notificationRepository
+	}
+
+	s.notificationRepository = &NotificationRepository{
+		store: s,
+	}
+
+	return s.notificationRepository
+}
+
+// Checklist ...
+func (s *Store) Checklist() store.ChecklistRepository {
+	if s.checklistRepository != nil {
+		return s.checklistRepository
+	}
+
+	s.checklistRepository = &ChecklistRepository{
+		store: s,
+	}
+
+	return s.checklistRepository
+}
+
+// Comments ...
+func (s *Store) Comments() store.CommentRepository {
+	if s.commentRepository != nil {
+		return s.commentRepository
+	}
+
+	s.commentRepository = &CommentRepository{
+		store: s,
+	}
+
+	return s.commentRepository
+}
+
+// Sessions ...
+func (s *Store) Sessions() store.SessionRepository {
+	if s.sessionRepository != nil {
+		return s.sessionRepository
+	}
+
+	s.sessionRepository = &SessionRepository{
+		store: s,
+	}
+
+	return s.sessionRepository
+}
+
+// RefreshTokens ...
+func (s *Store) RefreshTokens() store.RefreshTokenRepository {
+	if s.refreshTokenRepository != nil {
+		return s.refreshTokenRepository
+	}
+
+	s.refreshTokenRepository = &RefreshTokenRepository{
+		store: s,
+	}
+
+	return s.refreshTokenRepository
+}
+
+// APITokens ...
+func (s *Store) APITokens() store.APITokenRepository {
+	if s.apiTokenRepository != nil {
+		return s.apiTokenRepository
+	}
+
+	s.apiTokenRepository = &APITokenRepository{
+		store: s,
+	}
+
+	return s.apiTokenRepository
+}
+
+// PasswordResets ...
+func (s *Store) PasswordResets() store.PasswordResetRepository {
+	if s.passwordResetRepository != nil {
+		return s.passwordResetRepository
+	}
+
+	s.passwordResetRepository = &PasswordResetRepository{
+		store: s,
+	}
+
+	return s.passwordResetRepository
+}
+
+// EmailVerifications ...
+func (s *Store) EmailVerifications() store.EmailVerificationRepository {
+	if s.emailVerificationRepository != nil {
+		return s.emailVerificationRepository
+	}
+
+	s.emailVerificationRepository = &EmailVerificationRepository{
+		store: s,
+	}
+
+	return s.emailVerificationRepository
+}
+
+// ImportJobs ...
+func (s *Store) ImportJobs() store.ImportJobRepository {
+	if s.importJobRepository != nil {
+		return s.importJobRepository
+	}
+
+	s.importJobRepository = &ImportJobRepository{
+		store: s,
+	}
+
+	return s.importJobRepository
+}
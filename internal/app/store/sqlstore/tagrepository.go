@@ -0,0 +1,106 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+)
+
+// TagRepository ...
+type TagRepository struct {
+	store *Store
+}
+
+// SetNoteTags replaces tags of the note, creating missing tags for its author
+func (r *TagRepository) SetNoteTags(n *model.Note, names []string) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setNoteTags(tx, n, names); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// setNoteTags replaces tags of the note within the transaction
+func setNoteTags(tx *sql.Tx, n *model.Note, names []string) error {
+	names = model.NormalizeTags(names)
+	for _, name := range names {
+		t := &model.Tag{Name: name}
+		if err := t.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(
+		"DELETE FROM note_tags WHERE note_id = $1;",
+		n.ID,
+	); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		var tagID int
+		if err := tx.QueryRow(
+			"INSERT INTO tags (user_id, name) VALUES ($1, $2) ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name RETURNING id;",
+			n.AuthorID,
+			name,
+		).Scan(&tagID); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(
+			"INSERT INTO note_tags (note_id, tag_id) VALUES ($1, $2);",
+			n.ID,
+			tagID,
+		); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(
+		"DELETE FROM tags t WHERE user_id = $1 AND NOT EXISTS (SELECT 1 FROM note_tags nt WHERE nt.tag_id = t.id);",
+		n.AuthorID,
+	); err != nil {
+		return err
+	}
+
+	n.Tags = nil
+	if len(names) > 0 {
+		n.Tags = names
+	}
+	return nil
+}
+
+// FindByUser returns tags of the user with count of notes marked by each
+func (r *TagRepository) FindByUser(u *model.User) ([]*model.Tag, error) {
+	rows, err := r.store.db.Query(
+		"SELECT t.id, t.user_id, t.name, count(nt.note_id) FROM tags t JOIN note_tags nt ON nt.tag_id = t.id "+
+			"JOIN notes n ON n.id = nt.note_id AND n.deleted_at IS NULL "+
+			"WHERE t.user_id = $1 GROUP BY t.id ORDER BY t.name",
+		u.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.Tag{}
+	for rows.Next() {
+		t := &model.Tag{}
+		if err := rows.Scan(
+			&t.ID,
+			&t.UserID,
+			&t.Name,
+			&t.NoteCount,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, rows.Err()
+}
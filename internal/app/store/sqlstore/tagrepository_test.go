@@ -0,0 +1,62 @@
+package sqlstore_test
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/sqlstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagRepository_SetNoteTags(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_tags", "tags", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	n := model.TestNote(t)
+	s.User().Create(u)
+
+	assert.Error(t, s.Tags().SetNoteTags(n, []string{"work"}))
+
+	s.Notes().Create(n, u)
+	assert.NoError(t, s.Tags().SetNoteTags(n, []string{"work", " urgent", "work"}))
+	assert.Equal(t, []string{"work", "urgent"}, n.Tags)
+
+	rn, err := s.Notes().FindByID(n.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"urgent", "work"}, rn.Tags)
+
+	assert.NoError(t, s.Tags().SetNoteTags(n, nil))
+	rn, err = s.Notes().FindByID(n.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, rn.Tags)
+}
+
+func TestTagRepository_FindByUser(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("note_tags", "tags", "notes", "users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	tags, err := s.Tags().FindByUser(u)
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.Tag{}, tags)
+
+	n1 := model.TestNote(t)
+	n2 := model.TestNote(t)
+	s.Notes().Create(n1, u)
+	s.Notes().Create(n2, u)
+	s.Tags().SetNoteTags(n1, []string{"work", "urgent"})
+	s.Tags().SetNoteTags(n2, []string{"work"})
+
+	tags, err = s.Tags().FindByUser(u)
+	assert.NoError(t, err)
+	assert.Len(t, tags, 2)
+	assert.Equal(t, "urgent", tags[0].Name)
+	assert.Equal(t, 1, tags[0].NoteCount)
+	assert.Equal(t, "work", tags[1].Name)
+	assert.Equal(t, 2, tags[1].NoteCount)
+}
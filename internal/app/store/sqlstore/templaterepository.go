@@ -0,0 +1,111 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+const templateColumns = "id, user_id, name, header, body, created_at, updated_at"
+
+// TemplateRepository ...
+type TemplateRepository struct {
+	store *Store
+}
+
+// Create ...
+func (r *TemplateRepository) Create(t *model.Template) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	return r.store.db.QueryRow(
+		"INSERT INTO templates (user_id, name, header, body) VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at;",
+		t.UserID,
+		t.Name,
+		t.Header,
+		t.Body,
+	).Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
+}
+
+// Update saves name, header and body of the template
+func (r *TemplateRepository) Update(t *model.Template) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	t.UpdatedAt = time.Now()
+	return execOne(
+		r.store.db,
+		"UPDATE templates SET name = $2, header = $3, body = $4, updated_at = $5 WHERE id = $1;",
+		t.ID,
+		t.Name,
+		t.Header,
+		t.Body,
+		t.UpdatedAt,
+	)
+}
+
+// Delete ...
+func (r *TemplateRepository) Delete(id int) error {
+	return execOne(
+		r.store.db,
+		"DELETE FROM templates WHERE id = $1;",
+		id,
+	)
+}
+
+// Find ...
+func (r *TemplateRepository) Find(id int) (*model.Template, error) {
+	t, err := scanTemplate(r.store.db.QueryRow(
+		"SELECT "+templateColumns+" FROM templates WHERE id = $1",
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// FindByUser returns templates of the user ordered by name
+func (r *TemplateRepository) FindByUser(u *model.User) ([]*model.Template, error) {
+	rows, err := r.store.db.Query(
+		"SELECT "+templateColumns+" FROM templates WHERE user_id = $1 ORDER BY name, id",
+		u.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*model.Template{}
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, rows.Err()
+}
+
+func scanTemplate(row scanner) (*model.Template, error) {
+	t := &model.Template{}
+	if err := row.Scan(
+		&t.ID,
+		&t.UserID,
+		&t.Name,
+		&t.Header,
+		&t.Body,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -2,6 +2,7 @@ package sqlstore
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/KapitanD/http-api-server/internal/app/model"
 	"github.com/KapitanD/http-api-server/internal/app/store"
@@ -34,12 +35,13 @@ func (r *UserRepository) Create(u *model.User) error {
 func (r *UserRepository) FindByEmail(email string) (*model.User, error) {
 	u := &model.User{}
 	if err := r.store.db.QueryRow(
-		"SELECT id, email, encrypted_password FROM users WHERE email = $1",
+		"SELECT id, email, encrypted_password, email_verified_at FROM users WHERE email = $1",
 		email,
 	).Scan(
 		&u.ID,
 		&u.Email,
 		&u.EncryptedPassword,
+		&u.EmailVerifiedAt,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, store.ErrRecordNotFound
@@ -53,12 +55,13 @@ func (r *UserRepository) FindByEmail(email string) (*model.User, error) {
 func (r *UserRepository) Find(id int) (*model.User, error) {
 	u := &model.User{}
 	if err := r.store.db.QueryRow(
-		"SELECT id, email, encrypted_password FROM users WHERE id = $1",
+		"SELECT id, email, encrypted_password, email_verified_at FROM users WHERE id = $1",
 		id,
 	).Scan(
 		&u.ID,
 		&u.Email,
 		&u.EncryptedPassword,
+		&u.EmailVerifiedAt,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, store.ErrRecordNotFound
@@ -67,3 +70,31 @@ func (r *UserRepository) Find(id int) (*model.User, error) {
 	}
 	return u, nil
 }
+
+// UpdatePassword validates and encrypts the new password of the user
+func (r *UserRepository) UpdatePassword(u *model.User) error {
+	if err := u.ValidatePassword(); err != nil {
+		return err
+	}
+
+	if err := u.BeforeCreate(); err != nil {
+		return err
+	}
+
+	return execOne(
+		r.store.db,
+		"UPDATE users SET encrypted_password = $2 WHERE id = $1;",
+		u.ID,
+		u.EncryptedPassword,
+	)
+}
+
+// MarkVerified ...
+func (r *UserRepository) MarkVerified(id int, at time.Time) error {
+	return execOne(
+		r.store.db,
+		"UPDATE users SET email_verified_at = $2 WHERE id = $1;",
+		id,
+		at,
+	)
+}
@@ -2,6 +2,7 @@ package sqlstore_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/KapitanD/http-api-server/internal/app/model"
 	"github.com/KapitanD/http-api-server/internal/app/store"
@@ -48,3 +49,42 @@ func TestUserRepository_Find(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, u2)
 }
+
+func TestUserRepository_UpdatePassword(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	assert.Error(t, s.User().UpdatePassword(&model.User{ID: u.ID, Password: "short"}))
+	assert.NoError(t, s.User().UpdatePassword(&model.User{ID: u.ID, Password: "new password"}))
+	assert.EqualError(t, s.User().UpdatePassword(&model.User{ID: u.ID + 1, Password: "new password"}), store.ErrRecordNotFound.Error())
+
+	fu, err := s.User().Find(u.ID)
+	assert.NoError(t, err)
+	assert.True(t, fu.ComparePassword("new password"))
+	assert.False(t, fu.ComparePassword("password"))
+}
+
+func TestUserRepository_MarkVerified(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("users")
+
+	s := sqlstore.New(db)
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	fu, err := s.User().Find(u.ID)
+	assert.NoError(t, err)
+	assert.False(t, fu.Verified())
+
+	at := time.Now()
+	assert.NoError(t, s.User().MarkVerified(u.ID, at))
+	assert.EqualError(t, s.User().MarkVerified(u.ID+1, at), store.ErrRecordNotFound.Error())
+
+	fu, err = s.User().FindByEmail(u.Email)
+	assert.NoError(t, err)
+	assert.True(t, fu.Verified())
+}
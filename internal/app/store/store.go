@@ -4,4 +4,22 @@ package store
 type Store interface {
 	User() UserRepository
 	Notes() NoteRepository
+	Tags() TagRepository
+	Revisions() RevisionRepository
+	Shares() ShareRepository
+	Links() LinkRepository
+	Notebooks() NotebookRepository
+	Attachments() AttachmentRepository
+	Templates() TemplateRepository
+	References() ReferenceRepository
+	Reminders() ReminderRepository
+	Notifications() NotificationRepository
+	Checklist() ChecklistRepository
+	Comments() CommentRepository
+	Sessions() SessionRepository
+	RefreshTokens() RefreshTokenRepository
+	APITokens() APITokenRepository
+	PasswordResets() PasswordResetRepository
+	EmailVerifications() EmailVerificationRepository
+	ImportJobs() ImportJobRepository
 }
@@ -0,0 +1,101 @@
+package teststore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// APITokenRepository ...
+type APITokenRepository struct {
+	store  *Store
+	tokens map[int]*model.APIToken
+	lastID int
+}
+
+// Create ...
+func (r *APITokenRepository) Create(t *model.APIToken) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	if err := t.BeforeCreate(); err != nil {
+		return err
+	}
+
+	r.lastID++
+	t.ID = r.lastID
+	t.CreatedAt = time.Now()
+	c := *t
+	c.Token = ""
+	r.tokens[t.ID] = &c
+	return nil
+}
+
+// Touch ...
+func (r *APITokenRepository) Touch(id int, at time.Time) error {
+	t, ok := r.tokens[id]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	t.LastUsedAt = &at
+	return nil
+}
+
+// Delete ...
+func (r *APITokenRepository) Delete(id int) error {
+	if _, ok := r.tokens[id]; !ok {
+		return store.ErrRecordNotFound
+	}
+	delete(r.tokens, id)
+	return nil
+}
+
+// DeleteByUser ...
+func (r *APITokenRepository) DeleteByUser(userID int) error {
+	for id, t := range r.tokens {
+		if t.UserID == userID {
+			delete(r.tokens, id)
+		}
+	}
+	return nil
+}
+
+// Find ...
+func (r *APITokenRepository) Find(id int) (*model.APIToken, error) {
+	t, ok := r.tokens[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+	c := *t
+	return &c, nil
+}
+
+// FindByToken ...
+func (r *APITokenRepository) FindByToken(hash string) (*model.APIToken, error) {
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.TokenHash == hash && (t.ExpiresAt == nil || t.ExpiresAt.After(now)) {
+			c := *t
+			return &c, nil
+		}
+	}
+	return nil, store.ErrRecordNotFound
+}
+
+// FindByUser ...
+func (r *APITokenRepository) FindByUser(u *model.User) ([]*model.APIToken, error) {
+	result := []*model.APIToken{}
+	for _, t := range r.tokens {
+		if t.UserID == u.ID {
+			c := *t
+			result = append(result, &c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID > result[j].ID
+	})
+	return result, nil
+}
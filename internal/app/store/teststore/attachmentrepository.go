@@ -0,0 +1,88 @@
+package teststore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// AttachmentRepository ...
+type AttachmentRepository struct {
+	store       *Store
+	attachments map[int]*model.Attachment
+	lastID      int
+}
+
+// Create ...
+func (r *AttachmentRepository) Create(a *model.Attachment) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+
+	r.lastID++
+	a.ID = r.lastID
+	a.CreatedAt = time.Now()
+	r.attachments[a.ID] = a
+	return nil
+}
+
+// Delete ...
+func (r *AttachmentRepository) Delete(id int) error {
+	if _, ok := r.attachments[id]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.attachments, id)
+	return nil
+}
+
+// Find ...
+func (r *AttachmentRepository) Find(id int) (*model.Attachment, error) {
+	a, ok := r.attachments[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	return a, nil
+}
+
+// FindByNote ...
+func (r *AttachmentRepository) FindByNote(noteID int) ([]*model.Attachment, error) {
+	return r.filter(func(a *model.Attachment) bool {
+		return a.NoteID == noteID
+	}), nil
+}
+
+// FindTrashedBefore ...
+func (r *AttachmentRepository) FindTrashedBefore(before time.Time) ([]*model.Attachment, error) {
+	r.store.Notes()
+	notes := r.store.noteRepository.notes
+	return r.filter(func(a *model.Attachment) bool {
+		n, ok := notes[a.NoteID]
+		return ok && n.DeletedAt != nil && n.DeletedAt.Before(before)
+	}), nil
+}
+
+func (r *AttachmentRepository) filter(match func(*model.Attachment) bool) []*model.Attachment {
+	result := []*model.Attachment{}
+	for _, a := range r.attachments {
+		if match(a) {
+			result = append(result, a)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+func (r *AttachmentRepository) deleteByNote(noteID int) {
+	for id, a := range r.attachments {
+		if a.NoteID == noteID {
+			delete(r.attachments, id)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package teststore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// ChecklistRepository ...
+type ChecklistRepository struct {
+	store  *Store
+	items  map[int]*model.ChecklistItem
+	lastID int
+}
+
+// Create ...
+func (r *ChecklistRepository) Create(i *model.ChecklistItem) error {
+	if err := i.Validate(); err != nil {
+		return err
+	}
+	if _, err := r.store.Notes().FindByID(i.NoteID); err != nil {
+		return err
+	}
+
+	r.lastID++
+	i.ID = r.lastID
+	i.Position = len(r.byNote(i.NoteID)) + 1
+	i.CreatedAt = time.Now()
+	i.UpdatedAt = i.CreatedAt
+	c := *i
+	r.items[i.ID] = &c
+	r.updateCompletion(i.NoteID)
+	return nil
+}
+
+// Update ...
+func (r *ChecklistRepository) Update(i *model.ChecklistItem) error {
+	if err := i.Validate(); err != nil {
+		return err
+	}
+	ri, ok := r.items[i.ID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	ri.Text = i.Text
+	ri.Done = i.Done
+	ri.UpdatedAt = time.Now()
+	i.UpdatedAt = ri.UpdatedAt
+	r.updateCompletion(ri.NoteID)
+	return nil
+}
+
+// Move ...
+func (r *ChecklistRepository) Move(id int, position int) error {
+	ri, ok := r.items[id]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	items := r.byNote(ri.NoteID)
+	if position < 1 {
+		position = 1
+	}
+	if position > len(items) {
+		position = len(items)
+	}
+
+	items = append(items[:ri.Position-1], items[ri.Position:]...)
+	items = append(items[:position-1], append([]*model.ChecklistItem{ri}, items[position-1:]...)...)
+	for n, i := range items {
+		i.Position = n + 1
+	}
+	ri.UpdatedAt = time.Now()
+	return nil
+}
+
+// Delete ...
+func (r *ChecklistRepository) Delete(id int) error {
+	ri, ok := r.items[id]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.items, id)
+	for _, i := range r.byNote(ri.NoteID) {
+		if i.Position > ri.Position {
+			i.Position--
+		}
+	}
+	r.updateCompletion(ri.NoteID)
+	return nil
+}
+
+// Find ...
+func (r *ChecklistRepository) Find(id int) (*model.ChecklistItem, error) {
+	i, ok := r.items[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	c := *i
+	return &c, nil
+}
+
+// FindByNote ...
+func (r *ChecklistRepository) FindByNote(noteID int) ([]*model.ChecklistItem, error) {
+	result := []*model.ChecklistItem{}
+	for _, i := range r.byNote(noteID) {
+		c := *i
+		result = append(result, &c)
+	}
+	return result, nil
+}
+
+// byNote returns stored items of the note ordered by position
+func (r *ChecklistRepository) byNote(noteID int) []*model.ChecklistItem {
+	result := []*model.ChecklistItem{}
+	for _, i := range r.items {
+		if i.NoteID == noteID {
+			result = append(result, i)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Position < result[j].Position
+	})
+	return result
+}
+
+// updateCompletion sets completion of the stored note
+// as sqlstore computes it when notes are read
+func (r *ChecklistRepository) updateCompletion(noteID int) {
+	r.store.Notes()
+	if n, ok := r.store.noteRepository.notes[noteID]; ok {
+		n.Completion = model.Completion(r.byNote(noteID))
+	}
+}
+
+func (r *ChecklistRepository) deleteByNote(noteID int) {
+	for id, i := range r.items {
+		if i.NoteID == noteID {
+			delete(r.items, id)
+		}
+	}
+}
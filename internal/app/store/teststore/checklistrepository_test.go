@@ -0,0 +1,133 @@
+package teststore_test
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecklistRepository_Create(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+
+	for p := 1; p <= 2; p++ {
+		i := model.TestChecklistItem(t)
+		i.NoteID = n.ID
+		assert.NoError(t, s.Checklist().Create(i))
+		assert.NotZero(t, i.ID)
+		assert.Equal(t, p, i.Position)
+	}
+
+	assert.Error(t, s.Checklist().Create(&model.ChecklistItem{NoteID: n.ID}))
+	s.Notes().Trash(n.ID)
+	i := model.TestChecklistItem(t)
+	i.NoteID = n.ID
+	assert.EqualError(t, s.Checklist().Create(i), store.ErrRecordNotFound.Error())
+}
+
+func TestChecklistRepository_Update(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+
+	rn, _ := s.Notes().FindByID(n.ID)
+	assert.Nil(t, rn.Completion)
+
+	items := []*model.ChecklistItem{}
+	for p := 0; p < 4; p++ {
+		i := model.TestChecklistItem(t)
+		i.NoteID = n.ID
+		s.Checklist().Create(i)
+		items = append(items, i)
+	}
+
+	items[0].Done = true
+	assert.NoError(t, s.Checklist().Update(items[0]))
+	items[0].Text = ""
+	assert.Error(t, s.Checklist().Update(items[0]))
+
+	rn, err := s.Notes().FindByID(n.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, rn.Completion) {
+		assert.Equal(t, 25, *rn.Completion)
+	}
+
+	ri, err := s.Checklist().Find(items[0].ID)
+	assert.NoError(t, err)
+	assert.True(t, ri.Done)
+	assert.Equal(t, "buy milk", ri.Text)
+}
+
+func TestChecklistRepository_Move(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+
+	ids := []int{}
+	for _, text := range []string{"a", "b", "c", "d"} {
+		i := &model.ChecklistItem{NoteID: n.ID, Text: text}
+		s.Checklist().Create(i)
+		ids = append(ids, i.ID)
+	}
+
+	texts := func() string {
+		il, err := s.Checklist().FindByNote(n.ID)
+		assert.NoError(t, err)
+		result := ""
+		for p, i := range il {
+			assert.Equal(t, p+1, i.Position)
+			result += i.Text
+		}
+		return result
+	}
+
+	assert.NoError(t, s.Checklist().Move(ids[3], 2))
+	assert.Equal(t, "adbc", texts())
+	assert.NoError(t, s.Checklist().Move(ids[0], 3))
+	assert.Equal(t, "dbac", texts())
+	assert.NoError(t, s.Checklist().Move(ids[2], 100))
+	assert.Equal(t, "dbac", texts())
+	assert.NoError(t, s.Checklist().Move(ids[2], 0))
+	assert.Equal(t, "cdba", texts())
+	assert.EqualError(t, s.Checklist().Move(ids[3]+1, 1), store.ErrRecordNotFound.Error())
+}
+
+func TestChecklistRepository_Delete(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+
+	items := []*model.ChecklistItem{}
+	for p := 0; p < 3; p++ {
+		i := model.TestChecklistItem(t)
+		i.NoteID = n.ID
+		s.Checklist().Create(i)
+		items = append(items, i)
+	}
+
+	assert.NoError(t, s.Checklist().Delete(items[0].ID))
+	assert.EqualError(t, s.Checklist().Delete(items[0].ID), store.ErrRecordNotFound.Error())
+	il, err := s.Checklist().FindByNote(n.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, il, 2) {
+		assert.Equal(t, 1, il[0].Position)
+		assert.Equal(t, 2, il[1].Position)
+	}
+
+	// items are deleted with the note
+	s.Notes().Delete(n.ID)
+	_, err = s.Checklist().Find(items[1].ID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
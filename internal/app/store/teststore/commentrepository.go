@@ -0,0 +1,104 @@
+package teststore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// CommentRepository ...
+type CommentRepository struct {
+	store    *Store
+	comments map[int]*model.Comment
+	lastID   int
+}
+
+// Create ...
+func (r *CommentRepository) Create(c *model.Comment) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	r.lastID++
+	c.ID = r.lastID
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = c.CreatedAt
+	cc := *c
+	r.comments[c.ID] = &cc
+	return nil
+}
+
+// Update ...
+func (r *CommentRepository) Update(c *model.Comment) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	rc, ok := r.comments[c.ID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	rc.Body = c.Body
+	rc.UpdatedAt = time.Now()
+	c.UpdatedAt = rc.UpdatedAt
+	return nil
+}
+
+// Delete ...
+func (r *CommentRepository) Delete(id int) error {
+	if _, ok := r.comments[id]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.comments, id)
+	for rid, c := range r.comments {
+		if c.ParentID != nil && *c.ParentID == id {
+			r.Delete(rid)
+		}
+	}
+	return nil
+}
+
+// Find ...
+func (r *CommentRepository) Find(id int) (*model.Comment, error) {
+	c, ok := r.comments[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	return r.withAuthor(c), nil
+}
+
+// FindByNote ...
+func (r *CommentRepository) FindByNote(noteID int) ([]*model.Comment, error) {
+	result := []*model.Comment{}
+	for _, c := range r.comments {
+		if c.NoteID == noteID {
+			result = append(result, r.withAuthor(c))
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// withAuthor returns a copy of the comment with the email of its author
+func (r *CommentRepository) withAuthor(c *model.Comment) *model.Comment {
+	cc := *c
+	if u, err := r.store.User().Find(c.AuthorID); err == nil {
+		cc.AuthorEmail = u.Email
+	}
+	return &cc
+}
+
+func (r *CommentRepository) deleteByNote(noteID int) {
+	for id, c := range r.comments {
+		if c.NoteID == noteID {
+			delete(r.comments, id)
+		}
+	}
+}
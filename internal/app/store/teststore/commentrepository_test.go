@@ -0,0 +1,91 @@
+package teststore_test
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentRepository_Create(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+
+	c := model.TestComment(t)
+	c.NoteID = n.ID
+	c.AuthorID = u.ID
+	assert.NoError(t, s.Comments().Create(c))
+	assert.NotZero(t, c.ID)
+
+	rc, err := s.Comments().Find(c.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, c.Body, rc.Body)
+	assert.Equal(t, u.Email, rc.AuthorEmail)
+
+	assert.Error(t, s.Comments().Create(&model.Comment{NoteID: n.ID, AuthorID: u.ID}))
+}
+
+func TestCommentRepository_Update(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+	c := model.TestComment(t)
+	c.NoteID = n.ID
+	c.AuthorID = u.ID
+	s.Comments().Create(c)
+
+	c.Body = "changed"
+	assert.NoError(t, s.Comments().Update(c))
+	c.Body = ""
+	assert.Error(t, s.Comments().Update(c))
+	c.Body = "changed"
+	c.ID++
+	assert.EqualError(t, s.Comments().Update(c), store.ErrRecordNotFound.Error())
+
+	rc, err := s.Comments().Find(c.ID - 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "changed", rc.Body)
+}
+
+func TestCommentRepository_Delete(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+
+	c := model.TestComment(t)
+	c.NoteID = n.ID
+	c.AuthorID = u.ID
+	s.Comments().Create(c)
+	reply := model.TestComment(t)
+	reply.NoteID = n.ID
+	reply.AuthorID = u.ID
+	reply.ParentID = &c.ID
+	s.Comments().Create(reply)
+	other := model.TestComment(t)
+	other.NoteID = n.ID
+	other.AuthorID = u.ID
+	s.Comments().Create(other)
+
+	// replies are deleted with the comment
+	assert.NoError(t, s.Comments().Delete(c.ID))
+	assert.EqualError(t, s.Comments().Delete(c.ID), store.ErrRecordNotFound.Error())
+	cl, err := s.Comments().FindByNote(n.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, cl, 1) {
+		assert.Equal(t, other.ID, cl[0].ID)
+	}
+
+	// comments are deleted with the note
+	s.Notes().Delete(n.ID)
+	_, err = s.Comments().Find(other.ID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
@@ -0,0 +1,67 @@
+package teststore
+
+import (
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// EmailVerificationRepository ...
+type EmailVerificationRepository struct {
+	store         *Store
+	verifications map[int]*model.EmailVerification
+	lastID        int
+}
+
+// Create ...
+func (r *EmailVerificationRepository) Create(v *model.EmailVerification) error {
+	if err := v.BeforeCreate(); err != nil {
+		return err
+	}
+
+	r.lastID++
+	v.ID = r.lastID
+	v.CreatedAt = time.Now()
+	c := *v
+	c.Token = ""
+	r.verifications[v.ID] = &c
+	return nil
+}
+
+// FindByToken ...
+func (r *EmailVerificationRepository) FindByToken(hash string) (*model.EmailVerification, error) {
+	now := time.Now()
+	for _, v := range r.verifications {
+		if v.TokenHash == hash && v.ExpiresAt.After(now) {
+			c := *v
+			return &c, nil
+		}
+	}
+	return nil, store.ErrRecordNotFound
+}
+
+// FindLatest ...
+func (r *EmailVerificationRepository) FindLatest(userID int) (*model.EmailVerification, error) {
+	var latest *model.EmailVerification
+	for _, v := range r.verifications {
+		if v.UserID == userID && (latest == nil || v.ID > latest.ID) {
+			latest = v
+		}
+	}
+	if latest == nil {
+		return nil, store.ErrRecordNotFound
+	}
+	c := *latest
+	return &c, nil
+}
+
+// DeleteByUser ...
+func (r *EmailVerificationRepository) DeleteByUser(userID int) error {
+	for id, v := range r.verifications {
+		if v.UserID == userID {
+			delete(r.verifications, id)
+		}
+	}
+	return nil
+}
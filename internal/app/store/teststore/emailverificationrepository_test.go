@@ -0,0 +1,53 @@
+package teststore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailVerificationRepository_Create(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	v := &model.EmailVerification{UserID: u.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	assert.NoError(t, s.EmailVerifications().Create(v))
+	assert.NotZero(t, v.ID)
+	assert.NotEmpty(t, v.Token)
+
+	fv, err := s.EmailVerifications().FindByToken(model.HashToken(v.Token))
+	assert.NoError(t, err)
+	assert.Equal(t, u.ID, fv.UserID)
+
+	expired := &model.EmailVerification{UserID: u.ID, ExpiresAt: time.Now().Add(-time.Minute)}
+	s.EmailVerifications().Create(expired)
+	_, err = s.EmailVerifications().FindByToken(model.HashToken(expired.Token))
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestEmailVerificationRepository_FindLatest(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	_, err := s.EmailVerifications().FindLatest(u.ID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	var last *model.EmailVerification
+	for i := 0; i < 2; i++ {
+		last = &model.EmailVerification{UserID: u.ID, ExpiresAt: time.Now().Add(time.Hour)}
+		s.EmailVerifications().Create(last)
+	}
+	v, err := s.EmailVerifications().FindLatest(u.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, last.ID, v.ID)
+
+	assert.NoError(t, s.EmailVerifications().DeleteByUser(u.ID))
+	_, err = s.EmailVerifications().FindLatest(u.ID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
@@ -0,0 +1,129 @@
+package teststore
+
+import (
+	"sync"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// ImportJobRepository is locked unlike other repositories,
+// jobs are updated in background while they're polled
+type ImportJobRepository struct {
+	store *Store
+	mu    sync.Mutex
+	jobs  map[string]*model.ImportJob
+}
+
+// Create ...
+func (r *ImportJobRepository) Create(j *model.ImportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	j.CreatedAt = time.Now()
+	j.HeartbeatAt = j.CreatedAt
+	j.Files = []*model.ImportFileResult{}
+	c := *j
+	r.jobs[j.ID] = &c
+	return nil
+}
+
+// Find returns the job of the user with its files
+func (r *ImportJobRepository) Find(id string, userID int) (*model.ImportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	j, ok := r.jobs[id]
+	if !ok || j.UserID != userID {
+		return nil, store.ErrRecordNotFound
+	}
+	c := *j
+	c.Files = []*model.ImportFileResult{}
+	for _, f := range j.Files {
+		fc := *f
+		c.Files = append(c.Files, &fc)
+	}
+	return &c, nil
+}
+
+// AddFile ...
+func (r *ImportJobRepository) AddFile(id string, f *model.ImportFileResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	j, ok := r.jobs[id]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	c := *f
+	j.Files = append(j.Files, &c)
+	if f.Status == model.ImportFileImported {
+		j.Imported++
+	} else {
+		j.Failed++
+	}
+	j.HeartbeatAt = time.Now()
+	return nil
+}
+
+// Touch ...
+func (r *ImportJobRepository) Touch(id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	j, ok := r.jobs[id]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	j.HeartbeatAt = at
+	return nil
+}
+
+// Finish ...
+func (r *ImportJobRepository) Finish(j *model.ImportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rj, ok := r.jobs[j.ID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	rj.Status = j.Status
+	rj.Error = j.Error
+	rj.FinishedAt = j.FinishedAt
+	return nil
+}
+
+// FailStale ...
+func (r *ImportJobRepository) FailStale(t time.Time, reason string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	count := 0
+	for _, j := range r.jobs {
+		if j.Status == model.ImportJobRunning && j.HeartbeatAt.Before(t) {
+			j.Status = model.ImportJobFailed
+			j.Error = reason
+			j.FinishedAt = &now
+			count++
+		}
+	}
+	return count, nil
+}
+
+// DeleteFinishedBefore ...
+func (r *ImportJobRepository) DeleteFinishedBefore(t time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, j := range r.jobs {
+		if j.FinishedAt != nil && j.FinishedAt.Before(t) {
+			delete(r.jobs, id)
+			count++
+		}
+	}
+	return count, nil
+}
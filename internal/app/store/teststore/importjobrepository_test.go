@@ -0,0 +1,121 @@
+package teststore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportJobRepository_Create(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	j := &model.ImportJob{ID: "job", UserID: u.ID, Format: "zip", Status: model.ImportJobRunning}
+	assert.NoError(t, s.ImportJobs().Create(j))
+	assert.False(t, j.CreatedAt.IsZero())
+
+	rj, err := s.ImportJobs().Find(j.ID, u.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ImportJobRunning, rj.Status)
+	assert.Equal(t, []*model.ImportFileResult{}, rj.Files)
+
+	_, err = s.ImportJobs().Find(j.ID, u.ID+1)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestImportJobRepository_AddFile(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+
+	j := &model.ImportJob{ID: "job", UserID: u.ID, Format: "zip", Status: model.ImportJobRunning}
+	s.ImportJobs().Create(j)
+
+	files := []*model.ImportFileResult{
+		{Name: "plan.md", Status: model.ImportFileImported, NoteID: n.ID},
+		{Name: "broken.md", Status: model.ImportFileFailed, Error: "broken"},
+	}
+	for _, f := range files {
+		assert.NoError(t, s.ImportJobs().AddFile(j.ID, f))
+	}
+	assert.EqualError(t, s.ImportJobs().AddFile("unknown", files[0]), store.ErrRecordNotFound.Error())
+
+	rj, err := s.ImportJobs().Find(j.ID, u.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, rj.Imported)
+	assert.Equal(t, 1, rj.Failed)
+	assert.Equal(t, files, rj.Files)
+}
+
+func TestImportJobRepository_Finish(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	finished := &model.ImportJob{ID: "finished", UserID: u.ID, Format: "zip", Status: model.ImportJobRunning}
+	s.ImportJobs().Create(finished)
+	running := &model.ImportJob{ID: "running", UserID: u.ID, Format: "json", Status: model.ImportJobRunning}
+	s.ImportJobs().Create(running)
+
+	now := time.Now()
+	assert.NoError(t, s.ImportJobs().Finish(&model.ImportJob{
+		ID:         finished.ID,
+		Status:     model.ImportJobFailed,
+		Error:      "broken archive",
+		FinishedAt: &now,
+	}))
+	rj, err := s.ImportJobs().Find(finished.ID, u.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ImportJobFailed, rj.Status)
+	assert.Equal(t, "broken archive", rj.Error)
+	assert.NotNil(t, rj.FinishedAt)
+
+	count, err := s.ImportJobs().DeleteFinishedBefore(now.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	_, err = s.ImportJobs().Find(finished.ID, u.ID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+	_, err = s.ImportJobs().Find(running.ID, u.ID)
+	assert.NoError(t, err)
+}
+
+func TestImportJobRepository_FailStale(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	stale := &model.ImportJob{ID: "stale", UserID: u.ID, Format: "zip", Status: model.ImportJobRunning}
+	s.ImportJobs().Create(stale)
+	alive := &model.ImportJob{ID: "alive", UserID: u.ID, Format: "json", Status: model.ImportJobRunning}
+	s.ImportJobs().Create(alive)
+
+	cutoff := time.Now().Add(time.Minute)
+	assert.NoError(t, s.ImportJobs().Touch(alive.ID, cutoff.Add(time.Minute)))
+	assert.EqualError(t, s.ImportJobs().Touch("unknown", cutoff), store.ErrRecordNotFound.Error())
+
+	count, err := s.ImportJobs().FailStale(cutoff, "interrupted")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	rj, err := s.ImportJobs().Find(stale.ID, u.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ImportJobFailed, rj.Status)
+	assert.Equal(t, "interrupted", rj.Error)
+	assert.NotNil(t, rj.FinishedAt)
+
+	rj, err = s.ImportJobs().Find(alive.ID, u.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ImportJobRunning, rj.Status)
+
+	// only the other job fails later, the failed one isn't counted again
+	count, err = s.ImportJobs().FailStale(cutoff.Add(time.Hour), "interrupted")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
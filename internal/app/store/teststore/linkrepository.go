@@ -0,0 +1,113 @@
+package teststore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// LinkRepository ...
+type LinkRepository struct {
+	store *Store
+	links map[string]*model.NoteLink
+}
+
+// Create ...
+func (r *LinkRepository) Create(l *model.NoteLink) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+
+	if err := l.BeforeCreate(); err != nil {
+		return err
+	}
+
+	l.CreatedAt = time.Now()
+	rl := *l
+	rl.Password = ""
+	r.links[l.Token] = &rl
+	return nil
+}
+
+// Delete ...
+func (r *LinkRepository) Delete(noteID int, token string) error {
+	l, ok := r.links[token]
+	if !ok || l.NoteID != noteID {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.links, token)
+	return nil
+}
+
+// Find ...
+func (r *LinkRepository) Find(token string) (*model.NoteLink, error) {
+	l, ok := r.links[token]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	rl := *l
+	return &rl, nil
+}
+
+// FindByNote ...
+func (r *LinkRepository) FindByNote(noteID int) ([]*model.NoteLink, error) {
+	result := []*model.NoteLink{}
+	for _, l := range r.links {
+		if l.NoteID == noteID {
+			rl := *l
+			result = append(result, &rl)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].Token < result[j].Token
+	})
+	return result, nil
+}
+
+// RecordView ...
+func (r *LinkRepository) RecordView(l *model.NoteLink) error {
+	rl, ok := r.links[l.Token]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	rl.Views++
+	rl.FailedAttempts = 0
+	l.Views = rl.Views
+	l.FailedAttempts = 0
+	return nil
+}
+
+// RecordFailedAttempt ...
+func (r *LinkRepository) RecordFailedAttempt(l *model.NoteLink, maxAttempts int, lockout time.Duration) error {
+	rl, ok := r.links[l.Token]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	rl.FailedAttempts++
+	if rl.FailedAttempts >= maxAttempts {
+		lockedUntil := time.Now().Add(lockout)
+		rl.FailedAttempts = 0
+		rl.LockedUntil = &lockedUntil
+	}
+	l.FailedAttempts = rl.FailedAttempts
+	l.LockedUntil = rl.LockedUntil
+	return nil
+}
+
+func (r *LinkRepository) deleteByNote(noteID int) {
+	for token, l := range r.links {
+		if l.NoteID == noteID {
+			delete(r.links, token)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package teststore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// NotebookRepository ...
+type NotebookRepository struct {
+	store     *Store
+	notebooks map[int]*model.Notebook
+	lastID    int
+}
+
+// Create ...
+func (r *NotebookRepository) Create(nb *model.Notebook) error {
+	if err := nb.Validate(); err != nil {
+		return err
+	}
+
+	r.lastID++
+	nb.ID = r.lastID
+	nb.CreatedAt = time.Now()
+	nb.UpdatedAt = nb.CreatedAt
+	r.notebooks[nb.ID] = nb
+	return nil
+}
+
+// Rename ...
+func (r *NotebookRepository) Rename(id int, name string) error {
+	if err := (&model.Notebook{Name: name}).Validate(); err != nil {
+		return err
+	}
+	nb, ok := r.notebooks[id]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	nb.Name = name
+	nb.UpdatedAt = time.Now()
+	return nil
+}
+
+// Move ...
+func (r *NotebookRepository) Move(id int, parentID *int) error {
+	nb, ok := r.notebooks[id]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	for p := parentID; p != nil; {
+		if *p == id {
+			return store.ErrNotebookCycle
+		}
+		parent, ok := r.notebooks[*p]
+		if !ok {
+			break
+		}
+		p = parent.ParentID
+	}
+
+	nb.ParentID = copyID(parentID)
+	nb.UpdatedAt = time.Now()
+	return nil
+}
+
+// Delete ...
+func (r *NotebookRepository) Delete(id int, mode string) error {
+	if mode != store.NotebookDeleteCascade && mode != store.NotebookDeleteMoveToParent {
+		return store.ErrInvalidDeleteMode
+	}
+	nb, ok := r.notebooks[id]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	r.store.Notes()
+	notes := r.store.noteRepository.notes
+	if mode == store.NotebookDeleteCascade {
+		ids := r.subtree(id)
+		now := time.Now()
+		for _, n := range notes {
+			if n.NotebookID == nil || !ids[*n.NotebookID] {
+				continue
+			}
+			if n.DeletedAt == nil {
+				n.DeletedAt = &now
+			}
+			n.NotebookID = nil
+		}
+		for nid := range ids {
+			delete(r.notebooks, nid)
+		}
+		return nil
+	}
+
+	for _, child := range r.notebooks {
+		if child.ParentID != nil && *child.ParentID == id {
+			child.ParentID = copyID(nb.ParentID)
+		}
+	}
+	for _, n := range notes {
+		if n.NotebookID != nil && *n.NotebookID == id {
+			n.NotebookID = copyID(nb.ParentID)
+		}
+	}
+	delete(r.notebooks, id)
+	return nil
+}
+
+// Find ...
+func (r *NotebookRepository) Find(id int) (*model.Notebook, error) {
+	nb, ok := r.notebooks[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	return nb, nil
+}
+
+// FindByUser ...
+func (r *NotebookRepository) FindByUser(u *model.User) ([]*model.Notebook, error) {
+	result := []*model.Notebook{}
+	for _, nb := range r.notebooks {
+		if nb.UserID == u.ID {
+			result = append(result, nb)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// subtree returns ids of the notebook and all its descendants
+func (r *NotebookRepository) subtree(id int) map[int]bool {
+	ids := map[int]bool{id: true}
+	for added := true; added; {
+		added = false
+		for _, nb := range r.notebooks {
+			if nb.ParentID != nil && ids[*nb.ParentID] && !ids[nb.ID] {
+				ids[nb.ID] = true
+				added = true
+			}
+		}
+	}
+	return ids
+}
+
+func copyID(id *int) *int {
+	if id == nil {
+		return nil
+	}
+	v := *id
+	return &v
+}
@@ -0,0 +1,162 @@
+package teststore_test
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotebookRepository_Create(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	nb := model.TestNotebook(t)
+	nb.UserID = u.ID
+	assert.NoError(t, s.Notebooks().Create(nb))
+	assert.NotZero(t, nb.ID)
+
+	child := model.TestNotebook(t)
+	child.UserID = u.ID
+	child.ParentID = &nb.ID
+	assert.NoError(t, s.Notebooks().Create(child))
+
+	rnb, err := s.Notebooks().Find(child.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, nb.ID, *rnb.ParentID)
+
+	assert.Error(t, s.Notebooks().Create(&model.Notebook{UserID: u.ID}))
+}
+
+func TestNotebookRepository_Rename(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	nb := model.TestNotebook(t)
+	nb.UserID = u.ID
+	s.Notebooks().Create(nb)
+
+	assert.NoError(t, s.Notebooks().Rename(nb.ID, "projects"))
+	assert.Error(t, s.Notebooks().Rename(nb.ID, ""))
+	assert.EqualError(t, s.Notebooks().Rename(nb.ID+1, "projects"), store.ErrRecordNotFound.Error())
+
+	rnb, err := s.Notebooks().Find(nb.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "projects", rnb.Name)
+}
+
+func TestNotebookRepository_Move(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	var ids []int
+	for i := 0; i < 3; i++ {
+		nb := model.TestNotebook(t)
+		nb.UserID = u.ID
+		if i > 0 {
+			nb.ParentID = &ids[i-1]
+		}
+		s.Notebooks().Create(nb)
+		ids = append(ids, nb.ID)
+	}
+
+	assert.EqualError(t, s.Notebooks().Move(ids[0], &ids[0]), store.ErrNotebookCycle.Error())
+	assert.EqualError(t, s.Notebooks().Move(ids[0], &ids[2]), store.ErrNotebookCycle.Error())
+	assert.NoError(t, s.Notebooks().Move(ids[2], &ids[0]))
+	assert.NoError(t, s.Notebooks().Move(ids[1], nil))
+
+	rnb, err := s.Notebooks().Find(ids[1])
+	assert.NoError(t, err)
+	assert.Nil(t, rnb.ParentID)
+}
+
+func TestNotebookRepository_Delete(t *testing.T) {
+	testCases := []struct {
+		name            string
+		mode            string
+		expectedTrashed bool
+	}{
+		{
+			name:            "cascade",
+			mode:            store.NotebookDeleteCascade,
+			expectedTrashed: true,
+		},
+		{
+			name: "move to parent",
+			mode: store.NotebookDeleteMoveToParent,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := teststore.New()
+			u := model.TestUser(t)
+			s.User().Create(u)
+			root := model.TestNotebook(t)
+			root.UserID = u.ID
+			s.Notebooks().Create(root)
+			nb := model.TestNotebook(t)
+			nb.UserID = u.ID
+			nb.ParentID = &root.ID
+			s.Notebooks().Create(nb)
+			child := model.TestNotebook(t)
+			child.UserID = u.ID
+			child.ParentID = &nb.ID
+			s.Notebooks().Create(child)
+
+			n1 := model.TestNote(t)
+			n1.NotebookID = &nb.ID
+			s.Notes().Create(n1, u)
+			n2 := model.TestNote(t)
+			n2.NotebookID = &child.ID
+			s.Notes().Create(n2, u)
+
+			assert.NoError(t, s.Notebooks().Delete(nb.ID, tc.mode))
+			_, err := s.Notebooks().Find(nb.ID)
+			assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+			if tc.expectedTrashed {
+				_, err := s.Notebooks().Find(child.ID)
+				assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+				for _, n := range []*model.Note{n1, n2} {
+					_, err = s.Notes().FindTrashedByID(n.ID)
+					assert.NoError(t, err)
+				}
+				return
+			}
+
+			rc, err := s.Notebooks().Find(child.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, root.ID, *rc.ParentID)
+			rn, err := s.Notes().FindByID(n1.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, root.ID, *rn.NotebookID)
+		})
+	}
+
+	s := teststore.New()
+	assert.EqualError(t, s.Notebooks().Delete(1, "unknown"), store.ErrInvalidDeleteMode.Error())
+}
+
+func TestNotebookRepository_FindByUser(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	s.User().Create(u)
+	s.User().Create(other)
+
+	for _, owner := range []*model.User{u, u, other} {
+		nb := model.TestNotebook(t)
+		nb.UserID = owner.ID
+		s.Notebooks().Create(nb)
+	}
+
+	nbl, err := s.Notebooks().FindByUser(u)
+	assert.NoError(t, err)
+	assert.Len(t, nbl, 2)
+}
@@ -1,6 +1,8 @@
 package teststore
 
 import (
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/KapitanD/http-api-server/internal/app/model"
@@ -9,8 +11,10 @@ import (
 
 // NoteRepository ...
 type NoteRepository struct {
-	store *Store
-	notes map[int]*model.Note
+	store  *Store
+	notes  map[int]*model.Note
+	index  *searchIndex
+	lastID int
 }
 
 // Create ...
@@ -21,8 +25,15 @@ func (r *NoteRepository) Create(n *model.Note, u *model.User) error {
 
 	n.AuthorID = u.ID
 
-	n.ID = len(r.notes) + 1
+	r.lastID++
+	n.ID = r.lastID
+	n.Version = 1
 	r.notes[n.ID] = n
+	r.index.add(n)
+	r.store.Revisions()
+	r.store.revisionRepository.add(n)
+	r.store.References()
+	r.store.referenceRepository.save(n)
 
 	return nil
 }
@@ -33,42 +44,396 @@ func (r *NoteRepository) Update(id int, un *model.Note) error {
 		return err
 	}
 	n, ok := r.notes[id]
-	if !ok {
+	if !ok || n.DeletedAt != nil {
 		return store.ErrRecordNotFound
 	}
-	if un.Body != "" {
+	if un.Version != 0 && un.Version != n.Version {
+		return store.ErrVersionConflict
+	}
+	changed := false
+	if un.Body != "" && un.Body != n.Body {
 		n.Body = un.Body
+		changed = true
 	}
-	if un.Header != "" {
+	if un.Header != "" && un.Header != n.Header {
 		n.Header = un.Header
+		changed = true
 	}
 	n.UpdatedAt = time.Now()
+	n.Version++
+	r.index.add(n)
+	if changed {
+		r.store.Revisions()
+		r.store.revisionRepository.add(n)
+		r.store.References()
+		r.store.referenceRepository.save(n)
+	}
 	return nil
 }
 
 // Delete ...
 func (r *NoteRepository) Delete(id int) error {
 	delete(r.notes, id)
+	r.index.remove(id)
+	r.store.Revisions()
+	delete(r.store.revisionRepository.revisions, id)
+	r.store.Shares()
+	delete(r.store.shareRepository.shares, id)
+	r.store.Links()
+	r.store.linkRepository.deleteByNote(id)
+	r.store.Attachments()
+	r.store.attachmentRepository.deleteByNote(id)
+	r.store.References()
+	r.store.referenceRepository.deleteByNote(id)
+	r.store.Reminders()
+	r.store.reminderRepository.deleteByNote(id)
+	r.store.Notifications()
+	r.store.notificationRepository.deleteByNote(id)
+	r.store.Checklist()
+	r.store.checklistRepository.deleteByNote(id)
+	r.store.Comments()
+	r.store.commentRepository.deleteByNote(id)
+	return nil
+}
+
+// Trash ...
+func (r *NoteRepository) Trash(id int) error {
+	n, ok := r.notes[id]
+	if !ok || n.DeletedAt != nil {
+		return store.ErrRecordNotFound
+	}
+
+	now := time.Now()
+	n.DeletedAt = &now
+	return nil
+}
+
+// Restore ...
+func (r *NoteRepository) Restore(id int) error {
+	n, ok := r.notes[id]
+	if !ok || n.DeletedAt == nil {
+		return store.ErrRecordNotFound
+	}
+
+	n.DeletedAt = nil
+	return nil
+}
+
+// Move ...
+func (r *NoteRepository) Move(id int, notebookID *int) error {
+	n, ok := r.notes[id]
+	if !ok || n.DeletedAt != nil {
+		return store.ErrRecordNotFound
+	}
+
+	n.NotebookID = copyID(notebookID)
 	return nil
 }
 
-// FindByUser ...
-func (r *NoteRepository) FindByUser(u *model.User) ([]*model.Note, error) {
+// SetFlag ...
+func (r *NoteRepository) SetFlag(id int, flag string, value bool) error {
+	n, ok := r.notes[id]
+	if !ok || n.DeletedAt != nil {
+		return store.ErrRecordNotFound
+	}
+
+	switch flag {
+	case store.FlagPinned:
+		n.Pinned = value
+	case store.FlagArchived:
+		n.Archived = value
+	case store.FlagStarred:
+		n.Starred = value
+	default:
+		return store.ErrUnknownFlag
+	}
+	return nil
+}
+
+// PurgeTrash ...
+func (r *NoteRepository) PurgeTrash(before time.Time) (int, error) {
+	count := 0
+	for id, n := range r.notes {
+		if n.DeletedAt != nil && n.DeletedAt.Before(before) {
+			r.Delete(id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Bulk ...
+func (r *NoteRepository) Bulk(items []*store.BulkItem, atomic bool) error {
+	trashed := make(map[int]bool)
+	for _, it := range items {
+		it.Err = r.checkBulkItem(it, trashed)
+		if it.Err != nil && atomic {
+			return store.ErrBulkFailed
+		}
+	}
+
+	for _, it := range items {
+		if it.Err == nil {
+			it.Err = r.applyBulkItem(it)
+		}
+	}
+	return nil
+}
+
+// checkBulkItem reports errors the item would fail with, notes trashed
+// by previous items are tracked so failures are found before any change
+func (r *NoteRepository) checkBulkItem(it *store.BulkItem, trashed map[int]bool) error {
+	if err := it.Validate(); err != nil {
+		return err
+	}
+	n, ok := r.notes[it.NoteID]
+	if !ok || n.DeletedAt != nil || trashed[it.NoteID] {
+		return store.ErrRecordNotFound
+	}
+
+	role := model.RoleOwner
+	if n.AuthorID != it.UserID {
+		sh, err := r.store.Shares().Find(it.NoteID, it.UserID)
+		if err != nil {
+			return err
+		}
+		role = sh.Role
+	}
+	if !model.RoleAllows(role, it.Role) {
+		return store.ErrForbidden
+	}
+	if it.Op == store.BulkDelete {
+		trashed[it.NoteID] = true
+	}
+	return nil
+}
+
+func (r *NoteRepository) applyBulkItem(it *store.BulkItem) error {
+	switch it.Op {
+	case store.BulkDelete:
+		return r.Trash(it.NoteID)
+	case store.BulkUpdate:
+		return r.Update(it.NoteID, &model.Note{
+			Header:    it.Header,
+			Body:      it.Body,
+			UpdatedAt: time.Now(),
+		})
+	case store.BulkTag:
+		n := r.notes[it.NoteID]
+		return r.store.Tags().SetNoteTags(n, it.MergeTags(n.Tags))
+	default:
+		return r.Move(it.NoteID, it.NotebookID)
+	}
+}
+
+// EachByUser ...
+func (r *NoteRepository) EachByUser(u *model.User, fn func(*model.Note) error) error {
 	result := []*model.Note{}
 	for _, n := range r.notes {
-		if n.AuthorID == u.ID {
+		if n.AuthorID == u.ID && n.DeletedAt == nil {
 			result = append(result, n)
 		}
 	}
-	return result, nil
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	for _, n := range result {
+		if err := fn(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindPage ...
+func (r *NoteRepository) FindPage(u *model.User, q *store.NoteQuery) ([]*model.Note, string, error) {
+	if err := q.Validate(); err != nil {
+		return nil, "", err
+	}
+	c, err := q.DecodeCursor()
+	if err != nil {
+		return nil, "", err
+	}
+
+	result := []*model.Note{}
+	for _, n := range r.notes {
+		if n.AuthorID != u.ID || n.DeletedAt != nil || !matchTags(n, q.Tags, q.MatchAllTags) {
+			continue
+		}
+		if q.Archived == store.ArchivedExclude && n.Archived || q.Archived == store.ArchivedOnly && !n.Archived {
+			continue
+		}
+		if q.StarredOnly && !n.Starred {
+			continue
+		}
+		if q.NotebookID != 0 && (n.NotebookID == nil || *n.NotebookID != q.NotebookID) {
+			continue
+		}
+		if !q.CreatedAfter.IsZero() && !n.CreatedAt.After(q.CreatedAfter) {
+			continue
+		}
+		if !q.UpdatedBefore.IsZero() && !n.UpdatedAt.Before(q.UpdatedBefore) {
+			continue
+		}
+		if c != nil && !afterCursor(n, q, c) {
+			continue
+		}
+		result = append(result, n)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return compareNotes(result[i], result[j], q) < 0
+	})
+
+	if len(result) > q.Limit {
+		result = result[:q.Limit]
+		return result, q.NextCursor(result[len(result)-1]), nil
+	}
+	return result, "", nil
 }
 
 // FindByID ...
 func (r *NoteRepository) FindByID(id int) (*model.Note, error) {
 	n, ok := r.notes[id]
-	if !ok {
+	if !ok || n.DeletedAt != nil {
 		return nil, store.ErrRecordNotFound
 	}
 
 	return n, nil
 }
+
+// FindTrash ...
+func (r *NoteRepository) FindTrash(u *model.User) ([]*model.Note, error) {
+	result := []*model.Note{}
+	for _, n := range r.notes {
+		if n.AuthorID == u.ID && n.DeletedAt != nil {
+			result = append(result, n)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].DeletedAt.Equal(*result[j].DeletedAt) {
+			return result[i].DeletedAt.After(*result[j].DeletedAt)
+		}
+		return result[i].ID > result[j].ID
+	})
+	return result, nil
+}
+
+// CountByUser ...
+func (r *NoteRepository) CountByUser(u *model.User) (int, error) {
+	count := 0
+	for _, n := range r.notes {
+		if n.AuthorID == u.ID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// FindTrashedByID ...
+func (r *NoteRepository) FindTrashedByID(id int) (*model.Note, error) {
+	n, ok := r.notes[id]
+	if !ok || n.DeletedAt == nil {
+		return nil, store.ErrRecordNotFound
+	}
+
+	return n, nil
+}
+
+// Search ...
+func (r *NoteRepository) Search(u *model.User, sq *store.SearchQuery) ([]*model.SearchResult, error) {
+	result := []*model.SearchResult{}
+	for id, rank := range r.index.search(sq) {
+		n := r.notes[id]
+		if n.AuthorID != u.ID || n.DeletedAt != nil {
+			continue
+		}
+		result = append(result, &model.SearchResult{
+			Note:          n,
+			Rank:          rank,
+			HeaderSnippet: sq.Highlight(n.Header),
+			BodySnippet:   snippet(n.Body, sq),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Rank != result[j].Rank {
+			return result[i].Rank > result[j].Rank
+		}
+		return result[i].Note.ID > result[j].Note.ID
+	})
+	if len(result) > sq.Limit {
+		result = result[:sq.Limit]
+	}
+	return result, nil
+}
+
+func matchTags(n *model.Note, tags []string, matchAll bool) bool {
+	if len(tags) == 0 {
+		return true
+	}
+
+	matched := 0
+	for _, tag := range tags {
+		for _, t := range n.Tags {
+			if t == tag {
+				matched++
+				break
+			}
+		}
+	}
+	if matchAll {
+		return matched == len(tags)
+	}
+	return matched > 0
+}
+
+func afterCursor(n *model.Note, q *store.NoteQuery, c *store.Cursor) bool {
+	cn := &model.Note{
+		ID:        c.ID,
+		Pinned:    c.Pinned,
+		Header:    c.Header,
+		CreatedAt: c.Time,
+		UpdatedAt: c.Time,
+	}
+	return compareNotes(n, cn, q) > 0
+}
+
+func compareNotes(a, b *model.Note, q *store.NoteQuery) int {
+	if q.PinnedFirst && a.Pinned != b.Pinned {
+		if a.Pinned {
+			return -1
+		}
+		return 1
+	}
+
+	var res int
+	switch q.Sort {
+	case store.SortCreatedAt:
+		res = compareTimes(a.CreatedAt, b.CreatedAt)
+	case store.SortUpdatedAt:
+		res = compareTimes(a.UpdatedAt, b.UpdatedAt)
+	case store.SortHeader:
+		res = strings.Compare(a.Header, b.Header)
+	}
+	if res == 0 {
+		res = a.ID - b.ID
+	}
+	if q.Desc {
+		return -res
+	}
+	return res
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	default:
+		return 0
+	}
+}
@@ -1,7 +1,10 @@
 package teststore_test
 
 import (
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/KapitanD/http-api-server/internal/app/model"
 	"github.com/KapitanD/http-api-server/internal/app/store"
@@ -46,24 +49,36 @@ func TestNoteRepository_Delete(t *testing.T) {
 	assert.EqualError(t, store.ErrRecordNotFound, err.Error())
 }
 
-func TestNoteRepository_FindByUser(t *testing.T) {
+func TestNoteRepository_EachByUser(t *testing.T) {
 	s := teststore.New()
 	u := model.TestUser(t)
 	n := model.TestNote(t)
 
 	s.User().Create(u)
-	rn, err := s.Notes().FindByUser(u)
-	assert.NoError(t, err)
+	rn := []*model.Note{}
+	collect := func(n *model.Note) error {
+		rn = append(rn, n)
+		return nil
+	}
+	assert.NoError(t, s.Notes().EachByUser(u, collect))
 	assert.Equal(t, []*model.Note{}, rn)
 
 	s.Notes().Create(n, u)
-	rn, err = s.Notes().FindByUser(u)
-	assert.NoError(t, err)
+	assert.NoError(t, s.Notes().EachByUser(u, collect))
 	assert.Equal(t, len(rn), 1)
 	// dont need to compare timestamp, other fields are content uniqness
 	n.CreatedAt = rn[0].CreatedAt
 	n.UpdatedAt = rn[0].UpdatedAt
 	assert.Equal(t, []*model.Note{n}, rn)
+
+	errStop := errors.New("stop")
+	s.Notes().Create(model.TestNote(t), u)
+	calls := 0
+	assert.EqualError(t, s.Notes().EachByUser(u, func(*model.Note) error {
+		calls++
+		return errStop
+	}), errStop.Error())
+	assert.Equal(t, 1, calls)
 }
 
 func TestNoteRepository_FindByID(t *testing.T) {
@@ -83,3 +98,424 @@ func TestNoteRepository_FindByID(t *testing.T) {
 	n.UpdatedAt = rn.UpdatedAt
 	assert.Equal(t, n, rn)
 }
+
+func TestNoteRepository_FindPage(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 5; i++ {
+		n := model.TestNote(t)
+		n.Header = fmt.Sprintf("header %d", 5-i)
+		n.CreatedAt = start.Add(time.Duration(i) * time.Minute)
+		n.UpdatedAt = n.CreatedAt
+		s.Notes().Create(n, u)
+	}
+
+	q := store.NewNoteQuery()
+	q.Limit = 2
+	ids := []int{}
+	for page := 0; page < 3; page++ {
+		nl, next, err := s.Notes().FindPage(u, q)
+		assert.NoError(t, err)
+		for _, n := range nl {
+			ids = append(ids, n.ID)
+		}
+		if page < 2 {
+			assert.NotEmpty(t, next)
+		} else {
+			assert.Empty(t, next)
+		}
+		q.Cursor = next
+	}
+	assert.Len(t, ids, 5)
+	for i := 1; i < len(ids); i++ {
+		assert.True(t, ids[i-1] > ids[i])
+	}
+
+	q = store.NewNoteQuery()
+	q.Sort = store.SortHeader
+	q.Desc = false
+	q.CreatedAfter = start.Add(90 * time.Second)
+	nl, _, err := s.Notes().FindPage(u, q)
+	assert.NoError(t, err)
+	assert.Len(t, nl, 3)
+	assert.Equal(t, "header 1", nl[0].Header)
+
+	q.Cursor = "invalid"
+	_, _, err = s.Notes().FindPage(u, q)
+	assert.EqualError(t, err, store.ErrInvalidCursor.Error())
+}
+
+func TestNoteRepository_FindPageByTags(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	n1 := model.TestNote(t)
+	n2 := model.TestNote(t)
+	s.Notes().Create(n1, u)
+	s.Notes().Create(n2, u)
+	s.Tags().SetNoteTags(n1, []string{"work", "urgent"})
+	s.Tags().SetNoteTags(n2, []string{"work"})
+
+	q := store.NewNoteQuery()
+	q.Tags = []string{"urgent", "home"}
+	rn, _, err := s.Notes().FindPage(u, q)
+	assert.NoError(t, err)
+	assert.Len(t, rn, 1)
+
+	q.Tags = []string{"work", "urgent"}
+	rn, _, err = s.Notes().FindPage(u, q)
+	assert.NoError(t, err)
+	assert.Len(t, rn, 2)
+
+	q.MatchAllTags = true
+	rn, _, err = s.Notes().FindPage(u, q)
+	assert.NoError(t, err)
+	assert.Len(t, rn, 1)
+	assert.Equal(t, n1.ID, rn[0].ID)
+}
+
+func TestNoteRepository_Search(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	n1 := model.TestNote(t)
+	n1.Header = "Weekly meeting"
+	n1.Body = "Discuss the project plan for next week"
+	n2 := model.TestNote(t)
+	n2.Header = "Project ideas"
+	n2.Body = "Draft of projects for the next quarter"
+	s.Notes().Create(n1, u)
+	s.Notes().Create(n2, u)
+
+	testCases := []struct {
+		name        string
+		query       string
+		expectedIDs []int
+	}{
+		{
+			name:        "word",
+			query:       "meeting",
+			expectedIDs: []int{n1.ID},
+		},
+		{
+			name:        "header ranks higher",
+			query:       "project",
+			expectedIDs: []int{n2.ID, n1.ID},
+		},
+		{
+			name:        "phrase",
+			query:       `"next week"`,
+			expectedIDs: []int{n1.ID},
+		},
+		{
+			name:        "prefix",
+			query:       "quart*",
+			expectedIDs: []int{n2.ID},
+		},
+		{
+			name:        "negation",
+			query:       "next -draft",
+			expectedIDs: []int{n1.ID},
+		},
+		{
+			name:        "not found",
+			query:       "holiday",
+			expectedIDs: []int{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sq, err := store.ParseSearchQuery(tc.query)
+			assert.NoError(t, err)
+			res, err := s.Notes().Search(u, sq)
+			assert.NoError(t, err)
+			ids := []int{}
+			for _, sr := range res {
+				ids = append(ids, sr.Note.ID)
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+		})
+	}
+
+	sq, _ := store.ParseSearchQuery("meeting")
+	res, _ := s.Notes().Search(u, sq)
+	assert.Equal(t, "Weekly <mark>meeting</mark>", res[0].HeaderSnippet)
+}
+
+func TestNoteRepository_SearchEscapesSnippets(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	n := model.TestNote(t)
+	n.Header = "<b>Weekly</b> meeting"
+	n.Body = `Agenda <img src=x onerror="alert(1)"> for the meeting`
+	s.Notes().Create(n, u)
+
+	sq, _ := store.ParseSearchQuery("meeting")
+	res, err := s.Notes().Search(u, sq)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, "&lt;b&gt;Weekly&lt;/b&gt; <mark>meeting</mark>", res[0].HeaderSnippet)
+	assert.NotContains(t, res[0].BodySnippet, "<img")
+	assert.Contains(t, res[0].BodySnippet, "<mark>meeting</mark>")
+}
+
+func TestNoteRepository_CountByUser(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	s.User().Create(other)
+
+	for _, user := range []*model.User{u, u, other} {
+		s.Notes().Create(model.TestNote(t), user)
+	}
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+	s.Notes().Trash(n.ID)
+
+	count, err := s.Notes().CountByUser(u)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestNoteRepository_TrashAndRestore(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.Notes().Create(n, u)
+
+	assert.EqualError(t, s.Notes().Restore(n.ID), store.ErrRecordNotFound.Error())
+	assert.NoError(t, s.Notes().Trash(n.ID))
+	assert.EqualError(t, s.Notes().Trash(n.ID), store.ErrRecordNotFound.Error())
+
+	_, err := s.Notes().FindByID(n.ID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+	assert.NoError(t, s.Notes().EachByUser(u, func(*model.Note) error {
+		t.Error("trashed note must be skipped")
+		return nil
+	}))
+
+	rn, err := s.Notes().FindTrash(u)
+	assert.NoError(t, err)
+	assert.Len(t, rn, 1)
+	assert.NotNil(t, rn[0].DeletedAt)
+	tn, err := s.Notes().FindTrashedByID(n.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, n.ID, tn.ID)
+
+	assert.NoError(t, s.Notes().Restore(n.ID))
+	rn, err = s.Notes().FindTrash(u)
+	assert.NoError(t, err)
+	assert.Len(t, rn, 0)
+	_, err = s.Notes().FindByID(n.ID)
+	assert.NoError(t, err)
+}
+
+func TestNoteRepository_PurgeTrash(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	n1 := model.TestNote(t)
+	n2 := model.TestNote(t)
+	s.Notes().Create(n1, u)
+	s.Notes().Create(n2, u)
+	s.Notes().Trash(n1.ID)
+
+	count, err := s.Notes().PurgeTrash(time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	count, err = s.Notes().PurgeTrash(time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	_, err = s.Notes().FindTrashedByID(n1.ID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+	_, err = s.Notes().FindByID(n2.ID)
+	assert.NoError(t, err)
+}
+
+func TestNoteRepository_UpdateVersion(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.Notes().Create(n, u)
+	assert.Equal(t, 1, n.Version)
+
+	assert.NoError(t, s.Notes().Update(n.ID, &model.Note{Body: "one", Version: 1}))
+	assert.EqualError(
+		t,
+		s.Notes().Update(n.ID, &model.Note{Body: "two", Version: 1}),
+		store.ErrVersionConflict.Error(),
+	)
+	assert.NoError(t, s.Notes().Update(n.ID, &model.Note{Body: "three"}))
+
+	rn, err := s.Notes().FindByID(n.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, rn.Version)
+	assert.Equal(t, "three", rn.Body)
+}
+
+func TestNoteRepository_Move(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	nb := model.TestNotebook(t)
+	nb.UserID = u.ID
+	s.Notebooks().Create(nb)
+	n := model.TestNote(t)
+	s.Notes().Create(n, u)
+
+	assert.NoError(t, s.Notes().Move(n.ID, &nb.ID))
+	q := store.NewNoteQuery()
+	q.NotebookID = nb.ID
+	nl, _, err := s.Notes().FindPage(u, q)
+	assert.NoError(t, err)
+	assert.Len(t, nl, 1)
+
+	assert.NoError(t, s.Notes().Move(n.ID, nil))
+	nl, _, err = s.Notes().FindPage(u, q)
+	assert.NoError(t, err)
+	assert.Len(t, nl, 0)
+
+	assert.EqualError(t, s.Notes().Move(n.ID+1, nil), store.ErrRecordNotFound.Error())
+}
+
+func TestNoteRepository_SetFlag(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	n := model.TestNote(t)
+	s.User().Create(u)
+	s.Notes().Create(n, u)
+
+	assert.NoError(t, s.Notes().SetFlag(n.ID, store.FlagStarred, true))
+	assert.EqualError(t, s.Notes().SetFlag(n.ID, "deleted", true), store.ErrUnknownFlag.Error())
+	assert.EqualError(t, s.Notes().SetFlag(n.ID+1, store.FlagStarred, true), store.ErrRecordNotFound.Error())
+
+	rn, err := s.Notes().FindByID(n.ID)
+	assert.NoError(t, err)
+	assert.True(t, rn.Starred)
+	assert.False(t, rn.Pinned)
+}
+
+func TestNoteRepository_FindPageFlags(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	notes := make([]*model.Note, 4)
+	for i := range notes {
+		notes[i] = model.TestNote(t)
+		notes[i].UpdatedAt = notes[i].UpdatedAt.Add(time.Duration(i) * time.Minute)
+		s.Notes().Create(notes[i], u)
+	}
+	s.Notes().SetFlag(notes[0].ID, store.FlagPinned, true)
+	s.Notes().SetFlag(notes[1].ID, store.FlagArchived, true)
+	s.Notes().SetFlag(notes[2].ID, store.FlagStarred, true)
+
+	ids := func(q *store.NoteQuery) []int {
+		result := []int{}
+		for {
+			nl, next, err := s.Notes().FindPage(u, q)
+			assert.NoError(t, err)
+			for _, n := range nl {
+				result = append(result, n.ID)
+			}
+			if next == "" {
+				return result
+			}
+			q.Cursor = next
+		}
+	}
+
+	q := store.NewNoteQuery()
+	q.Limit = 1
+	assert.Equal(t, []int{notes[0].ID, notes[3].ID, notes[2].ID}, ids(q))
+
+	q = store.NewNoteQuery()
+	q.Limit = 1
+	q.PinnedFirst = false
+	q.Archived = store.ArchivedInclude
+	assert.Equal(t, []int{notes[3].ID, notes[2].ID, notes[1].ID, notes[0].ID}, ids(q))
+
+	q = store.NewNoteQuery()
+	q.Archived = store.ArchivedOnly
+	assert.Equal(t, []int{notes[1].ID}, ids(q))
+
+	q = store.NewNoteQuery()
+	q.StarredOnly = true
+	assert.Equal(t, []int{notes[2].ID}, ids(q))
+}
+
+func TestNoteRepository_Bulk(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	n1 := model.TestNote(t)
+	s.Notes().Create(n1, u)
+	n2 := model.TestNote(t)
+	s.Notes().Create(n2, u)
+	s.Tags().SetNoteTags(n2, []string{"draft", "work"})
+
+	items := []*store.BulkItem{
+		{Op: store.BulkDelete, NoteID: n1.ID, UserID: u.ID, Role: model.RoleOwner},
+		{Op: store.BulkUpdate, NoteID: n2.ID, UserID: u.ID, Role: model.RoleEditor, Header: "bulk header"},
+		{Op: store.BulkTag, NoteID: n2.ID, UserID: u.ID, Role: model.RoleEditor, AddTags: []string{"urgent"}, RemoveTags: []string{"draft"}},
+	}
+	assert.NoError(t, s.Notes().Bulk(items, true))
+	for _, it := range items {
+		assert.NoError(t, it.Err)
+	}
+	_, err := s.Notes().FindByID(n1.ID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+	rn, err := s.Notes().FindByID(n2.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "bulk header", rn.Header)
+	assert.ElementsMatch(t, []string{"urgent", "work"}, rn.Tags)
+
+	items = []*store.BulkItem{
+		{Op: store.BulkUpdate, NoteID: n2.ID, UserID: u.ID, Role: model.RoleEditor, Header: "atomic header"},
+		{Op: store.BulkDelete, NoteID: n1.ID, UserID: u.ID, Role: model.RoleOwner},
+	}
+	assert.EqualError(t, s.Notes().Bulk(items, true), store.ErrBulkFailed.Error())
+	assert.EqualError(t, items[1].Err, store.ErrRecordNotFound.Error())
+	rn, err = s.Notes().FindByID(n2.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "bulk header", rn.Header)
+
+	items = []*store.BulkItem{
+		{Op: store.BulkUpdate, NoteID: n2.ID, UserID: u.ID, Role: model.RoleEditor, Header: "best effort header"},
+		{Op: store.BulkDelete, NoteID: n1.ID, UserID: u.ID, Role: model.RoleOwner},
+	}
+	assert.NoError(t, s.Notes().Bulk(items, false))
+	assert.NoError(t, items[0].Err)
+	assert.EqualError(t, items[1].Err, store.ErrRecordNotFound.Error())
+	rn, err = s.Notes().FindByID(n2.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "best effort header", rn.Header)
+
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	s.User().Create(other)
+	s.Shares().Save(&model.NoteShare{NoteID: n2.ID, UserID: other.ID, Role: model.RoleViewer})
+	items = []*store.BulkItem{
+		{Op: store.BulkUpdate, NoteID: n2.ID, UserID: other.ID, Role: model.RoleEditor, Header: "viewer header"},
+		{Op: store.BulkTag, NoteID: n1.ID, UserID: other.ID, Role: model.RoleEditor, AddTags: []string{"shared"}},
+	}
+	assert.NoError(t, s.Notes().Bulk(items, false))
+	assert.EqualError(t, items[0].Err, store.ErrForbidden.Error())
+	assert.EqualError(t, items[1].Err, store.ErrRecordNotFound.Error())
+	rn, err = s.Notes().FindByID(n2.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "best effort header", rn.Header)
+}
@@ -0,0 +1,62 @@
+package teststore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// NotificationRepository ...
+type NotificationRepository struct {
+	store         *Store
+	notifications map[int]*model.Notification
+	lastID        int
+}
+
+// Create ...
+func (r *NotificationRepository) Create(n *model.Notification) error {
+	r.lastID++
+	n.ID = r.lastID
+	n.CreatedAt = time.Now()
+	r.notifications[n.ID] = n
+	return nil
+}
+
+// MarkRead ...
+func (r *NotificationRepository) MarkRead(id int, u *model.User) error {
+	n, ok := r.notifications[id]
+	if !ok || n.UserID != u.ID {
+		return store.ErrRecordNotFound
+	}
+
+	if n.ReadAt == nil {
+		now := time.Now()
+		n.ReadAt = &now
+	}
+	return nil
+}
+
+// FindByUser ...
+func (r *NotificationRepository) FindByUser(u *model.User, unread bool) ([]*model.Notification, error) {
+	result := []*model.Notification{}
+	for _, n := range r.notifications {
+		if n.UserID == u.ID && (!unread || n.ReadAt == nil) {
+			result = append(result, n)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID > result[j].ID
+	})
+	return result, nil
+}
+
+func (r *NotificationRepository) deleteByNote(noteID int) {
+	for id, n := range r.notifications {
+		if n.NoteID != nil && *n.NoteID == noteID {
+			delete(r.notifications, id)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package teststore
+
+import (
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// PasswordResetRepository ...
+type PasswordResetRepository struct {
+	store  *Store
+	resets map[int]*model.PasswordReset
+	lastID int
+}
+
+// Create ...
+func (r *PasswordResetRepository) Create(p *model.PasswordReset) error {
+	if err := p.BeforeCreate(); err != nil {
+		return err
+	}
+
+	r.lastID++
+	p.ID = r.lastID
+	p.CreatedAt = time.Now()
+	c := *p
+	c.Token = ""
+	r.resets[p.ID] = &c
+	return nil
+}
+
+// FindByToken ...
+func (r *PasswordResetRepository) FindByToken(hash string) (*model.PasswordReset, error) {
+	now := time.Now()
+	for _, p := range r.resets {
+		if p.TokenHash == hash && p.UsedAt == nil && p.ExpiresAt.After(now) {
+			c := *p
+			return &c, nil
+		}
+	}
+	return nil, store.ErrRecordNotFound
+}
+
+// FindLatest ...
+func (r *PasswordResetRepository) FindLatest(userID int) (*model.PasswordReset, error) {
+	var latest *model.PasswordReset
+	for _, p := range r.resets {
+		if p.UserID == userID && (latest == nil || p.ID > latest.ID) {
+			latest = p
+		}
+	}
+	if latest == nil {
+		return nil, store.ErrRecordNotFound
+	}
+	c := *latest
+	return &c, nil
+}
+
+// Use ...
+func (r *PasswordResetRepository) Use(id int, u *model.User, at time.Time) error {
+	if err := u.ValidatePassword(); err != nil {
+		return err
+	}
+
+	if err := u.BeforeCreate(); err != nil {
+		return err
+	}
+
+	p, ok := r.resets[id]
+	if !ok || p.UserID != u.ID || p.UsedAt != nil {
+		return store.ErrRecordNotFound
+	}
+	stored, ok := r.store.User().(*UserRepository).users[u.ID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	stored.EncryptedPassword = u.EncryptedPassword
+	p.UsedAt = &at
+	return nil
+}
+
+// DeleteByUser ...
+func (r *PasswordResetRepository) DeleteByUser(userID int) error {
+	for id, p := range r.resets {
+		if p.UserID == userID {
+			delete(r.resets, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package teststore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordResetRepository_Create(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	p := &model.PasswordReset{UserID: u.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	assert.NoError(t, s.PasswordResets().Create(p))
+	assert.NotZero(t, p.ID)
+	assert.NotEmpty(t, p.Token)
+
+	fp, err := s.PasswordResets().FindByToken(model.HashToken(p.Token))
+	assert.NoError(t, err)
+	assert.Equal(t, u.ID, fp.UserID)
+
+	expired := &model.PasswordReset{UserID: u.ID, ExpiresAt: time.Now().Add(-time.Minute)}
+	s.PasswordResets().Create(expired)
+	_, err = s.PasswordResets().FindByToken(model.HashToken(expired.Token))
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestPasswordResetRepository_FindLatest(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	_, err := s.PasswordResets().FindLatest(u.ID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	var last *model.PasswordReset
+	for i := 0; i < 2; i++ {
+		last = &model.PasswordReset{UserID: u.ID, ExpiresAt: time.Now().Add(time.Hour)}
+		s.PasswordResets().Create(last)
+	}
+	p, err := s.PasswordResets().FindLatest(u.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, last.ID, p.ID)
+}
+
+func TestPasswordResetRepository_Use(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	p := &model.PasswordReset{UserID: u.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	s.PasswordResets().Create(p)
+
+	// a failed password update doesn't spend the token
+	assert.Error(t, s.PasswordResets().Use(p.ID, &model.User{ID: u.ID, Password: "short"}, time.Now()))
+	assert.EqualError(t, s.PasswordResets().Use(p.ID, &model.User{ID: u.ID + 1, Password: "new password"}, time.Now()), store.ErrRecordNotFound.Error())
+	_, err := s.PasswordResets().FindByToken(model.HashToken(p.Token))
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.PasswordResets().Use(p.ID, &model.User{ID: u.ID, Password: "new password"}, time.Now()))
+	assert.EqualError(t, s.PasswordResets().Use(p.ID, &model.User{ID: u.ID, Password: "another password"}, time.Now()), store.ErrRecordNotFound.Error())
+	_, err = s.PasswordResets().FindByToken(model.HashToken(p.Token))
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	fu, err := s.User().Find(u.ID)
+	assert.NoError(t, err)
+	assert.True(t, fu.ComparePassword("new password"))
+}
+
+func TestPasswordResetRepository_DeleteByUser(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	s.User().Create(other)
+
+	resets := []*model.PasswordReset{}
+	for _, user := range []*model.User{u, u, other} {
+		p := &model.PasswordReset{UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}
+		s.PasswordResets().Create(p)
+		resets = append(resets, p)
+	}
+
+	assert.NoError(t, s.PasswordResets().DeleteByUser(u.ID))
+	for i, p := range resets {
+		_, err := s.PasswordResets().FindByToken(model.HashToken(p.Token))
+		if i < 2 {
+			assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package teststore
+
+import (
+	"sort"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+)
+
+type reference struct {
+	link     *model.WikiLink
+	targetID *int
+}
+
+// ReferenceRepository ...
+type ReferenceRepository struct {
+	store      *Store
+	references map[int][]*reference
+}
+
+// FindBySource ...
+func (r *ReferenceRepository) FindBySource(noteID int) ([]*model.NoteReference, error) {
+	result := []*model.NoteReference{}
+	for _, ref := range r.references[noteID] {
+		result = append(result, r.noteReference(noteID, ref))
+	}
+	return result, nil
+}
+
+// FindByTarget ...
+func (r *ReferenceRepository) FindByTarget(noteID int) ([]*model.NoteReference, error) {
+	r.store.Notes()
+	result := []*model.NoteReference{}
+	for sourceID, refs := range r.references {
+		if r.store.noteRepository.notes[sourceID].DeletedAt != nil {
+			continue
+		}
+		for _, ref := range refs {
+			if ref.targetID != nil && *ref.targetID == noteID {
+				result = append(result, r.noteReference(sourceID, ref))
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].SourceID < result[j].SourceID
+	})
+	return result, nil
+}
+
+func (r *ReferenceRepository) noteReference(sourceID int, ref *reference) *model.NoteReference {
+	notes := r.store.noteRepository.notes
+	nr := &model.NoteReference{
+		SourceID:     sourceID,
+		SourceHeader: notes[sourceID].Header,
+		Link:         ref.link.Link,
+		TargetID:     copyID(ref.targetID),
+	}
+	if ref.targetID != nil {
+		nr.ResolveStatus(notes[*ref.targetID])
+	} else {
+		nr.ResolveStatus(nil)
+	}
+	return nr
+}
+
+// save replaces links of the note and points unresolved links
+// of the author's notes to it when they match its header
+func (r *ReferenceRepository) save(n *model.Note) {
+	notes := r.store.noteRepository.notes
+	refs := []*reference{}
+	for _, l := range model.ParseWikiLinks(n.Body) {
+		ref := &reference{link: l}
+		for _, target := range notes {
+			if target.AuthorID != n.AuthorID || l.NoteID == 0 && target.DeletedAt != nil || !l.Matches(target) {
+				continue
+			}
+			if ref.targetID == nil || target.ID < *ref.targetID {
+				ref.targetID = copyID(&target.ID)
+			}
+		}
+		refs = append(refs, ref)
+	}
+	r.references[n.ID] = refs
+
+	for sourceID, refs := range r.references {
+		if notes[sourceID].AuthorID != n.AuthorID {
+			continue
+		}
+		for _, ref := range refs {
+			if ref.targetID == nil && ref.link.NoteID == 0 && ref.link.Matches(n) {
+				ref.targetID = copyID(&n.ID)
+			}
+		}
+	}
+}
+
+// deleteByNote removes links of the note, links to it become unresolved
+func (r *ReferenceRepository) deleteByNote(noteID int) {
+	delete(r.references, noteID)
+	for _, refs := range r.references {
+		for _, ref := range refs {
+			if ref.targetID != nil && *ref.targetID == noteID {
+				ref.targetID = nil
+			}
+		}
+	}
+}
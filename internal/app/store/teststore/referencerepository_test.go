@@ -0,0 +1,84 @@
+package teststore_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferenceRepository_FindBySource(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	target := model.TestNote(t)
+	target.Header = "Weekly plan"
+	s.Notes().Create(target, u)
+
+	source := model.TestNote(t)
+	source.Body = fmt.Sprintf("See [[weekly plan]], [[Roadmap]] and [[#%d]]", target.ID)
+	s.Notes().Create(source, u)
+
+	refs, err := s.References().FindBySource(source.ID)
+	assert.NoError(t, err)
+	statuses := []string{}
+	for _, ref := range refs {
+		statuses = append(statuses, ref.Link+" "+ref.Status)
+	}
+	assert.Equal(t, []string{
+		"weekly plan ok",
+		"Roadmap broken",
+		fmt.Sprintf("#%d ok", target.ID),
+	}, statuses)
+
+	roadmap := model.TestNote(t)
+	roadmap.Header = "Roadmap"
+	s.Notes().Create(roadmap, u)
+	s.Notes().Update(target.ID, &model.Note{Header: "Daily plan"})
+
+	refs, err = s.References().FindBySource(source.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ReferenceRenamed, refs[0].Status)
+	assert.Equal(t, "Daily plan", refs[0].TargetHeader)
+	assert.Equal(t, model.ReferenceOK, refs[1].Status)
+	assert.Equal(t, roadmap.ID, *refs[1].TargetID)
+	assert.Equal(t, model.ReferenceOK, refs[2].Status)
+
+	s.Notes().Trash(roadmap.ID)
+	s.Notes().Delete(target.ID)
+	refs, err = s.References().FindBySource(source.ID)
+	assert.NoError(t, err)
+	for _, ref := range refs {
+		assert.Equal(t, model.ReferenceBroken, ref.Status)
+	}
+	assert.Nil(t, refs[0].TargetID)
+}
+
+func TestReferenceRepository_FindByTarget(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	s.User().Create(other)
+	target := model.TestNote(t)
+	target.Header = "Weekly plan"
+	s.Notes().Create(target, u)
+
+	var sources []*model.Note
+	for _, author := range []*model.User{u, u, other} {
+		n := model.TestNote(t)
+		n.Body = "[[Weekly plan]]"
+		s.Notes().Create(n, author)
+		sources = append(sources, n)
+	}
+	s.Notes().Trash(sources[1].ID)
+
+	refs, err := s.References().FindByTarget(target.ID)
+	assert.NoError(t, err)
+	assert.Len(t, refs, 1)
+	assert.Equal(t, sources[0].ID, refs[0].SourceID)
+	assert.Equal(t, sources[0].Header, refs[0].SourceHeader)
+}
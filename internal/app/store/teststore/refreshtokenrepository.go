@@ -0,0 +1,71 @@
+package teststore
+
+import (
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// RefreshTokenRepository ...
+type RefreshTokenRepository struct {
+	store  *Store
+	tokens map[int]*model.RefreshToken
+	lastID int
+}
+
+// Create ...
+func (r *RefreshTokenRepository) Create(t *model.RefreshToken) error {
+	if err := t.BeforeCreate(); err != nil {
+		return err
+	}
+
+	r.lastID++
+	t.ID = r.lastID
+	t.CreatedAt = time.Now()
+	c := *t
+	c.Token = ""
+	r.tokens[t.ID] = &c
+	return nil
+}
+
+// FindByToken ...
+func (r *RefreshTokenRepository) FindByToken(hash string) (*model.RefreshToken, error) {
+	for _, t := range r.tokens {
+		if t.TokenHash == hash {
+			c := *t
+			return &c, nil
+		}
+	}
+	return nil, store.ErrRecordNotFound
+}
+
+// Use ...
+func (r *RefreshTokenRepository) Use(id int, at time.Time) error {
+	t, ok := r.tokens[id]
+	if !ok || t.UsedAt != nil || t.RevokedAt != nil {
+		return store.ErrRecordNotFound
+	}
+	t.UsedAt = &at
+	return nil
+}
+
+// RevokeFamily ...
+func (r *RefreshTokenRepository) RevokeFamily(family string, at time.Time) error {
+	for _, t := range r.tokens {
+		if t.Family == family && t.RevokedAt == nil {
+			t.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+// RevokeByUser ...
+func (r *RefreshTokenRepository) RevokeByUser(userID int, at time.Time) error {
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.RevokedAt == nil {
+			t.RevokedAt = &at
+		}
+	}
+	return nil
+}
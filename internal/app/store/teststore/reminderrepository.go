@@ -0,0 +1,137 @@
+package teststore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// ReminderRepository ...
+type ReminderRepository struct {
+	store     *Store
+	reminders map[int]*model.Reminder
+	lastID    int
+}
+
+// Create ...
+func (r *ReminderRepository) Create(rm *model.Reminder) error {
+	if err := rm.Validate(); err != nil {
+		return err
+	}
+
+	r.lastID++
+	rm.ID = r.lastID
+	rm.CreatedAt = time.Now()
+	c := *rm
+	r.reminders[rm.ID] = &c
+	return nil
+}
+
+// Update ...
+func (r *ReminderRepository) Update(rm *model.Reminder) error {
+	if err := rm.Validate(); err != nil {
+		return err
+	}
+	if _, ok := r.reminders[rm.ID]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	c := *rm
+	r.reminders[rm.ID] = &c
+	return nil
+}
+
+// Delete ...
+func (r *ReminderRepository) Delete(id int) error {
+	if _, ok := r.reminders[id]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.reminders, id)
+	return nil
+}
+
+// Find ...
+func (r *ReminderRepository) Find(id int) (*model.Reminder, error) {
+	rm, ok := r.reminders[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	c := *rm
+	return &c, nil
+}
+
+// FindByNote ...
+func (r *ReminderRepository) FindByNote(noteID int, u *model.User) ([]*model.Reminder, error) {
+	return r.find(func(rm *model.Reminder) bool {
+		return rm.NoteID == noteID && rm.UserID == u.ID
+	}), nil
+}
+
+// FindByUser ...
+func (r *ReminderRepository) FindByUser(u *model.User) ([]*model.Reminder, error) {
+	return r.find(func(rm *model.Reminder) bool {
+		return rm.UserID == u.ID && rm.RemindAt != nil
+	}), nil
+}
+
+// FireDue ...
+func (r *ReminderRepository) FireDue(now time.Time, limit int, fn func(*model.Reminder) error) (int, error) {
+	due := r.find(func(rm *model.Reminder) bool {
+		return rm.RemindAt != nil && !rm.RemindAt.After(now)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for _, rm := range due {
+		next := *rm
+		next.LastError = ""
+		next.Advance(now)
+		r.reminders[rm.ID] = &next
+	}
+
+	for _, rm := range due {
+		if err := fn(rm); err != nil {
+			if rr, ok := r.reminders[rm.ID]; ok {
+				rr.LastError = err.Error()
+			}
+		}
+	}
+	return len(due), nil
+}
+
+// find returns copies of matching reminders, upcoming first
+func (r *ReminderRepository) find(match func(*model.Reminder) bool) []*model.Reminder {
+	result := []*model.Reminder{}
+	for _, rm := range r.reminders {
+		if match(rm) {
+			c := *rm
+			result = append(result, &c)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].RemindAt, result[j].RemindAt
+		switch {
+		case a == nil && b == nil:
+		case a == nil || b == nil:
+			return b == nil
+		case !a.Equal(*b):
+			return a.Before(*b)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+func (r *ReminderRepository) deleteByNote(noteID int) {
+	for id, rm := range r.reminders {
+		if rm.NoteID == noteID {
+			delete(r.reminders, id)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package teststore
+
+import (
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// RevisionRepository ...
+type RevisionRepository struct {
+	store     *Store
+	revisions map[int][]*model.NoteRevision
+}
+
+// FindByNote ...
+func (r *RevisionRepository) FindByNote(noteID int) ([]*model.NoteRevision, error) {
+	result := []*model.NoteRevision{}
+	revisions := r.revisions[noteID]
+	for i := len(revisions) - 1; i >= 0; i-- {
+		result = append(result, revisions[i])
+	}
+	return result, nil
+}
+
+// Find ...
+func (r *RevisionRepository) Find(noteID int, revision int) (*model.NoteRevision, error) {
+	for _, rev := range r.revisions[noteID] {
+		if rev.Revision == revision {
+			return rev, nil
+		}
+	}
+	return nil, store.ErrRecordNotFound
+}
+
+// Prune ...
+func (r *RevisionRepository) Prune(noteID int, keep int) error {
+	revisions := r.revisions[noteID]
+	if len(revisions) > keep {
+		r.revisions[noteID] = revisions[len(revisions)-keep:]
+	}
+	return nil
+}
+
+func (r *RevisionRepository) add(n *model.Note) {
+	revisions := r.revisions[n.ID]
+	rev := &model.NoteRevision{
+		NoteID:    n.ID,
+		Revision:  1,
+		Header:    n.Header,
+		Body:      n.Body,
+		CreatedAt: n.UpdatedAt,
+	}
+	if len(revisions) > 0 {
+		rev.Revision = revisions[len(revisions)-1].Revision + 1
+	}
+	r.revisions[n.ID] = append(revisions, rev)
+}
@@ -0,0 +1,162 @@
+package teststore
+
+import (
+	"strings"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+const (
+	headerWeight    = 1.0
+	bodyWeight      = 0.4
+	snippetMaxWords = 35
+)
+
+// searchIndex is an inverted index of note words, body positions go after
+// a gap following header positions so phrases don't cross the boundary
+type searchIndex struct {
+	postings map[string]map[int][]int
+	headers  map[int]int
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		postings: make(map[string]map[int][]int),
+		headers:  make(map[int]int),
+	}
+}
+
+func (i *searchIndex) add(n *model.Note) {
+	i.remove(n.ID)
+
+	header := store.Tokenize(n.Header)
+	i.headers[n.ID] = len(header)
+	for pos, token := range append(append(header, ""), store.Tokenize(n.Body)...) {
+		if token == "" {
+			continue
+		}
+		if i.postings[token] == nil {
+			i.postings[token] = make(map[int][]int)
+		}
+		i.postings[token][n.ID] = append(i.postings[token][n.ID], pos)
+	}
+}
+
+func (i *searchIndex) remove(id int) {
+	for token, docs := range i.postings {
+		delete(docs, id)
+		if len(docs) == 0 {
+			delete(i.postings, token)
+		}
+	}
+	delete(i.headers, id)
+}
+
+// search returns ranks of notes matching the query by note id
+func (i *searchIndex) search(sq *store.SearchQuery) map[int]float64 {
+	var ranks map[int]float64
+	excluded := make(map[int]bool)
+	for _, t := range sq.Terms {
+		starts := i.termStarts(t)
+		if t.Negate {
+			for id := range starts {
+				excluded[id] = true
+			}
+			continue
+		}
+
+		termRanks := make(map[int]float64)
+		for id, positions := range starts {
+			for _, pos := range positions {
+				if pos < i.headers[id] {
+					termRanks[id] += headerWeight
+				} else {
+					termRanks[id] += bodyWeight
+				}
+			}
+		}
+
+		if ranks == nil {
+			ranks = termRanks
+			continue
+		}
+		for id := range ranks {
+			if _, ok := termRanks[id]; !ok {
+				delete(ranks, id)
+				continue
+			}
+			ranks[id] += termRanks[id]
+		}
+	}
+
+	for id := range excluded {
+		delete(ranks, id)
+	}
+	return ranks
+}
+
+// termStarts returns positions of the term first word by note id
+func (i *searchIndex) termStarts(t *store.SearchTerm) map[int][]int {
+	var starts map[int][]int
+	for w := range t.Words {
+		positions := i.wordPositions(t, w)
+		if starts == nil {
+			starts = positions
+			continue
+		}
+
+		next := make(map[int][]int)
+		for id, ss := range starts {
+			for _, s := range ss {
+				for _, pos := range positions[id] {
+					if pos == s+w {
+						next[id] = append(next[id], s)
+						break
+					}
+				}
+			}
+		}
+		starts = next
+	}
+	return starts
+}
+
+func (i *searchIndex) wordPositions(t *store.SearchTerm, w int) map[int][]int {
+	result := make(map[int][]int)
+	for token, docs := range i.postings {
+		if !t.Matches(w, token) {
+			continue
+		}
+		for id, positions := range docs {
+			result[id] = append(result[id], positions...)
+		}
+	}
+	return result
+}
+
+// snippet highlights the fragment of text around the first match
+func snippet(text string, sq *store.SearchQuery) string {
+	words := strings.Fields(text)
+	if len(words) <= snippetMaxWords {
+		return sq.Highlight(text)
+	}
+
+	first := 0
+	for i, w := range words {
+		if strings.Contains(sq.Highlight(w), store.HighlightStart) {
+			first = i
+			break
+		}
+	}
+
+	start := first - snippetMaxWords/3
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetMaxWords
+	if end > len(words) {
+		end = len(words)
+	}
+	return sq.Highlight(strings.Join(words[start:end], " "))
+}
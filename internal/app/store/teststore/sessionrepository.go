@@ -0,0 +1,106 @@
+package teststore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// SessionRepository ...
+type SessionRepository struct {
+	store    *Store
+	sessions map[int]*model.Session
+	lastID   int
+}
+
+// Create ...
+func (r *SessionRepository) Create(s *model.Session) error {
+	if err := s.BeforeCreate(); err != nil {
+		return err
+	}
+
+	r.lastID++
+	s.ID = r.lastID
+	s.CreatedAt = time.Now()
+	s.LastSeenAt = s.CreatedAt
+	c := *s
+	c.Token = ""
+	r.sessions[s.ID] = &c
+	return nil
+}
+
+// Touch ...
+func (r *SessionRepository) Touch(id int, ip string, at time.Time) error {
+	s, ok := r.sessions[id]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	s.IP = ip
+	s.LastSeenAt = at
+	return nil
+}
+
+// Delete ...
+func (r *SessionRepository) Delete(id int) error {
+	if _, ok := r.sessions[id]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.sessions, id)
+	return nil
+}
+
+// DeleteByUser ...
+func (r *SessionRepository) DeleteByUser(userID int, except int) (int, error) {
+	count := 0
+	for id, s := range r.sessions {
+		if s.UserID == userID && id != except {
+			delete(r.sessions, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Find ...
+func (r *SessionRepository) Find(id int) (*model.Session, error) {
+	s, ok := r.sessions[id]
+	if !ok || !s.ExpiresAt.After(time.Now()) {
+		return nil, store.ErrRecordNotFound
+	}
+
+	c := *s
+	return &c, nil
+}
+
+// FindByToken ...
+func (r *SessionRepository) FindByToken(hash string) (*model.Session, error) {
+	for id, s := range r.sessions {
+		if s.TokenHash == hash {
+			return r.Find(id)
+		}
+	}
+	return nil, store.ErrRecordNotFound
+}
+
+// FindByUser ...
+func (r *SessionRepository) FindByUser(u *model.User) ([]*model.Session, error) {
+	result := []*model.Session{}
+	for _, s := range r.sessions {
+		if s.UserID == u.ID && s.ExpiresAt.After(time.Now()) {
+			c := *s
+			result = append(result, &c)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].LastSeenAt.Equal(result[j].LastSeenAt) {
+			return result[i].LastSeenAt.After(result[j].LastSeenAt)
+		}
+		return result[i].ID > result[j].ID
+	})
+	return result, nil
+}
@@ -0,0 +1,106 @@
+package teststore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// ShareRepository ...
+type ShareRepository struct {
+	store  *Store
+	shares map[int]map[int]*model.NoteShare
+}
+
+// Save ...
+func (r *ShareRepository) Save(s *model.NoteShare) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	if r.shares[s.NoteID] == nil {
+		r.shares[s.NoteID] = make(map[int]*model.NoteShare)
+	}
+	if existing, ok := r.shares[s.NoteID][s.UserID]; ok {
+		s.CreatedAt = existing.CreatedAt
+	} else {
+		s.CreatedAt = time.Now()
+	}
+	r.shares[s.NoteID][s.UserID] = &model.NoteShare{
+		NoteID:    s.NoteID,
+		UserID:    s.UserID,
+		Role:      s.Role,
+		CreatedAt: s.CreatedAt,
+	}
+	return nil
+}
+
+// Delete ...
+func (r *ShareRepository) Delete(noteID int, userID int) error {
+	if _, ok := r.shares[noteID][userID]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.shares[noteID], userID)
+	return nil
+}
+
+// Find ...
+func (r *ShareRepository) Find(noteID int, userID int) (*model.NoteShare, error) {
+	s, ok := r.shares[noteID][userID]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	return r.withEmail(s), nil
+}
+
+// FindByNote ...
+func (r *ShareRepository) FindByNote(noteID int) ([]*model.NoteShare, error) {
+	result := []*model.NoteShare{}
+	for _, s := range r.shares[noteID] {
+		result = append(result, r.withEmail(s))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Email < result[j].Email
+	})
+	return result, nil
+}
+
+// FindByUser ...
+func (r *ShareRepository) FindByUser(u *model.User) ([]*model.NoteShare, error) {
+	result := []*model.NoteShare{}
+	for noteID, shares := range r.shares {
+		s, ok := shares[u.ID]
+		if !ok {
+			continue
+		}
+		n, err := r.store.Notes().FindByID(noteID)
+		if err != nil {
+			continue
+		}
+
+		s = r.withEmail(s)
+		s.Note = n
+		result = append(result, s)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].NoteID > result[j].NoteID
+	})
+	return result, nil
+}
+
+func (r *ShareRepository) withEmail(s *model.NoteShare) *model.NoteShare {
+	res := *s
+	if u, err := r.store.User().Find(s.UserID); err == nil {
+		res.Email = u.Email
+	}
+	return &res
+}
@@ -7,8 +7,26 @@ import (
 
 // Store ...
 type Store struct {
-	userRepository *UserRepository
-	noteRepository *NoteRepository
+	userRepository              *UserRepository
+	noteRepository              *NoteRepository
+	tagRepository               *TagRepository
+	revisionRepository          *RevisionRepository
+	shareRepository             *ShareRepository
+	linkRepository              *LinkRepository
+	notebookRepository          *NotebookRepository
+	attachmentRepository        *AttachmentRepository
+	templateRepository          *TemplateRepository
+	referenceRepository         *ReferenceRepository
+	reminderRepository          *ReminderRepository
+	notificationRepository      *NotificationRepository
+	checklistRepository         *ChecklistRepository
+	commentRepository           *CommentRepository
+	sessionRepository           *SessionRepository
+	refreshTokenRepository      *RefreshTokenRepository
+	apiTokenRepository          *APITokenRepository
+	passwordResetRepository     *PasswordResetRepository
+	emailVerificationRepository *EmailVerificationRepository
+	importJobRepository         *ImportJobRepository
 }
 
 // New ...
@@ -39,7 +57,260 @@ func (s *Store) Notes() store.NoteRepository {
 	s.noteRepository = &NoteRepository{
 		store: s,
 		notes: make(map[int]*model.Note),
+		index: newSearchIndex(),
 	}
 
 	return s.noteRepository
 }
+
+// Tags ...
+func (s *Store) Tags() store.TagRepository {
+	if s.tagRepository != nil {
+		return s.tagRepository
+	}
+
+	s.tagRepository = &TagRepository{
+		store: s,
+		ids:   make(map[int]map[string]int),
+	}
+
+	return s.tagRepository
+}
+
+// Revisions ...
+func (s *Store) Revisions() store.RevisionRepository {
+	if s.revisionRepository != nil {
+		return s.revisionRepository
+	}
+
+	s.revisionRepository = &RevisionRepository{
+		store:     s,
+		revisions: make(map[int][]*model.NoteRevision),
+	}
+
+	return s.revisionRepository
+}
+
+// Shares ...
+func (s *Store) Shares() store.ShareRepository {
+	if s.shareRepository != nil {
+		return s.shareRepository
+	}
+
+	s.shareRepository = &ShareRepository{
+		store:  s,
+		shares: make(map[int]map[int]*model.NoteShare),
+	}
+
+	return s.shareRepository
+}
+
+// Links ...
+func (s *Store) Links() store.LinkRepository {
+	if s.linkRepository != nil {
+		return s.linkRepository
+	}
+
+	s.linkRepository = &LinkRepository{
+		store: s,
+		links: make(map[string]*model.NoteLink),
+	}
+
+	return s.linkRepository
+}
+
+// Notebooks ...
+func (s *Store) Notebooks() store.NotebookRepository {
+	if s.notebookRepository != nil {
+		return s.notebookRepository
+	}
+
+	s.notebookRepository = &NotebookRepository{
+		store:     s,
+		notebooks: make(map[int]*model.Notebook),
+	}
+
+	return s.notebookRepository
+}
+
+// Attachments ...
+func (s *Store) Attachments() store.AttachmentRepository {
+	if s.attachmentRepository != nil {
+		return s.attachmentRepository
+	}
+
+	s.attachmentRepository = &AttachmentRepository{
+		store:       s,
+		attachments: make(map[int]*model.Attachment),
+	}
+
+	return s.attachmentRepository
+}
+
+// Templates ...
+func (s *Store) Templates() store.TemplateRepository {
+	if s.templateRepository != nil {
+		return s.templateRepository
+	}
+
+	s.templateRepository = &TemplateRepository{
+		store:     s,
+		templates: make(map[int]*model.Template),
+	}
+
+	return s.templateRepository
+}
+
+// References ...
+func (s *Store) References() store.ReferenceRepository {
+	if s.referenceRepository != nil {
+		return s.referenceRepository
+	}
+
+	s.referenceRepository = &ReferenceRepository{
+		store:      s,
+		references: make(map[int][]*reference),
+	}
+
+	return s.referenceRepository
+}
+
+// Reminders ...
+func (s *Store) Reminders() store.ReminderRepository {
+	if s.reminderRepository != nil {
+		return s.reminderRepository
+	}
+
+	s.reminderRepository = &ReminderRepository{
+		store:     s,
+		reminders: make(map[int]*model.Reminder),
+	}
+
+	return s.reminderRepository
+}
+
+// Notifications ...
+func (s *Store) Notifications() store.NotificationRepository {
+	if s.notificationRepository != nil {
+		return s.notificationRepository
+	}
+
+	s.notificationRepository = &NotificationRepository{
+		store:         s,
+		notifications: make(map[int]*model.Notification),
+	}
+
+	return s.notificationRepository
+}
+
+// Checklist ...
+func (s *Store) Checklist() store.ChecklistRepository {
+	if s.checklistRepository != nil {
+		return s.checklistRepository
+	}
+
+	s.checklistRepository = &ChecklistRepository{
+		store: s,
+		items: make(map[int]*model.ChecklistItem),
+	}
+
+	return s.checklistRepository
+}
+
+// Comments ...
+func (s *Store) Comments() store.CommentRepository {
+	if s.commentRepository != nil {
+		return s.commentRepository
+	}
+
+	s.commentRepository = &CommentRepository{
+		store:    s,
+		comments: make(map[int]*model.Comment),
+	}
+
+	return s.commentRepository
+}
+
+// Sessions ...
+func (s *Store) Sessions() store.SessionRepository {
+	if s.sessionRepository != nil {
+		return s.sessionRepository
+	}
+
+	s.sessionRepository = &SessionRepository{
+		store:    s,
+		sessions: make(map[int]*model.Session),
+	}
+
+	return s.sessionRepository
+}
+
+// RefreshTokens ...
+func (s *Store) RefreshTokens() store.RefreshTokenRepository {
+	if s.refreshTokenRepository != nil {
+		return s.refreshTokenRepository
+	}
+
+	s.refreshTokenRepository = &RefreshTokenRepository{
+		store:  s,
+		tokens: make(map[int]*model.RefreshToken),
+	}
+
+	return s.refreshTokenRepository
+}
+
+// APITokens ...
+func (s *Store) APITokens() store.APITokenRepository {
+	if s.apiTokenRepository != nil {
+		return s.apiTokenRepository
+	}
+
+	s.apiTokenRepository = &APITokenRepository{
+		store:  s,
+		tokens: make(map[int]*model.APIToken),
+	}
+
+	return s.apiTokenRepository
+}
+
+// PasswordResets ...
+func (s *Store) PasswordResets() store.PasswordResetRepository {
+	if s.passwordResetRepository != nil {
+		return s.passwordResetRepository
+	}
+
+	s.passwordResetRepository = &PasswordResetRepository{
+		store:  s,
+		resets: make(map[int]*model.PasswordReset),
+	}
+
+	return s.passwordResetRepository
+}
+
+// EmailVerifications ...
+func (s *Store) EmailVerifications() store.EmailVerificationRepository {
+	if s.emailVerificationRepository != nil {
+		return s.emailVerificationRepository
+	}
+
+	s.emailVerificationRepository = &EmailVerificationRepository{
+		store:         s,
+		verifications: make(map[int]*model.EmailVerification),
+	}
+
+	return s.emailVerificationRepository
+}
+
+// ImportJobs ...
+func (s *Store) ImportJobs() store.ImportJobRepository {
+	if s.importJobRepository != nil {
+		return s.importJobRepository
+	}
+
+	s.importJobRepository = &ImportJobRepository{
+		store: s,
+		jobs:  make(map[string]*model.ImportJob),
+	}
+
+	return s.importJobRepository
+}
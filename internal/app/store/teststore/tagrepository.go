@@ -0,0 +1,82 @@
+package teststore
+
+import (
+	"sort"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// TagRepository ...
+type TagRepository struct {
+	store *Store
+	ids   map[int]map[string]int
+}
+
+// SetNoteTags ...
+func (r *TagRepository) SetNoteTags(n *model.Note, names []string) error {
+	names = model.NormalizeTags(names)
+	for _, name := range names {
+		t := &model.Tag{Name: name}
+		if err := t.Validate(); err != nil {
+			return err
+		}
+	}
+
+	r.store.Notes()
+	stored, ok := r.store.noteRepository.notes[n.ID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	n.Tags = nil
+	if len(names) > 0 {
+		n.Tags = names
+	}
+	stored.Tags = n.Tags
+	return nil
+}
+
+// FindByUser ...
+func (r *TagRepository) FindByUser(u *model.User) ([]*model.Tag, error) {
+	r.store.Notes()
+	tags := make(map[string]*model.Tag)
+	for _, n := range r.store.noteRepository.notes {
+		if n.AuthorID != u.ID || n.DeletedAt != nil {
+			continue
+		}
+		for _, name := range n.Tags {
+			t, ok := tags[name]
+			if !ok {
+				t = &model.Tag{
+					ID:     r.tagID(u.ID, name),
+					UserID: u.ID,
+					Name:   name,
+				}
+				tags[name] = t
+			}
+			t.NoteCount++
+		}
+	}
+
+	result := []*model.Tag{}
+	for _, t := range tags {
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+func (r *TagRepository) tagID(userID int, name string) int {
+	if r.ids[userID] == nil {
+		r.ids[userID] = make(map[string]int)
+	}
+	id, ok := r.ids[userID][name]
+	if !ok {
+		id = len(r.ids[userID]) + 1
+		r.ids[userID][name] = id
+	}
+	return id
+}
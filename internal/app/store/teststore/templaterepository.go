@@ -0,0 +1,83 @@
+package teststore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+)
+
+// TemplateRepository ...
+type TemplateRepository struct {
+	store     *Store
+	templates map[int]*model.Template
+	lastID    int
+}
+
+// Create ...
+func (r *TemplateRepository) Create(t *model.Template) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	r.lastID++
+	t.ID = r.lastID
+	t.CreatedAt = time.Now()
+	t.UpdatedAt = t.CreatedAt
+	r.templates[t.ID] = t
+	return nil
+}
+
+// Update ...
+func (r *TemplateRepository) Update(t *model.Template) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	if _, ok := r.templates[t.ID]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	t.UpdatedAt = time.Now()
+	c := *t
+	r.templates[t.ID] = &c
+	return nil
+}
+
+// Delete ...
+func (r *TemplateRepository) Delete(id int) error {
+	if _, ok := r.templates[id]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.templates, id)
+	return nil
+}
+
+// Find ...
+func (r *TemplateRepository) Find(id int) (*model.Template, error) {
+	t, ok := r.templates[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	return t, nil
+}
+
+// FindByUser ...
+func (r *TemplateRepository) FindByUser(u *model.User) ([]*model.Template, error) {
+	result := []*model.Template{}
+	for _, t := range r.templates {
+		if t.UserID == u.ID {
+			result = append(result, t)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
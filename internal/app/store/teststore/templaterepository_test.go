@@ -0,0 +1,88 @@
+package teststore_test
+
+import (
+	"testing"
+
+	"github.com/KapitanD/http-api-server/internal/app/model"
+	"github.com/KapitanD/http-api-server/internal/app/store"
+	"github.com/KapitanD/http-api-server/internal/app/store/teststore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateRepository_Create(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+
+	tmpl := model.TestTemplate(t)
+	tmpl.UserID = u.ID
+	assert.NoError(t, s.Templates().Create(tmpl))
+	assert.NotZero(t, tmpl.ID)
+
+	rt, err := s.Templates().Find(tmpl.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, tmpl.Body, rt.Body)
+
+	assert.Error(t, s.Templates().Create(&model.Template{UserID: u.ID}))
+}
+
+func TestTemplateRepository_Update(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	tmpl := model.TestTemplate(t)
+	tmpl.UserID = u.ID
+	s.Templates().Create(tmpl)
+
+	ut := *tmpl
+	ut.Name = "retro"
+	assert.NoError(t, s.Templates().Update(&ut))
+	ut.Body = "{{date"
+	assert.Error(t, s.Templates().Update(&ut))
+	ut.ID++
+	ut.Body = "body"
+	assert.EqualError(t, s.Templates().Update(&ut), store.ErrRecordNotFound.Error())
+
+	rt, err := s.Templates().Find(tmpl.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "retro", rt.Name)
+	assert.Equal(t, tmpl.Body, rt.Body)
+}
+
+func TestTemplateRepository_Delete(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	tmpl := model.TestTemplate(t)
+	tmpl.UserID = u.ID
+	s.Templates().Create(tmpl)
+
+	assert.NoError(t, s.Templates().Delete(tmpl.ID))
+	assert.EqualError(t, s.Templates().Delete(tmpl.ID), store.ErrRecordNotFound.Error())
+	_, err := s.Templates().Find(tmpl.ID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestTemplateRepository_FindByUser(t *testing.T) {
+	s := teststore.New()
+	u := model.TestUser(t)
+	s.User().Create(u)
+	other := model.TestUser(t)
+	other.Email = "other@example.org"
+	s.User().Create(other)
+
+	for _, name := range []string{"standup", "retro"} {
+		tmpl := model.TestTemplate(t)
+		tmpl.UserID = u.ID
+		tmpl.Name = name
+		s.Templates().Create(tmpl)
+	}
+	tmpl := model.TestTemplate(t)
+	tmpl.UserID = other.ID
+	s.Templates().Create(tmpl)
+
+	tl, err := s.Templates().FindByUser(u)
+	assert.NoError(t, err)
+	assert.Len(t, tl, 2)
+	assert.Equal(t, "retro", tl[0].Name)
+}
@@ -1,6 +1,8 @@
 package teststore
 
 import (
+	"time"
+
 	"github.com/KapitanD/http-api-server/internal/app/model"
 	"github.com/KapitanD/http-api-server/internal/app/store"
 )
@@ -47,3 +49,33 @@ func (r *UserRepository) Find(id int) (*model.User, error) {
 
 	return u, nil
 }
+
+// UpdatePassword ...
+func (r *UserRepository) UpdatePassword(u *model.User) error {
+	if err := u.ValidatePassword(); err != nil {
+		return err
+	}
+
+	if err := u.BeforeCreate(); err != nil {
+		return err
+	}
+
+	stored, ok := r.users[u.ID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	stored.EncryptedPassword = u.EncryptedPassword
+
+	return nil
+}
+
+// MarkVerified ...
+func (r *UserRepository) MarkVerified(id int, at time.Time) error {
+	u, ok := r.users[id]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	u.EmailVerifiedAt = &at
+
+	return nil
+}
@@ -0,0 +1,12 @@
+CREATE TABLE tags (
+    id bigserial not null primary key,
+    user_id bigint not null REFERENCES users (id) ON DELETE CASCADE,
+    name varchar not null,
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE note_tags (
+    note_id bigint not null REFERENCES notes (id) ON DELETE CASCADE,
+    tag_id bigint not null REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (note_id, tag_id)
+);
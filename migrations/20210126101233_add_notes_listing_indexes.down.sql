@@ -0,0 +1,3 @@
+DROP INDEX notes_author_header_idx;
+DROP INDEX notes_author_created_at_idx;
+DROP INDEX notes_author_updated_at_idx;
@@ -0,0 +1,3 @@
+CREATE INDEX notes_author_updated_at_idx ON notes (author_id, updated_at, id);
+CREATE INDEX notes_author_created_at_idx ON notes (author_id, created_at, id);
+CREATE INDEX notes_author_header_idx ON notes (author_id, header, id);
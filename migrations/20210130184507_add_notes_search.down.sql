@@ -0,0 +1,4 @@
+DROP INDEX notes_search_idx;
+DROP TRIGGER notes_search_update ON notes;
+DROP FUNCTION notes_search_update();
+ALTER TABLE notes DROP COLUMN search;
@@ -0,0 +1,15 @@
+ALTER TABLE notes ADD COLUMN search tsvector;
+
+CREATE FUNCTION notes_search_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search := setweight(to_tsvector('simple', NEW.header), 'A') || setweight(to_tsvector('simple', NEW.body), 'B');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notes_search_update BEFORE INSERT OR UPDATE OF header, body ON notes
+    FOR EACH ROW EXECUTE PROCEDURE notes_search_update();
+
+UPDATE notes SET search = setweight(to_tsvector('simple', header), 'A') || setweight(to_tsvector('simple', body), 'B');
+
+CREATE INDEX notes_search_idx ON notes USING GIN (search);
@@ -0,0 +1 @@
+DROP TABLE note_revisions;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE note_revisions (
+    id bigserial not null primary key,
+    note_id bigint not null REFERENCES notes (id) ON DELETE CASCADE,
+    revision int not null,
+    header varchar not null,
+    body text not null,
+    created_at timestamp default current_timestamp,
+    UNIQUE (note_id, revision)
+);
+
+INSERT INTO note_revisions (note_id, revision, header, body, created_at)
+SELECT id, 1, header, body, updated_at FROM notes;
@@ -0,0 +1 @@
+ALTER TABLE notes ADD COLUMN version int not null default 1;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE note_shares;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE note_shares (
+    note_id bigint not null REFERENCES notes (id) ON DELETE CASCADE,
+    user_id bigint not null REFERENCES users (id) ON DELETE CASCADE,
+    role varchar not null CHECK (role IN ('viewer', 'editor')),
+    created_at timestamp default current_timestamp,
+    PRIMARY KEY (note_id, user_id)
+);
+
+CREATE INDEX note_shares_user_id_idx ON note_shares (user_id);
@@ -0,0 +1 @@
+DROP TABLE note_links;
\ No newline at end of file
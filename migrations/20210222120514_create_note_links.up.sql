@@ -0,0 +1,10 @@
+CREATE TABLE note_links (
+    token varchar primary key,
+    note_id bigint not null REFERENCES notes (id) ON DELETE CASCADE,
+    encrypted_password varchar not null default '',
+    expires_at timestamp,
+    views bigint not null default 0,
+    created_at timestamp default current_timestamp
+);
+
+CREATE INDEX note_links_note_id_idx ON note_links (note_id);
@@ -0,0 +1,3 @@
+ALTER TABLE notes DROP COLUMN notebook_id;
+
+DROP TABLE notebooks;
@@ -0,0 +1,15 @@
+CREATE TABLE notebooks (
+    id bigserial not null primary key,
+    user_id bigint not null REFERENCES users (id) ON DELETE CASCADE,
+    parent_id bigint REFERENCES notebooks (id) ON DELETE CASCADE,
+    name varchar not null,
+    created_at timestamp default current_timestamp,
+    updated_at timestamp default current_timestamp
+);
+
+CREATE INDEX notebooks_user_id_idx ON notebooks (user_id);
+CREATE INDEX notebooks_parent_id_idx ON notebooks (parent_id);
+
+ALTER TABLE notes ADD COLUMN notebook_id bigint REFERENCES notebooks (id) ON DELETE SET NULL;
+
+CREATE INDEX notes_notebook_id_idx ON notes (notebook_id);
@@ -0,0 +1,4 @@
+ALTER TABLE notes
+    DROP COLUMN pinned,
+    DROP COLUMN archived,
+    DROP COLUMN starred;
@@ -0,0 +1,6 @@
+ALTER TABLE notes
+    ADD COLUMN pinned boolean not null default false,
+    ADD COLUMN archived boolean not null default false,
+    ADD COLUMN starred boolean not null default false;
+
+CREATE INDEX notes_author_id_pinned_updated_at_idx ON notes (author_id, pinned, updated_at) WHERE deleted_at IS NULL AND NOT archived;
@@ -0,0 +1 @@
+DROP TABLE note_attachments;
\ No newline at end of file
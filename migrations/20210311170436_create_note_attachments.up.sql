@@ -0,0 +1,11 @@
+CREATE TABLE note_attachments (
+    id bigserial not null primary key,
+    note_id bigint not null REFERENCES notes (id) ON DELETE CASCADE,
+    filename varchar not null,
+    content_type varchar not null,
+    size bigint not null,
+    storage_key varchar not null unique,
+    created_at timestamp default current_timestamp
+);
+
+CREATE INDEX note_attachments_note_id_idx ON note_attachments (note_id);
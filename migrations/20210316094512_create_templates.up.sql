@@ -0,0 +1,11 @@
+CREATE TABLE templates (
+    id bigserial not null primary key,
+    user_id bigint not null REFERENCES users (id) ON DELETE CASCADE,
+    name varchar not null,
+    header text not null,
+    body text not null,
+    created_at timestamp default current_timestamp,
+    updated_at timestamp default current_timestamp
+);
+
+CREATE INDEX templates_user_id_idx ON templates (user_id);
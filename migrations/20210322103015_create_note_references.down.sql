@@ -0,0 +1 @@
+DROP TABLE note_references;
\ No newline at end of file
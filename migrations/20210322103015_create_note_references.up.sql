@@ -0,0 +1,9 @@
+CREATE TABLE note_references (
+    source_id bigint not null REFERENCES notes (id) ON DELETE CASCADE,
+    link varchar not null,
+    position int not null,
+    target_id bigint REFERENCES notes (id) ON DELETE SET NULL,
+    PRIMARY KEY (source_id, link)
+);
+
+CREATE INDEX note_references_target_id_idx ON note_references (target_id);
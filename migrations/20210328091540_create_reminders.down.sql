@@ -0,0 +1,2 @@
+DROP TABLE notifications;
+DROP TABLE reminders;
\ No newline at end of file
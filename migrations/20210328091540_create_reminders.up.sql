@@ -0,0 +1,29 @@
+CREATE TABLE reminders (
+    id bigserial not null primary key,
+    note_id bigint not null REFERENCES notes (id) ON DELETE CASCADE,
+    user_id bigint not null REFERENCES users (id) ON DELETE CASCADE,
+    remind_at timestamp,
+    recurrence varchar not null default '',
+    channel varchar not null,
+    target varchar not null default '',
+    occurrence integer not null default 1,
+    fired integer not null default 0,
+    last_fired_at timestamp,
+    last_error varchar not null default '',
+    created_at timestamp default current_timestamp
+);
+
+CREATE INDEX reminders_remind_at_idx ON reminders (remind_at) WHERE remind_at IS NOT NULL;
+CREATE INDEX reminders_note_id_idx ON reminders (note_id, user_id);
+
+CREATE TABLE notifications (
+    id bigserial not null primary key,
+    user_id bigint not null REFERENCES users (id) ON DELETE CASCADE,
+    note_id bigint REFERENCES notes (id) ON DELETE CASCADE,
+    kind varchar not null,
+    message text not null,
+    created_at timestamp default current_timestamp,
+    read_at timestamp
+);
+
+CREATE INDEX notifications_user_id_idx ON notifications (user_id, id);
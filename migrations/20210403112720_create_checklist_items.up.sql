@@ -0,0 +1,10 @@
+CREATE TABLE checklist_items (
+    id bigserial not null primary key,
+    note_id bigint not null REFERENCES notes (id) ON DELETE CASCADE,
+    text varchar not null,
+    done boolean not null default false,
+    position integer not null,
+    created_at timestamp default current_timestamp,
+    updated_at timestamp default current_timestamp,
+    UNIQUE (note_id, position) DEFERRABLE INITIALLY DEFERRED
+);
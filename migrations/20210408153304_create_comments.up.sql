@@ -0,0 +1,11 @@
+CREATE TABLE comments (
+    id bigserial not null primary key,
+    note_id bigint not null REFERENCES notes (id) ON DELETE CASCADE,
+    author_id bigint not null REFERENCES users (id) ON DELETE CASCADE,
+    parent_id bigint REFERENCES comments (id) ON DELETE CASCADE,
+    body text not null,
+    created_at timestamp default current_timestamp,
+    updated_at timestamp default current_timestamp
+);
+
+CREATE INDEX comments_note_id_idx ON comments (note_id);
@@ -0,0 +1,12 @@
+CREATE TABLE sessions (
+    id bigserial not null primary key,
+    user_id bigint not null REFERENCES users (id) ON DELETE CASCADE,
+    token_hash varchar not null unique,
+    device varchar not null,
+    ip varchar not null,
+    created_at timestamp default current_timestamp,
+    last_seen_at timestamp default current_timestamp,
+    expires_at timestamp not null
+);
+
+CREATE INDEX sessions_user_id_idx ON sessions (user_id);
@@ -0,0 +1,13 @@
+CREATE TABLE refresh_tokens (
+    id bigserial not null primary key,
+    user_id bigint not null REFERENCES users (id) ON DELETE CASCADE,
+    family varchar not null,
+    token_hash varchar not null unique,
+    created_at timestamp default current_timestamp,
+    expires_at timestamp not null,
+    used_at timestamp,
+    revoked_at timestamp
+);
+
+CREATE INDEX refresh_tokens_user_id_idx ON refresh_tokens (user_id);
+CREATE INDEX refresh_tokens_family_idx ON refresh_tokens (family);
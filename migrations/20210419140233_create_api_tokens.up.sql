@@ -0,0 +1,12 @@
+CREATE TABLE api_tokens (
+    id bigserial not null primary key,
+    user_id bigint not null REFERENCES users (id) ON DELETE CASCADE,
+    name varchar not null,
+    token_hash varchar not null unique,
+    scopes varchar[] not null,
+    expires_at timestamp,
+    last_used_at timestamp,
+    created_at timestamp default current_timestamp
+);
+
+CREATE INDEX api_tokens_user_id_idx ON api_tokens (user_id);
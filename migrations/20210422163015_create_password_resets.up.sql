@@ -0,0 +1,10 @@
+CREATE TABLE password_resets (
+    id bigserial not null primary key,
+    user_id bigint not null REFERENCES users (id) ON DELETE CASCADE,
+    token_hash varchar not null unique,
+    created_at timestamp default current_timestamp,
+    expires_at timestamp not null,
+    used_at timestamp
+);
+
+CREATE INDEX password_resets_user_id_idx ON password_resets (user_id);
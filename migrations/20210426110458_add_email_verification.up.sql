@@ -0,0 +1,13 @@
+ALTER TABLE users ADD COLUMN email_verified_at timestamp;
+
+UPDATE users SET email_verified_at = current_timestamp;
+
+CREATE TABLE email_verifications (
+    id bigserial not null primary key,
+    user_id bigint not null REFERENCES users (id) ON DELETE CASCADE,
+    token_hash varchar not null unique,
+    created_at timestamp default current_timestamp,
+    expires_at timestamp not null
+);
+
+CREATE INDEX email_verifications_user_id_idx ON email_verifications (user_id);
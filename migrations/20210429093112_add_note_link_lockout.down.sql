@@ -0,0 +1,2 @@
+ALTER TABLE note_links DROP COLUMN locked_until;
+ALTER TABLE note_links DROP COLUMN failed_attempts;
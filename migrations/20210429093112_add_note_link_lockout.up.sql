@@ -0,0 +1,2 @@
+ALTER TABLE note_links ADD COLUMN failed_attempts integer not null default 0;
+ALTER TABLE note_links ADD COLUMN locked_until timestamp;
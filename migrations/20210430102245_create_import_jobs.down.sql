@@ -0,0 +1,2 @@
+DROP TABLE import_job_files;
+DROP TABLE import_jobs;
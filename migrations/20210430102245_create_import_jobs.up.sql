@@ -0,0 +1,24 @@
+CREATE TABLE import_jobs (
+    id varchar primary key,
+    user_id bigint not null REFERENCES users (id) ON DELETE CASCADE,
+    format varchar not null,
+    status varchar not null,
+    imported integer not null default 0,
+    failed integer not null default 0,
+    error varchar not null default '',
+    created_at timestamp default current_timestamp,
+    finished_at timestamp
+);
+
+CREATE INDEX import_jobs_finished_at_idx ON import_jobs (finished_at);
+
+CREATE TABLE import_job_files (
+    id bigserial not null primary key,
+    job_id varchar not null REFERENCES import_jobs (id) ON DELETE CASCADE,
+    name varchar not null,
+    status varchar not null,
+    note_id bigint,
+    error varchar not null default ''
+);
+
+CREATE INDEX import_job_files_job_id_idx ON import_job_files (job_id);
@@ -0,0 +1,2 @@
+DROP INDEX import_jobs_running_idx;
+ALTER TABLE import_jobs DROP COLUMN heartbeat_at;
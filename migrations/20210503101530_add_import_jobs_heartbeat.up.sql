@@ -0,0 +1,3 @@
+ALTER TABLE import_jobs ADD COLUMN heartbeat_at timestamp not null default current_timestamp;
+
+CREATE INDEX import_jobs_running_idx ON import_jobs (heartbeat_at) WHERE status = 'running';